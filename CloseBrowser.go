@@ -2,6 +2,9 @@ package devbrowser
 
 import (
 	"errors"
+	"time"
+
+	"github.com/tinywasm/devbrowser/cdproto/network"
 )
 
 func (h *DevBrowser) CloseBrowser() error {
@@ -16,6 +19,16 @@ func (h *DevBrowser) CloseBrowser() error {
 	h.ready = false
 	h.pendingReload = false
 
+	// Stop a running screencast while its target is still there; the frames
+	// of a closed browser are not collected.
+	h.RecordMutex.Lock()
+	rec := h.recording
+	h.recording = nil
+	h.RecordMutex.Unlock()
+	if rec != nil {
+		h.haltRecording(rec, "browser closed")
+	}
+
 	if h.Cancel != nil {
 		h.Cancel()
 	}
@@ -31,6 +44,22 @@ func (h *DevBrowser) CloseBrowser() error {
 	h.Ctx = nil
 	h.Cancel = nil
 	h.AllocCancel = nil
+	h.refs.retire()
+	h.lastStructure = structureSnapshot{}
+	h.checkpoints = nil
+	h.checkpointTimes = nil
+	h.lastMarks = nil
+	h.lastMarksURL = ""
+	h.DialogMutex.Lock()
+	h.Dialogs = nil
+	h.DialogMutex.Unlock()
+	h.NetworkMutex.Lock()
+	h.inflight = make(map[network.RequestID]string)
+	h.networkActivity = time.Time{}
+	h.NetworkMutex.Unlock()
+	h.mouseX, h.mouseY = 0, 0
+	h.frameTargets = nil
+	h.frameOrder = nil
 
 	h.Logger(h.StatusMessage())
 	h.UI.RefreshUI()
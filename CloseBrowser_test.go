@@ -0,0 +1,28 @@
+package devbrowser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tinywasm/devbrowser/cdproto/network"
+)
+
+type closeTestUI struct{}
+
+func (closeTestUI) RefreshUI()         {}
+func (closeTestUI) ReturnFocus() error { return nil }
+
+// TestCloseBrowserResetsNetworkIdle guards that requests left in flight by a
+// closed browser do not keep the network busy for the next session's waits.
+func TestCloseBrowserResetsNetworkIdle(t *testing.T) {
+	b := &DevBrowser{UI: closeTestUI{}, IsOpenFlag: true}
+	b.inflight = map[network.RequestID]string{"1": "http://localhost/slow"}
+	b.networkActivity = time.Now()
+
+	if err := b.CloseBrowser(); err != nil {
+		t.Fatalf("CloseBrowser failed: %v", err)
+	}
+	if urls, quiet := b.networkState(); len(urls) != 0 || quiet != 0 {
+		t.Errorf("expected no request in flight and no activity after close, got %v, %s", urls, quiet)
+	}
+}
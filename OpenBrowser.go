@@ -74,6 +74,20 @@ func (h *DevBrowser) OpenBrowser(port string, https bool) {
 		h.initializeNetworkCapture()
 		h.initializeErrorCapture()
 		h.initializeInterceptCapture()
+		h.initializeDialogCapture()
+
+		// Restore the emulated environment and media before the first
+		// request, so the page loads with its locale, user agent and theme.
+		if h.GetEnvironment() != (Environment{}) {
+			if err := h.applyEnvironment(); err != nil {
+				h.Logger(fmt.Sprintf("Failed to restore environment emulation: %v", err))
+			}
+		}
+		if h.GetMedia() != (Media{}) {
+			if err := h.applyMedia(); err != nil {
+				h.Logger(fmt.Sprintf("Failed to restore media emulation: %v", err))
+			}
+		}
 
 		if err := chromedp.Run(h.Ctx,
 			chromedp.Navigate(url),
@@ -83,8 +97,10 @@ func (h *DevBrowser) OpenBrowser(port string, https bool) {
 			return
 		}
 
-		// Esperar un momento adicional para asegurar que todo esté cargado
-		time.Sleep(100 * time.Millisecond)
+		// Esperar a que terminen las peticiones iniciales (scripts, wasm, fetch)
+		if _, err := h.WaitFor(WaitCondition{NetworkIdle: 100 * time.Millisecond, Timeout: 3 * time.Second}); err != nil {
+			h.Logger("Warning: page still loading:", err)
+		}
 
 		// Restore device emulation if set
 		h.Mu.Lock()
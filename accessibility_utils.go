@@ -0,0 +1,208 @@
+package devbrowser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tinywasm/devbrowser/cdproto/accessibility"
+	"github.com/tinywasm/devbrowser/cdproto/cdp"
+)
+
+// axTreeMaxLines bounds the text snapshot so a huge page cannot flood the
+// LLM context; the tail is replaced by a "truncated" note.
+const axTreeMaxLines = 2000
+
+// AXTreeOptions controls FormatAXTree.
+type AXTreeOptions struct {
+	// InterestingOnly drops ignored nodes and unnamed structural wrappers
+	// (generic, none, presentation...), lifting their children one level up,
+	// and text nodes that only repeat their parent's name.
+	InterestingOnly bool
+	MaxDepth        int // 0 = unlimited
+	// Ref, when set, names the DOM node behind each shown element node;
+	// a non-empty name is printed as "ref:<name>" at the end of the line.
+	Ref func(cdp.BackendNodeID) string
+}
+
+// axStructuralRoles are roles that only carry meaning when they have a name.
+var axStructuralRoles = map[string]bool{
+	"generic":         true,
+	"none":            true,
+	"presentation":    true,
+	"InlineTextBox":   true,
+	"LineBreak":       true,
+	"Section":         true,
+	"LayoutTable":     true,
+	"LayoutTableRow":  true,
+	"LayoutTableCell": true,
+}
+
+// axNoRefRoles are nodes that are not elements a selector could target.
+var axNoRefRoles = map[string]bool{
+	"RootWebArea":   true,
+	"StaticText":    true,
+	"InlineTextBox": true,
+	"LineBreak":     true,
+}
+
+// axStateProps are the properties shown in brackets after a node, in order.
+var axStateProps = []accessibility.PropertyName{
+	accessibility.PropertyNameLevel,
+	accessibility.PropertyNameChecked,
+	accessibility.PropertyNamePressed,
+	accessibility.PropertyNameExpanded,
+	accessibility.PropertyNameSelected,
+	accessibility.PropertyNameDisabled,
+	accessibility.PropertyNameRequired,
+	accessibility.PropertyNameReadonly,
+	accessibility.PropertyNameInvalid,
+	accessibility.PropertyNameModal,
+	accessibility.PropertyNameFocusable,
+	accessibility.PropertyNameFocused,
+}
+
+// FormatAXTree renders the accessibility nodes returned by
+// Accessibility.getFullAXTree as an indented outline starting at rootID,
+// one node per line:
+//
+//	RootWebArea "Shop"
+//	  navigation "Main"
+//	    link "Cart" [focusable]
+//	  checkbox "Remember me" [checked=false, focusable]
+//	  textbox "Email" [required, focusable] value="a@b.c" ref:e7
+//
+// The ref suffix only appears when opts.Ref is set.
+func FormatAXTree(nodes []*accessibility.Node, rootID accessibility.NodeID, opts AXTreeOptions) string {
+	byID := make(map[accessibility.NodeID]*accessibility.Node, len(nodes))
+	for _, n := range nodes {
+		byID[n.NodeID] = n
+	}
+
+	var sb strings.Builder
+	lines := 0
+	truncated := false
+
+	var walk func(id accessibility.NodeID, depth int, parentName string)
+	walk = func(id accessibility.NodeID, depth int, parentName string) {
+		n, ok := byID[id]
+		if !ok || truncated {
+			return
+		}
+
+		role := axValueString(n.Role)
+		name := axValueString(n.Name)
+
+		show := true
+		if opts.InterestingOnly {
+			switch {
+			case n.Ignored:
+				show = false
+			case axStructuralRoles[role] && name == "":
+				show = false
+			case role == "StaticText" && (name == "" || name == parentName):
+				show = false
+			}
+		}
+
+		childDepth := depth
+		childParentName := parentName
+		if show {
+			if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+				return
+			}
+			if lines == axTreeMaxLines {
+				sb.WriteString(fmt.Sprintf("... truncated at %d nodes\n", axTreeMaxLines))
+				truncated = true
+				return
+			}
+			sb.WriteString(strings.Repeat("  ", depth))
+			sb.WriteString(formatAXNode(n, role, name))
+			if opts.Ref != nil && n.BackendDOMNodeID != 0 && !axNoRefRoles[role] {
+				if ref := opts.Ref(n.BackendDOMNodeID); ref != "" {
+					sb.WriteString(" " + refPrefix + ref)
+				}
+			}
+			sb.WriteString("\n")
+			lines++
+			childDepth = depth + 1
+			childParentName = name
+		}
+
+		for _, c := range n.ChildIDs {
+			walk(c, childDepth, childParentName)
+		}
+	}
+	walk(rootID, 0, "")
+
+	return sb.String()
+}
+
+// formatAXNode renders `role "name" [states] value="..."`.
+func formatAXNode(n *accessibility.Node, role, name string) string {
+	if role == "" {
+		role = "unknown"
+	}
+	line := role
+	if n.Ignored {
+		line += " (ignored)"
+	}
+	if name != "" {
+		line += fmt.Sprintf(" %q", name)
+	}
+
+	props := make(map[accessibility.PropertyName]string, len(n.Properties))
+	for _, p := range n.Properties {
+		props[p.Name] = axValueString(p.Value)
+	}
+	var states []string
+	for _, pn := range axStateProps {
+		v, ok := props[pn]
+		if !ok {
+			continue
+		}
+		switch {
+		case v == "true":
+			states = append(states, string(pn))
+		case v == "false" || v == "":
+			// checked=false / pressed=false are informative for toggles;
+			// other false states are the default and only add noise.
+			if pn == accessibility.PropertyNameChecked || pn == accessibility.PropertyNamePressed || pn == accessibility.PropertyNameExpanded {
+				states = append(states, string(pn)+"=false")
+			}
+		default:
+			states = append(states, string(pn)+"="+v)
+		}
+	}
+	if len(states) > 0 {
+		line += " [" + strings.Join(states, ", ") + "]"
+	}
+
+	if v := axValueString(n.Value); v != "" {
+		line += fmt.Sprintf(" value=%q", v)
+	}
+	if d := axValueString(n.Description); d != "" {
+		line += fmt.Sprintf(" description=%q", d)
+	}
+	return line
+}
+
+// axValueString decodes an AX value (string, number, boolean or token) to
+// plain text.
+func axValueString(v *accessibility.Value) string {
+	if v == nil || len(v.Value) == 0 {
+		return ""
+	}
+	var decoded any
+	if err := json.Unmarshal(v.Value, &decoded); err != nil {
+		return string(v.Value)
+	}
+	switch d := decoded.(type) {
+	case string:
+		return strings.Join(strings.Fields(d), " ")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(d)
+	}
+}
@@ -0,0 +1,184 @@
+package devbrowser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"time"
+)
+
+// AnimationFrame is one encoded still (PNG or JPEG) of an animation and how
+// long it stays on screen before the next frame.
+type AnimationFrame struct {
+	Data  []byte
+	Delay time.Duration
+}
+
+// EncodeGIF builds a looping animated GIF from frames. Every frame is
+// dithered to the web-safe Plan 9 palette and drawn onto a canvas the size of
+// the first frame. GIF delays have 10ms resolution; shorter delays are
+// rounded up so players do not fall back to their own default speed.
+func EncodeGIF(frames []AnimationFrame) ([]byte, error) {
+	imgs, err := decodeAnimationFrames(frames)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := imgs[0].Bounds()
+	anim := &gif.GIF{LoopCount: 0}
+	for i, img := range imgs {
+		p := image.NewPaletted(bounds, palette.Plan9)
+		draw.FloydSteinberg.Draw(p, bounds, img, image.Point{})
+		anim.Image = append(anim.Image, p)
+
+		delay := int((frames[i].Delay + 5*time.Millisecond) / (10 * time.Millisecond))
+		if delay < 2 {
+			delay = 2
+		}
+		anim.Delay = append(anim.Delay, delay)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, fmt.Errorf("failed to encode gif: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeAPNG builds a looping animated PNG from frames, in full colour. The
+// first frame doubles as the default image, so viewers without APNG support
+// still show a regular PNG.
+//
+// Each frame is encoded with image/png and its IDAT payload is re-wrapped as
+// the APNG fdAT chunk, so frames must share one colour type: they are all
+// flattened onto an opaque canvas the size of the first frame.
+func EncodeAPNG(frames []AnimationFrame) ([]byte, error) {
+	imgs, err := decodeAnimationFrames(frames)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := imgs[0].Bounds()
+	var out bytes.Buffer
+	out.WriteString("\x89PNG\r\n\x1a\n")
+
+	var seq uint32
+	for i, img := range imgs {
+		canvas := image.NewRGBA(bounds)
+		draw.Draw(canvas, bounds, &image.Uniform{color.White}, image.Point{}, draw.Src)
+		draw.Draw(canvas, bounds, img, img.Bounds().Min, draw.Over)
+
+		var enc bytes.Buffer
+		if err := png.Encode(&enc, canvas); err != nil {
+			return nil, fmt.Errorf("failed to encode frame %d: %v", i+1, err)
+		}
+		ihdr, idat, err := splitPNGChunks(enc.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode frame %d: %v", i+1, err)
+		}
+
+		if i == 0 {
+			writePNGChunk(&out, "IHDR", ihdr)
+			actl := make([]byte, 8)
+			binary.BigEndian.PutUint32(actl[0:], uint32(len(imgs)))
+			binary.BigEndian.PutUint32(actl[4:], 0) // loop forever
+			writePNGChunk(&out, "acTL", actl)
+		}
+
+		delayMs := frames[i].Delay.Milliseconds()
+		if delayMs > 0xffff {
+			delayMs = 0xffff
+		}
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], seq)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(bounds.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(bounds.Dy()))
+		// x/y offsets stay 0: every frame covers the whole canvas.
+		binary.BigEndian.PutUint16(fctl[20:], uint16(delayMs))
+		binary.BigEndian.PutUint16(fctl[22:], 1000)
+		// dispose_op and blend_op stay 0 (none / source).
+		writePNGChunk(&out, "fcTL", fctl)
+		seq++
+
+		if i == 0 {
+			writePNGChunk(&out, "IDAT", idat)
+			continue
+		}
+		fdat := make([]byte, 4+len(idat))
+		binary.BigEndian.PutUint32(fdat, seq)
+		copy(fdat[4:], idat)
+		writePNGChunk(&out, "fdAT", fdat)
+		seq++
+	}
+
+	writePNGChunk(&out, "IEND", nil)
+	return out.Bytes(), nil
+}
+
+// decodeAnimationFrames decodes every frame, rejecting an empty list.
+func decodeAnimationFrames(frames []AnimationFrame) ([]image.Image, error) {
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("animation needs at least one frame")
+	}
+	imgs := make([]image.Image, len(frames))
+	for i, f := range frames {
+		img, _, err := image.Decode(bytes.NewReader(f.Data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode frame %d: %v", i+1, err)
+		}
+		imgs[i] = img
+	}
+	return imgs, nil
+}
+
+// splitPNGChunks returns the IHDR payload and the concatenated IDAT payloads
+// of an encoded PNG.
+func splitPNGChunks(data []byte) (ihdr, idat []byte, err error) {
+	if len(data) < 8 {
+		return nil, nil, fmt.Errorf("png too short")
+	}
+	rest := data[8:]
+	for len(rest) >= 12 {
+		n := int(binary.BigEndian.Uint32(rest))
+		if len(rest) < 12+n {
+			return nil, nil, fmt.Errorf("truncated png chunk")
+		}
+		typ := string(rest[4:8])
+		payload := rest[8 : 8+n]
+		switch typ {
+		case "IHDR":
+			ihdr = payload
+		case "IDAT":
+			idat = append(idat, payload...)
+		}
+		rest = rest[12+n:]
+	}
+	if ihdr == nil || idat == nil {
+		return nil, nil, fmt.Errorf("png without IHDR or IDAT")
+	}
+	return ihdr, idat, nil
+}
+
+// writePNGChunk writes one length-prefixed, CRC-terminated PNG chunk.
+func writePNGChunk(w *bytes.Buffer, typ string, payload []byte) {
+	var hdr [8]byte
+	binary.BigEndian.PutUint32(hdr[:4], uint32(len(payload)))
+	copy(hdr[4:], typ)
+	w.Write(hdr[:])
+	w.Write(payload)
+
+	crc := crc32.NewIEEE()
+	crc.Write(hdr[4:])
+	crc.Write(payload)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+	w.Write(sum[:])
+}
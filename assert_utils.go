@@ -0,0 +1,439 @@
+package devbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// assertTimeout bounds the reads of one assertion.
+const assertTimeout = 5 * time.Second
+
+// Assertion is a check of the page for Assert and browser_assert.
+type Assertion struct {
+	Type     string // exists, visible, count, text, attribute, property, style, url, local_storage, no_console_errors or no_failed_requests
+	Selector string // Element of exists, visible, count, attribute, property and style; scopes text (default the body)
+	Name     string // Attribute, property, CSS property or localStorage key
+	Op       string // equals (default), contains or matches (a regexp); count: equals, at_least or at_most
+	Expected string // Expected value; count: a number
+	Not      bool   // Negate exists, visible, count and value assertions
+	Since    string // Checkpoint no_console_errors and no_failed_requests start at (default: the page load)
+}
+
+// AssertionResult is the outcome of an Assertion: whether it passed and
+// the expected and actual values, for agents and Go tests alike.
+type AssertionResult struct {
+	Pass     bool
+	Check    string // What was checked, e.g. "text of #status"
+	Expected string
+	Actual   string
+}
+
+// String formats the result as PASS or FAIL with expected and actual.
+func (r AssertionResult) String() string {
+	status := "PASS"
+	if !r.Pass {
+		status = "FAIL"
+	}
+	return fmt.Sprintf("%s %s\n  expected: %s\n  actual:   %s", status, r.Check, r.Expected, r.Actual)
+}
+
+// assertMissing is the actual value of an assertion on an element that is
+// not in the page: such assertions fail, negated or not.
+type assertMissing struct{ actual string }
+
+func (e assertMissing) Error() string { return e.actual }
+
+// elementAssertions take a Selector; valueAssertions compare a value with Op.
+var (
+	elementAssertions = map[string]bool{"exists": true, "visible": true, "count": true, "attribute": true, "property": true, "style": true}
+	valueAssertions   = map[string]bool{"text": true, "attribute": true, "property": true, "style": true, "url": true, "local_storage": true}
+	namedAssertions   = map[string]bool{"attribute": true, "property": true, "style": true, "local_storage": true}
+)
+
+// validate checks that the fields of a fit its type.
+func (a Assertion) validate() error {
+	switch a.Type {
+	case "exists", "visible", "count", "text", "attribute", "property", "style", "url", "local_storage", "no_console_errors", "no_failed_requests":
+	case "":
+		return fmt.Errorf("type required: exists, visible, count, text, attribute, property, style, url, local_storage, no_console_errors or no_failed_requests")
+	default:
+		return fmt.Errorf("unsupported type: %s. Use exists, visible, count, text, attribute, property, style, url, local_storage, no_console_errors or no_failed_requests", a.Type)
+	}
+
+	if elementAssertions[a.Type] && a.Selector == "" {
+		return fmt.Errorf("%s needs a selector", a.Type)
+	}
+	if !elementAssertions[a.Type] && a.Type != "text" && a.Selector != "" {
+		return fmt.Errorf("%s takes no selector", a.Type)
+	}
+	if namedAssertions[a.Type] && a.Name == "" {
+		return fmt.Errorf("%s needs a name", a.Type)
+	}
+	if !namedAssertions[a.Type] && a.Name != "" {
+		return fmt.Errorf("%s takes no name", a.Type)
+	}
+	if a.Since != "" && a.Type != "no_console_errors" && a.Type != "no_failed_requests" {
+		return fmt.Errorf("since applies to no_console_errors and no_failed_requests")
+	}
+
+	switch {
+	case a.Type == "count":
+		switch a.Op {
+		case "", "equals", "at_least", "at_most":
+		default:
+			return fmt.Errorf("unsupported op for count: %s. Use equals, at_least or at_most", a.Op)
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(a.Expected)); err != nil || n < 0 {
+			return fmt.Errorf("count needs expected as a number, got %q", a.Expected)
+		}
+	case valueAssertions[a.Type]:
+		switch a.Op {
+		case "", "equals", "contains":
+		case "matches":
+			if _, err := regexp.Compile(a.Expected); err != nil {
+				return fmt.Errorf("invalid regexp %s: %v", a.Expected, err)
+			}
+		default:
+			return fmt.Errorf("unsupported op: %s. Use equals, contains or matches", a.Op)
+		}
+	default:
+		if a.Op != "" || a.Expected != "" {
+			return fmt.Errorf("%s takes no op or expected", a.Type)
+		}
+		if a.Not && (a.Type == "no_console_errors" || a.Type == "no_failed_requests") {
+			return fmt.Errorf("%s cannot be negated", a.Type)
+		}
+	}
+	return nil
+}
+
+func (a Assertion) op() string {
+	if a.Op == "" {
+		return "equals"
+	}
+	return a.Op
+}
+
+// check names what a checks.
+func (a Assertion) check() string {
+	switch a.Type {
+	case "exists", "visible", "count":
+		return a.Selector
+	case "text":
+		if a.Selector == "" {
+			return "text of the page"
+		}
+		return "text of " + a.Selector
+	case "attribute", "property", "style":
+		return fmt.Sprintf("%s %s of %s", a.Type, a.Name, a.Selector)
+	case "url":
+		return "URL"
+	case "local_storage":
+		return "localStorage " + a.Name
+	}
+	since := "the page load"
+	if a.Since != "" {
+		since = "checkpoint " + a.Since
+	}
+	if a.Type == "no_console_errors" {
+		return "console errors since " + since
+	}
+	return "failed requests since " + since
+}
+
+// expected describes the value a expects.
+func (a Assertion) expected() string {
+	not := ""
+	if a.Not {
+		not = "not "
+	}
+	switch a.Type {
+	case "exists":
+		if a.Not {
+			return "absent"
+		}
+		return "present"
+	case "visible":
+		return not + "visible"
+	case "count":
+		return not + a.op() + " " + strings.TrimSpace(a.Expected)
+	case "no_console_errors", "no_failed_requests":
+		return "none"
+	}
+	if a.op() == "matches" {
+		return not + "matches /" + a.Expected + "/"
+	}
+	return fmt.Sprintf("%s%s %q", not, a.op(), a.Expected)
+}
+
+// Assert runs an assertion against the page. A check that does not hold is
+// a result with Pass false; err is for assertions that cannot run.
+func (b *DevBrowser) Assert(a Assertion) (AssertionResult, error) {
+	res := AssertionResult{Check: a.check(), Expected: a.expected()}
+	if b.Ctx == nil {
+		return res, errors.New("context not initialized")
+	}
+	if err := a.validate(); err != nil {
+		return res, err
+	}
+	if err := b.checkSelector(a.Selector); err != nil {
+		return res, err
+	}
+
+	ctx, cancel := context.WithTimeout(b.Ctx, assertTimeout)
+	defer cancel()
+	if err := checkCSSSyntax(ctx, a.Selector); err != nil {
+		return res, err
+	}
+
+	holds, actual, err := b.assertActual(ctx, a)
+	var missing assertMissing
+	if errors.As(err, &missing) {
+		res.Actual = missing.actual
+		return res, nil
+	}
+	if err != nil {
+		return res, err
+	}
+	res.Actual = actual
+	res.Pass = holds != a.Not
+	return res, nil
+}
+
+// assertActual reads the actual value of a and whether it holds, before Not.
+func (b *DevBrowser) assertActual(ctx context.Context, a Assertion) (bool, string, error) {
+	switch a.Type {
+	case "exists":
+		var ok bool
+		if err := b.callOnElement(ctx, a.Selector, `function() { return true; }`, &ok); isElementMissing(err) {
+			return false, "absent", nil
+		} else if err != nil {
+			return false, "", err
+		}
+		return true, "present", nil
+
+	case "visible":
+		var st struct {
+			Visible bool   `json:"visible"`
+			Box     string `json:"box"`
+		}
+		if err := b.callOnElement(ctx, a.Selector, elementStateJS, &st); isElementMissing(err) {
+			return false, "absent", nil
+		} else if err != nil {
+			return false, "", err
+		}
+		if st.Visible {
+			return true, "visible, " + st.Box, nil
+		}
+		return false, "present, hidden", nil
+
+	case "count":
+		n, err := b.countElements(ctx, a.Selector)
+		if err != nil {
+			return false, "", err
+		}
+		want, _ := strconv.Atoi(strings.TrimSpace(a.Expected))
+		switch a.op() {
+		case "at_least":
+			return n >= want, strconv.Itoa(n), nil
+		case "at_most":
+			return n <= want, strconv.Itoa(n), nil
+		}
+		return n == want, strconv.Itoa(n), nil
+
+	case "no_console_errors":
+		since, err := b.assertSince(a.Since)
+		if err != nil {
+			return false, "", err
+		}
+		var found []string
+		b.LogsMutex.Lock()
+		for _, e := range b.consoleErrors {
+			if !e.at.Before(since) {
+				found = append(found, e.text)
+			}
+		}
+		b.LogsMutex.Unlock()
+		return len(found) == 0, listActual(found), nil
+
+	case "no_failed_requests":
+		since, err := b.assertSince(a.Since)
+		if err != nil {
+			return false, "", err
+		}
+		found := b.failedRequestsSince(since)
+		return len(found) == 0, listActual(found), nil
+	}
+
+	value, err := b.assertValue(ctx, a)
+	if err != nil {
+		return false, "", err
+	}
+	if value == nil {
+		return false, "(missing)", nil
+	}
+	return compareValue(a, *value), strconv.Quote(*value), nil
+}
+
+// assertValue reads the value a compares: nil when the attribute or the
+// localStorage key is missing.
+func (b *DevBrowser) assertValue(ctx context.Context, a Assertion) (*string, error) {
+	name, err := json.Marshal(a.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var fn string
+	switch a.Type {
+	case "url":
+		var loc string
+		if err := chromedp.Run(ctx, chromedp.Location(&loc)); err != nil {
+			return nil, err
+		}
+		// /dashboard compares with the path (and query and fragment) alone.
+		if a.op() == "equals" && strings.HasPrefix(a.Expected, "/") {
+			if u, err := url.Parse(loc); err == nil {
+				u.Scheme, u.Host, u.User = "", "", nil
+				loc = u.String()
+			}
+		}
+		return &loc, nil
+
+	case "local_storage":
+		var v *string
+		if err := chromedp.Run(ctx, chromedp.Evaluate(`localStorage.getItem(`+string(name)+`)`, &v)); err != nil {
+			return nil, err
+		}
+		return v, nil
+
+	case "text":
+		if a.Selector == "" {
+			var text string
+			if err := chromedp.Run(ctx, chromedp.Evaluate(`document.body ? document.body.innerText : ''`, &text)); err != nil {
+				return nil, err
+			}
+			return &text, nil
+		}
+		fn = `function() { return {value: this.innerText || this.textContent || ''}; }`
+	case "attribute":
+		fn = `function() { const v = this.getAttribute(` + string(name) + `); return v === null ? {} : {value: v}; }`
+	case "property":
+		fn = `function() {
+			const v = this[` + string(name) + `];
+			if (v === undefined || typeof v === 'string') return {value: v === undefined ? null : v};
+			try { return {value: JSON.stringify(v) ?? String(v)}; } catch (e) { return {value: String(v)}; }
+		}`
+	case "style":
+		fn = `function() { return {value: this.ownerDocument.defaultView.getComputedStyle(this).getPropertyValue(` + string(name) + `).trim()}; }`
+	}
+
+	var res struct {
+		Value *string `json:"value"`
+	}
+	if err := b.callOnElement(ctx, a.Selector, fn, &res); isElementMissing(err) {
+		return nil, assertMissing{"element not found: " + a.Selector}
+	} else if err != nil {
+		return nil, err
+	}
+	if a.Type == "property" && res.Value == nil {
+		s := "undefined"
+		return &s, nil
+	}
+	return res.Value, nil
+}
+
+// compareValue applies the op of a to value. equals on text ignores how
+// whitespace is laid out.
+func compareValue(a Assertion, value string) bool {
+	switch a.op() {
+	case "contains":
+		return strings.Contains(value, a.Expected)
+	case "matches":
+		return regexp.MustCompile(a.Expected).MatchString(value)
+	}
+	if a.Type == "text" {
+		return strings.Join(strings.Fields(value), " ") == strings.Join(strings.Fields(a.Expected), " ")
+	}
+	return value == a.Expected
+}
+
+// countElements counts the matches of a CSS selector or of a locator. A ref
+// is one element or none; a selector path has no count.
+func (b *DevBrowser) countElements(ctx context.Context, selector string) (int, error) {
+	switch {
+	case isElementRef(selector):
+		var ok bool
+		if err := b.callOnElement(ctx, selector, `function() { return true; }`, &ok); err != nil {
+			return 0, nil
+		}
+		return 1, nil
+	case isLocator(selector) && !strings.Contains(selector, selectorPathSep):
+		l, err := parseLocator(selector)
+		if err != nil {
+			return 0, err
+		}
+		var n int
+		err = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			nodes, err := locatorNodes(ctx, nil, l)
+			n = len(nodes)
+			return err
+		}))
+		return n, err
+	case isDeepSelector(selector):
+		return 0, fmt.Errorf("count takes a CSS selector, a locator or a ref, not a selector path")
+	}
+
+	arg, err := json.Marshal(selector)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	err = chromedp.Run(ctx, chromedp.Evaluate(`document.querySelectorAll(`+string(arg)+`).length`, &n))
+	return n, err
+}
+
+// assertSince is when a checkpoint was saved, or the start of the page.
+func (b *DevBrowser) assertSince(checkpoint string) (time.Time, error) {
+	if checkpoint == "" {
+		b.LogsMutex.Lock()
+		defer b.LogsMutex.Unlock()
+		return b.pageStart, nil
+	}
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+	at, ok := b.checkpointTimes[checkpoint]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unknown checkpoint %q: save it first with browser_assert checkpoint=%q", checkpoint, checkpoint)
+	}
+	return at, nil
+}
+
+// markCheckpoint records when checkpoint was saved. The caller holds Mu.
+func (b *DevBrowser) markCheckpoint(checkpoint string) {
+	if b.checkpointTimes == nil {
+		b.checkpointTimes = make(map[string]time.Time)
+	}
+	b.checkpointTimes[checkpoint] = time.Now()
+}
+
+// listActual shows what a no_* assertion found: none, or the first few.
+func listActual(found []string) string {
+	if len(found) == 0 {
+		return "none"
+	}
+	shown := found[:min(len(found), 5)]
+	out := fmt.Sprintf("%d: %s", len(found), strings.Join(shown, "; "))
+	if len(found) > len(shown) {
+		out += fmt.Sprintf("; and %d more", len(found)-len(shown))
+	}
+	return out
+}
@@ -0,0 +1,254 @@
+package devbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/tinywasm/devbrowser/cdproto/css"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// assetURLJS drops the cache-busting query of an earlier reload.
+const assetURLJS = `(href) => {
+	const u = new URL(href);
+	u.searchParams.delete('__devbrowser');
+	return u.href;
+}`
+
+// assetMatchJS is the in-page twin of assetMatches.
+const assetMatchJS = `(href, filter) => {
+	if (!filter) return true;
+	let u;
+	try { u = new URL(href, location.href); } catch (e) { return false; }
+	const p = u.origin + u.pathname;
+	return p === filter || p.endsWith(filter.startsWith('/') ? filter : '/' + filter);
+}`
+
+// reloadLinksJS reloads the <link rel=stylesheet> elements matching filter
+// with a cache-busting query, adding the new link before removing the old
+// one so the page never shows unstyled. It returns the URLs that loaded.
+const reloadLinksJS = `(filter) => {
+	const matches = ` + assetMatchJS + `;
+	const clean = ` + assetURLJS + `;
+	const links = Array.from(document.querySelectorAll('link[rel~="stylesheet"][href]')).filter(l => matches(l.href, filter));
+	return Promise.all(links.map(link => new Promise(resolve => {
+		const url = new URL(link.href);
+		url.searchParams.set('__devbrowser', Date.now());
+		const next = link.cloneNode();
+		next.href = url.href;
+		const done = ok => {
+			clearTimeout(timer);
+			(ok ? link : next).remove();
+			resolve(ok ? clean(link.href) : '');
+		};
+		const timer = setTimeout(() => done(false), 5000);
+		next.onload = () => done(true);
+		next.onerror = () => done(false);
+		link.after(next);
+	}))).then(r => r.filter(Boolean));
+}`
+
+// reloadImagesJS reloads the <img> elements matching filter with a
+// cache-busting query and returns the URLs that loaded.
+const reloadImagesJS = `(filter) => {
+	const matches = ` + assetMatchJS + `;
+	const clean = ` + assetURLJS + `;
+	const imgs = Array.from(document.images).filter(i => i.getAttribute('src') && matches(i.src, filter));
+	return Promise.all(imgs.map(img => new Promise(resolve => {
+		const orig = clean(img.src);
+		const url = new URL(orig);
+		url.searchParams.set('__devbrowser', Date.now());
+		const done = ok => {
+			clearTimeout(timer);
+			resolve(ok ? orig : '');
+		};
+		const timer = setTimeout(() => done(false), 5000);
+		img.addEventListener('load', () => done(true), {once: true});
+		img.addEventListener('error', () => done(false), {once: true});
+		img.removeAttribute('srcset');
+		img.src = url.href;
+	}))).then(r => r.filter(Boolean));
+}`
+
+// fetchFreshJS fetches a URL past the cache, for stylesheets replaced
+// through the CSS domain.
+const fetchFreshJS = `(href) => fetch(href, {cache: 'no-store'}).then(r => {
+	if (!r.ok) throw new Error('HTTP ' + r.status);
+	return r.text();
+})`
+
+// assetMatches reports whether the asset at href matches filter: the whole
+// URL without its query, or a path suffix like /css/app.css or app.css. An
+// empty filter matches everything.
+func assetMatches(href, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	u.RawQuery, u.Fragment = "", ""
+	p := u.String()
+	if p == filter {
+		return true
+	}
+	if !strings.HasPrefix(filter, "/") {
+		filter = "/" + filter
+	}
+	return strings.HasSuffix(p, filter)
+}
+
+// ReloadStylesheet reloads the stylesheets whose URL matches url (a full
+// URL or a path suffix like app.css; all when empty) without reloading the
+// page: <link rel=stylesheet> elements are swapped for cache-busted copies
+// and imported sheets get their fresh text through CSS.setStyleSheetText.
+// It returns the URLs reloaded.
+func (b *DevBrowser) ReloadStylesheet(url string) ([]string, error) {
+	if b.Ctx == nil {
+		return nil, errors.New("context not initialized")
+	}
+	ctx, cancel := context.WithTimeout(b.Ctx, navDefaultTimeout)
+	defer cancel()
+
+	// Read the sheets before the swap, which adds sheets of its own.
+	imported, err := importedStyleSheets(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing stylesheets: %v", err)
+	}
+
+	filter, err := json.Marshal(url)
+	if err != nil {
+		return nil, err
+	}
+	var reloaded []string
+	if err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf("(%s)(%s)", reloadLinksJS, filter), &reloaded, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	})); err != nil {
+		return nil, fmt.Errorf("Error reloading stylesheets: %v", err)
+	}
+
+	for _, h := range imported {
+		href, err := json.Marshal(h.SourceURL)
+		if err != nil {
+			return reloaded, err
+		}
+		var text string
+		if err := chromedp.Run(ctx,
+			chromedp.Evaluate(fmt.Sprintf("(%s)(%s)", fetchFreshJS, href), &text, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+				return p.WithAwaitPromise(true)
+			}),
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				_, err := css.SetStyleSheetText(h.StyleSheetID, text).Do(ctx)
+				return err
+			}),
+		); err != nil {
+			return reloaded, fmt.Errorf("Error reloading %s: %v", h.SourceURL, err)
+		}
+		reloaded = append(reloaded, h.SourceURL)
+	}
+	return reloaded, nil
+}
+
+// importedStyleSheets lists the stylesheets matching filter that no element
+// owns (sheets pulled in with @import). Re-enabling the CSS domain makes
+// Chrome announce every stylesheet of the page again.
+func importedStyleSheets(ctx context.Context, filter string) ([]*css.StyleSheetHeader, error) {
+	var headers []*css.StyleSheetHeader
+	lctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	chromedp.ListenTarget(lctx, func(ev interface{}) {
+		if ev, ok := ev.(*css.EventStyleSheetAdded); ok {
+			h := ev.Header
+			if h.OwnerNode == 0 && !h.IsInline && !h.HasSourceURL && h.SourceURL != "" && h.Origin == css.StyleSheetOriginRegular && assetMatches(h.SourceURL, filter) {
+				headers = append(headers, h)
+			}
+		}
+	})
+	// Events arrive before the reply of the command that caused them.
+	if err := chromedp.Run(ctx, css.Disable(), css.Enable()); err != nil {
+		return nil, err
+	}
+	cancel()
+	return headers, nil
+}
+
+// ReloadAssets reloads the assets of a kind in place, without reloading the
+// page: css (every stylesheet, see ReloadStylesheet) or images (every
+// <img>). It returns the URLs reloaded.
+func (b *DevBrowser) ReloadAssets(kind string) ([]string, error) {
+	switch kind {
+	case "css":
+		return b.ReloadStylesheet("")
+	case "images":
+		return b.reloadImages("")
+	}
+	return nil, fmt.Errorf("unsupported asset kind: %s. Use css or images", kind)
+}
+
+// reloadImages reloads the <img> elements matching filter.
+func (b *DevBrowser) reloadImages(filter string) ([]string, error) {
+	if b.Ctx == nil {
+		return nil, errors.New("context not initialized")
+	}
+	ctx, cancel := context.WithTimeout(b.Ctx, navDefaultTimeout)
+	defer cancel()
+	encoded, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	var reloaded []string
+	if err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf("(%s)(%s)", reloadImagesJS, encoded), &reloaded, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	})); err != nil {
+		return nil, fmt.Errorf("Error reloading images: %v", err)
+	}
+	return reloaded, nil
+}
+
+// assetKind is the asset kind of a changed file that can be reloaded in
+// place, or "".
+func assetKind(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".css":
+		return "css"
+	case ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".avif":
+		return "images"
+	}
+	return ""
+}
+
+// ReloadForFile reloads the page after path changed. With soft reloads on,
+// a stylesheet or image change reloads the matching assets in place
+// instead (all of their kind when none matches the file name, as with
+// bundled CSS), falling back to a reload when the page uses none.
+func (b *DevBrowser) ReloadForFile(path string) error {
+	kind := assetKind(path)
+	b.Mu.Lock()
+	inPlace := b.softReload && b.ready && b.Ctx != nil && b.IsOpenFlag && kind != ""
+	b.Mu.Unlock()
+	if !inPlace {
+		return b.Reload()
+	}
+
+	reload := b.ReloadStylesheet
+	if kind == "images" {
+		reload = b.reloadImages
+	}
+	name := filepath.Base(path)
+	reloaded, err := reload(name)
+	if err == nil && len(reloaded) == 0 {
+		reloaded, err = reload("")
+	}
+	if err != nil || len(reloaded) == 0 {
+		return b.Reload()
+	}
+	b.Logger(fmt.Sprintf("Hot-swap for %s: %s reloaded in place", name, strings.Join(reloaded, ", ")))
+	return nil
+}
@@ -1,6 +1,7 @@
 package devbrowser
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -11,6 +12,8 @@ const (
 	StoreKeyBrowserSize      = "browser_size"
 	StoreKeyViewportMode     = "viewport_mode"
 	StoreKeyViewportDevice   = "viewport_device"
+	StoreKeyEnvironment      = "browser_environment"
+	StoreKeyMedia            = "browser_media"
 )
 
 // LoadConfig loads all browser configuration from the store
@@ -62,6 +65,22 @@ func (b *DevBrowser) LoadConfig() {
 	if device, err := b.DB.Get(StoreKeyViewportDevice); err == nil && device != "" {
 		b.ViewportDevice = device
 	}
+
+	// Load environment emulation (JSON)
+	if env, err := b.DB.Get(StoreKeyEnvironment); err == nil && env != "" {
+		var e Environment
+		if err := json.Unmarshal([]byte(env), &e); err == nil && e.validate() == nil {
+			b.environment = e
+		}
+	}
+
+	// Load media emulation (JSON)
+	if media, err := b.DB.Get(StoreKeyMedia); err == nil && media != "" {
+		var m Media
+		if err := json.Unmarshal([]byte(media), &m); err == nil && m.validate() == nil {
+			b.media = m
+		}
+	}
 }
 
 // SaveConfig saves all browser configuration to the store
@@ -96,5 +115,15 @@ func (b *DevBrowser) SaveConfig() error {
 		return err
 	}
 
+	// Save environment emulation
+	if err := b.DB.Set(StoreKeyEnvironment, encodeEnvironment(b.environment)); err != nil {
+		return err
+	}
+
+	// Save media emulation
+	if err := b.DB.Set(StoreKeyMedia, encodeMedia(b.media)); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -3,6 +3,7 @@ package devbrowser
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/tinywasm/devbrowser/cdproto/audits"
 	"github.com/tinywasm/devbrowser/cdproto/log"
@@ -18,7 +19,11 @@ func (b *DevBrowser) initializeConsoleCapture() error {
 	}
 
 	// Initialize the console logs slice
+	b.LogsMutex.Lock()
 	b.ConsoleLogs = []string{}
+	b.consoleErrors = nil
+	b.pageStart = time.Now()
+	b.LogsMutex.Unlock()
 
 	// Listen for console API called events and console cleared events
 	chromedp.ListenTarget(b.Ctx, func(ev interface{}) {
@@ -27,28 +32,11 @@ func (b *DevBrowser) initializeConsoleCapture() error {
 			b.LogsMutex.Lock()
 			defer b.LogsMutex.Unlock()
 
-			// Format the arguments without prefix
-			var message string
-			for i, arg := range ev.Args {
-				if i > 0 {
-					message += " "
-				}
-				// Get the string value from the RemoteObject
-				if arg.Value != nil {
-					// Extract the raw value without JSON encoding
-					val := string(arg.Value)
-					// Remove surrounding quotes if it's a string value
-					if len(val) > 2 && val[0] == '"' && val[len(val)-1] == '"' {
-						val = val[1 : len(val)-1]
-					}
-					message += val
-				} else if arg.Description != "" {
-					message += arg.Description
-				}
-			}
-
 			// Add to logs without type prefix to save tokens
-			b.ConsoleLogs = append(b.ConsoleLogs, message)
+			b.ConsoleLogs = append(b.ConsoleLogs, formatConsoleArgs(ev.Args))
+			if ev.Type == runtime.APITypeError || ev.Type == runtime.APITypeAssert {
+				b.addConsoleError(formatConsoleArgs(ev.Args))
+			}
 
 		case *runtime.EventExceptionThrown:
 			b.LogsMutex.Lock()
@@ -60,6 +48,7 @@ func (b *DevBrowser) initializeConsoleCapture() error {
 				msg += ": " + ev.ExceptionDetails.Exception.Description
 			}
 			b.ConsoleLogs = append(b.ConsoleLogs, msg)
+			b.addConsoleError(msg)
 
 		case *log.EventEntryAdded:
 			b.LogsMutex.Lock()
@@ -72,6 +61,9 @@ func (b *DevBrowser) initializeConsoleCapture() error {
 				msg += fmt.Sprintf(" (%s)", ev.Entry.URL)
 			}
 			b.ConsoleLogs = append(b.ConsoleLogs, msg)
+			if ev.Entry.Level == log.LevelError {
+				b.addConsoleError(msg)
+			}
 
 		case *audits.EventIssueAdded:
 			b.LogsMutex.Lock()
@@ -87,6 +79,7 @@ func (b *DevBrowser) initializeConsoleCapture() error {
 			// Clear logs when execution contexts are cleared (page reload/navigation)
 			b.LogsMutex.Lock()
 			b.ConsoleLogs = []string{}
+			b.pageStart = time.Now()
 			b.LogsMutex.Unlock()
 		}
 	})
@@ -104,6 +97,47 @@ func (b *DevBrowser) initializeConsoleCapture() error {
 	return nil
 }
 
+// maxConsoleErrors caps the console errors kept for assertions.
+const maxConsoleErrors = 200
+
+type consoleError struct {
+	text string
+	at   time.Time
+}
+
+// addConsoleError records a console error for assertions. The caller holds
+// LogsMutex.
+func (b *DevBrowser) addConsoleError(text string) {
+	b.consoleErrors = append(b.consoleErrors, consoleError{text: text, at: time.Now()})
+	if len(b.consoleErrors) > maxConsoleErrors {
+		b.consoleErrors = b.consoleErrors[len(b.consoleErrors)-maxConsoleErrors:]
+	}
+}
+
+// formatConsoleArgs joins console API arguments into a single line without
+// any type prefix.
+func formatConsoleArgs(args []*runtime.RemoteObject) string {
+	var message string
+	for i, arg := range args {
+		if i > 0 {
+			message += " "
+		}
+		// Get the string value from the RemoteObject
+		if arg.Value != nil {
+			// Extract the raw value without JSON encoding
+			val := string(arg.Value)
+			// Remove surrounding quotes if it's a string value
+			if len(val) > 2 && val[0] == '"' && val[len(val)-1] == '"' {
+				val = val[1 : len(val)-1]
+			}
+			message += val
+		} else if arg.Description != "" {
+			message += arg.Description
+		}
+	}
+	return message
+}
+
 // GetConsoleLogs returns captured console logs from the browser.
 // Returns an error if the browser context is not initialized.
 func (b *DevBrowser) GetConsoleLogs() ([]string, error) {
@@ -0,0 +1,329 @@
+package devbrowser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// contentFormats are the formats of browser_get_content.
+var contentFormats = map[string]bool{
+	"structure": true,
+	"markdown":  true,
+	"text":      true,
+	"links":     true,
+	"forms":     true,
+}
+
+// contentFormatJS renders the element it is called on in one of the
+// formats other than structure. It walks the rendered tree: open shadow
+// roots instead of their host's children, the nodes assigned to slots and
+// the body of same-origin frames, skipping hidden elements. Links and form
+// controls get ref placeholders like GetStructureJS.
+const contentFormatJS = `function(format) {
+	const refTargets = window.` + refTargetsGlobal + ` = [];
+	const ref = el => ' ref:@' + (refTargets.push(el) - 1);
+	const SKIP = new Set(['script', 'style', 'noscript', 'template', 'head', 'svg', 'select', 'textarea', 'datalist']);
+	const hidden = el => {
+		const style = el.ownerDocument.defaultView.getComputedStyle(el);
+		return style.display === 'none' || style.visibility === 'hidden' || style.opacity === '0';
+	};
+	const clean = s => (s || '').replace(/\s+/g, ' ').trim();
+
+	const kids = node => {
+		if (node.shadowRoot) return Array.from(node.shadowRoot.childNodes);
+		if (node.localName === 'slot') {
+			const assigned = node.assignedNodes({flatten: true});
+			return assigned.length ? assigned : Array.from(node.childNodes);
+		}
+		if (node.localName === 'iframe' || node.localName === 'frame') {
+			let doc = null;
+			try { doc = node.contentDocument; } catch (e) {}
+			return doc && doc.body ? [doc.body] : [];
+		}
+		return Array.from(node.childNodes);
+	};
+	const elements = (node, out = []) => {
+		for (const child of kids(node)) {
+			if (child.nodeType !== 1 || hidden(child)) continue;
+			out.push(child);
+			elements(child, out);
+		}
+		return out;
+	};
+
+	if (format === 'links') {
+		const seen = new Set();
+		const lines = elements(this)
+			.filter(el => el.localName === 'a' && typeof el.href === 'string' && el.href)
+			.map(a => {
+				const img = a.querySelector('img[alt]');
+				const text = clean(a.innerText) || clean(a.getAttribute('aria-label')) || clean(a.title) || clean(img && img.alt);
+				if (seen.has(text + '\n' + a.href)) return '';
+				seen.add(text + '\n' + a.href);
+				return '- ' + (text || '(no text)') + ' → ' + a.href + ref(a) + '\n';
+			});
+		return lines.join('') || 'No links.\n';
+	}
+
+	if (format === 'forms') {
+		// Controls are grouped by the form they submit with, in order of
+		// their first control; controls outside any form are grouped last.
+		const groups = new Map();
+		for (const el of elements(this)) {
+			if (!['input', 'select', 'textarea', 'button'].includes(el.localName)) continue;
+			const form = el.form || null;
+			if (!groups.has(form)) groups.set(form, []);
+			groups.get(form).push(el);
+		}
+		if (groups.has(null)) {
+			const loose = groups.get(null);
+			groups.delete(null);
+			groups.set(null, loose);
+		}
+
+		const quote = s => JSON.stringify(s.length > 80 ? s.slice(0, 80) + '…' : s);
+		const isButton = el => el.localName === 'button' || ['submit', 'button', 'reset', 'image'].includes(el.type);
+		const control = el => {
+			const tag = el.localName;
+			const labels = el.labels ? Array.from(el.labels).map(l => clean(l.innerText)).join(' ') : '';
+			const label = labels || clean(el.getAttribute('aria-label')) ||
+				(tag === 'button' ? clean(el.innerText) : '') || (isButton(el) ? clean(el.value) : '') ||
+				clean(el.placeholder) || clean(el.title);
+
+			let line = '  - ' + (tag === 'input' ? el.type : tag === 'button' ? 'button ' + el.type : tag);
+			if (label) line += ' ' + quote(label);
+			if (el.name) line += ' name=' + el.name;
+			if (tag === 'select') {
+				const opts = Array.from(el.options);
+				line += ' options=[' + opts.slice(0, 20).map(o => (o.selected ? '*' : '') + clean(o.text)).join(', ') +
+					(opts.length > 20 ? ', … ' + (opts.length - 20) + ' more' : '') + ']';
+			} else if (el.type === 'checkbox' || el.type === 'radio') {
+				line += el.checked ? ' checked' : ' unchecked';
+				if (el.value !== 'on') line += ' value=' + quote(el.value);
+			} else if (!isButton(el) && el.type !== 'file') {
+				line += ' value=' + quote(el.type === 'password' && el.value ? '••••' : el.value);
+			}
+			['required', 'disabled', 'readonly', 'multiple'].forEach(a => {
+				if (el.hasAttribute(a)) line += ' ' + a;
+			});
+			return line + ref(el) + '\n';
+		};
+
+		let out = '';
+		for (const [form, controls] of groups) {
+			if (form) {
+				out += 'form' + (form.id ? '#' + form.id : '') + (form.getAttribute('name') ? ' name=' + form.getAttribute('name') : '') +
+					' ' + form.method.toUpperCase() + ' ' + form.action + ref(form) + '\n';
+			} else {
+				out += 'outside any form\n';
+			}
+			out += controls.map(control).join('');
+		}
+		return out || 'No form controls.\n';
+	}
+
+	// markdown and text. Indentation that must survive the final whitespace
+	// cleanup (nested lists, <pre>) is written as \u0001.
+	const md = format === 'markdown';
+	const KEEP = '\u0001';
+	const block = s => '\n\n' + s.trim() + '\n\n';
+	// wrap puts inline markup around the text of raw, outside its spaces.
+	const wrap = (raw, open, close = open) => md ? raw.replace(/^(\s*)([\s\S]*?)(\s*)$/, (m, a, t, z) => t ? a + open + t + close + z : m) : raw;
+
+	const render = (node, list) => {
+		if (node.nodeType === 3) return node.textContent.replace(/\s+/g, ' ');
+		if (node.nodeType !== 1) return '';
+		const el = node, tag = el.localName;
+		if (SKIP.has(tag) || hidden(el)) return '';
+		const inner = (l = list) => kids(el).map(n => render(n, l)).join('');
+
+		if (/^h[1-6]$/.test(tag)) {
+			const text = clean(inner());
+			return text ? block((md ? '#'.repeat(+tag[1]) + ' ' : '') + text) : '';
+		}
+		switch (tag) {
+		case 'br':
+			return '\n';
+		case 'hr':
+			return md ? block('---') : '\n\n';
+		case 'a': {
+			const raw = inner();
+			return el.href && typeof el.href === 'string' && !el.href.startsWith('javascript:') ? wrap(raw, '[', '](' + el.href + ')') : raw;
+		}
+		case 'img': {
+			const alt = clean(el.alt);
+			return md && alt ? '![' + alt + '](' + (el.currentSrc || el.src) + ')' : alt;
+		}
+		case 'strong': case 'b':
+			return wrap(inner(), '**');
+		case 'em': case 'i':
+			return wrap(inner(), '_');
+		case 's': case 'del':
+			return wrap(inner(), '~~');
+		case 'code':
+			return wrap(inner(), '` + "`" + `');
+		case 'pre': {
+			const text = el.innerText.replace(/\n+$/, '').replace(/ /g, KEEP);
+			return text ? block(md ? '` + "```" + `\n' + text + '\n` + "```" + `' : text) : '';
+		}
+		case 'input':
+			return ['submit', 'button', 'reset'].includes(el.type) ? ' ' + el.value + ' ' : '';
+		case 'ul': case 'ol': {
+			// Items start their own line; only a top-level list is a block.
+			const items = inner({ordered: tag === 'ol', depth: list ? list.depth + 1 : 0, n: 0});
+			return list ? items : '\n' + items + '\n\n';
+		}
+		case 'li': {
+			const l = list || {ordered: false, depth: 0, n: 0};
+			const marker = md ? (l.ordered ? ++l.n + '. ' : '- ') : '';
+			const text = inner(l).trim().replace(/\n{2,}/g, '\n');
+			return text ? '\n' + KEEP.repeat(2 * l.depth) + marker + text : '';
+		}
+		case 'blockquote': {
+			const text = inner().trim().replace(/\n{3,}/g, '\n\n');
+			return block(md ? text.split('\n').map(line => '> ' + line.trim()).join('\n') : text);
+		}
+		case 'table': {
+			const rows = Array.from(el.rows)
+				.filter(r => !hidden(r))
+				.map(r => Array.from(r.cells).map(c => {
+					const text = clean(kids(c).map(n => render(n)).join(''));
+					return md ? text.replace(/\|/g, '\\|') : text;
+				}));
+			if (!rows.length) return '';
+			const width = Math.max(...rows.map(r => r.length));
+			const line = r => md ? '| ' + r.concat(Array(width - r.length).fill('')).join(' | ') + ' |' : r.join(' | ');
+			const lines = rows.map(line);
+			if (md) lines.splice(1, 0, '|' + ' --- |'.repeat(width));
+			return block(lines.join('\n'));
+		}
+		}
+
+		const display = el.ownerDocument.defaultView.getComputedStyle(el).display;
+		return display.startsWith('inline') || display === 'contents' ? inner() : block(inner());
+	};
+
+	return render(this)
+		.replace(/[ \t]+/g, ' ')
+		.replace(/ *\n */g, '\n')
+		.replace(/\n{3,}/g, '\n\n')
+		.trim()
+		.replace(/\u0001/g, ' ') + '\n';
+}`
+
+// contentFormatCall binds contentFormatJS to format, for callers that call a
+// function declaration without arguments.
+func contentFormatCall(format string) string {
+	return fmt.Sprintf("function() { return (%s).call(this, %q); }", contentFormatJS, format)
+}
+
+// structureMaxKeep is how many siblings of a run TruncateStructure keeps
+// before collapsing the rest, tried in order until the snapshot fits.
+var structureMaxKeep = []int{10, 5, 3, 1}
+
+// structureMaxLine is the length long lines are cut to once collapsing
+// repeated siblings is not enough.
+const structureMaxLine = 160
+
+// TruncateStructure fits a GetStructureJS snapshot into maxChars
+// characters (0 = no limit). Runs of siblings with the same tag and class,
+// like list rows, cards or table rows, keep their first rows and end in a
+// line such as "… 48 more <li>", keeping fewer rows until the snapshot
+// fits; then long lines are shortened, and what still does not fit is cut
+// at a line boundary with a note.
+func TruncateStructure(structure string, maxChars int) string {
+	if maxChars <= 0 || utf8.RuneCountInString(structure) <= maxChars {
+		return structure
+	}
+	root := parseStructure(structure)
+
+	var out string
+	for _, keep := range structureMaxKeep {
+		var sb strings.Builder
+		writeCollapsedStructure(&sb, root.children, 0, keep, 0)
+		if out = sb.String(); utf8.RuneCountInString(out) <= maxChars {
+			return out
+		}
+	}
+
+	var sb strings.Builder
+	writeCollapsedStructure(&sb, root.children, 0, 1, structureMaxLine)
+	return truncateContent(sb.String(), maxChars)
+}
+
+// writeCollapsedStructure writes nodes at depth, collapsing runs of more than
+// keep+1 similar siblings and cutting lines to maxLine characters (0 = no
+// limit).
+func writeCollapsedStructure(sb *strings.Builder, nodes []*structureNode, depth, keep, maxLine int) {
+	indent := strings.Repeat("  ", depth)
+	for i := 0; i < len(nodes); {
+		run := 1
+		for i+run < len(nodes) && structureShape(nodes[i+run].content) == structureShape(nodes[i].content) {
+			run++
+		}
+		shown := run
+		if run > keep+1 {
+			shown = keep
+		}
+		for _, n := range nodes[i : i+shown] {
+			line := n.line
+			if maxLine > 0 && utf8.RuneCountInString(line) > maxLine {
+				line = string([]rune(line)[:maxLine]) + "…"
+			}
+			sb.WriteString(indent + line + "\n")
+			writeCollapsedStructure(sb, n.children, depth+1, keep, maxLine)
+		}
+		if shown < run {
+			sb.WriteString(fmt.Sprintf("%s… %d more <%s>\n", indent, run-shown, structureTag(nodes[i].content)))
+		}
+		i += run
+	}
+}
+
+// structureShape is the tag and class of a snapshot line: what list rows
+// have in common while their ids and text differ.
+func structureShape(content string) string {
+	if !strings.HasPrefix(content, "<") {
+		return content
+	}
+	shape := structureTag(content)
+	if m := structureClass.FindStringSubmatch(content); m != nil {
+		shape += "." + m[1]
+	}
+	return shape
+}
+
+var structureTagName = regexp.MustCompile(`^<([^\s>]+)`)
+
+func structureTag(content string) string {
+	if m := structureTagName.FindStringSubmatch(content); m != nil {
+		return m[1]
+	}
+	return content
+}
+
+// truncateContent cuts content to maxChars characters (0 = no limit) at the
+// last line boundary that leaves room for a note saying it was cut.
+func truncateContent(content string, maxChars int) string {
+	if maxChars <= 0 || utf8.RuneCountInString(content) <= maxChars {
+		return content
+	}
+	note := fmt.Sprintf("… truncated to max_chars=%d; narrow it with selector or raise max_chars\n", maxChars)
+
+	// Byte offset of the first rune past the budget left by the note.
+	budget := max(maxChars-utf8.RuneCountInString(note), 0)
+	end, n := len(content), 0
+	for i := range content {
+		if n == budget {
+			end = i
+			break
+		}
+		n++
+	}
+	if nl := strings.LastIndexByte(content[:end], '\n'); nl >= 0 {
+		end = nl + 1
+	}
+	return content[:end] + note
+}
@@ -6,6 +6,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/tinywasm/devbrowser/cdproto/network"
+	"github.com/tinywasm/devbrowser/cdproto/target"
 	"github.com/tinywasm/devbrowser/chromedp"
 )
 
@@ -60,6 +62,20 @@ type DevBrowser struct {
 	// in an incomplete state until manual reload.
 	pendingReload bool
 
+	// softReload makes Reload keep the page state and ReloadForFile swap
+	// CSS in place; softReloadKeys are the storage keys it keeps (guarded by
+	// Mu).
+	softReload     bool
+	softReloadKeys []string
+
+	// environment is the emulated geolocation, timezone, locale and user
+	// agent, re-applied on every open (guarded by Mu).
+	environment Environment
+
+	// media is the emulated media type and preference features, re-applied
+	// on every open (guarded by Mu).
+	media Media
+
 	DB Store // Key-value store para configuración y estado
 
 	// chromedp fields
@@ -79,10 +95,23 @@ type DevBrowser struct {
 	// Console log capture
 	ConsoleLogs []string
 	LogsMutex   sync.Mutex
+	// Console errors with when they were logged, kept across navigations,
+	// and when the current page started (guarded by LogsMutex): what
+	// no_console_errors assertions check.
+	consoleErrors []consoleError
+	pageStart     time.Time
 
 	// Network log capture
 	NetworkLogs  []NetworkLogEntry
 	NetworkMutex sync.Mutex
+	// Requests sent and not finished yet, by id, and when one last started
+	// or ended (guarded by NetworkMutex): the state of network idle waits.
+	inflight        map[network.RequestID]string
+	networkActivity time.Time
+	// Failed requests (network errors and HTTP 4xx/5xx), kept across
+	// navigations unlike NetworkLogs (guarded by NetworkMutex): what
+	// no_failed_requests assertions and run summaries check.
+	failedRequests []NetworkLogEntry
 
 	// JS error capture
 	JsErrors    []JSError
@@ -93,6 +122,46 @@ type DevBrowser struct {
 	InterceptedReqs []InterceptedRequest
 	InterceptMutex  sync.Mutex
 
+	// JavaScript dialogs: the log and how they are answered (guarded by
+	// DialogMutex)
+	Dialogs         []DialogEntry
+	DialogMutex     sync.Mutex
+	dialogPolicy    DialogPolicy
+	dialogPolicyErr error // Invalid WithDialogPolicy, logged once the browser opens
+
+	// Set-of-marks snapshot of the last browser_screenshot with marks (guarded
+	// by Mu): ref N in click/fill resolves to the element ref of
+	// lastMarks[N-1], or to its selector while the page is still on
+	// lastMarksURL when no element ref could be assigned.
+	lastMarks    []pageMark
+	lastMarksURL string
+
+	// Element refs ("ref:e42") handed out by snapshots (guarded by Mu).
+	refs elementRefs
+
+	// Last browser_get_content snapshot and named checkpoints, the baselines
+	// of diff=true (guarded by Mu).
+	lastStructure structureSnapshot
+	checkpoints   map[string]structureSnapshot
+	// When each checkpoint was saved (guarded by Mu), the start of
+	// assertions on console errors and failed requests since it.
+	checkpointTimes map[string]time.Time
+
+	// Where the pointer tools last left the mouse (guarded by Mu), the start
+	// of their next path.
+	mouseX, mouseY float64
+
+	// Out-of-process iframes attached by syncFrameTargets (guarded by Mu),
+	// in attach order so parents come before their children.
+	frameTargets map[target.ID]*frameTarget
+	frameOrder   []target.ID
+
+	// Screencast recording (browser_record_start / browser_record_stop).
+	// Kept after an automatic max-duration stop until browser_record_stop
+	// collects the frames.
+	recording   *screencastRecording
+	RecordMutex sync.Mutex
+
 	// Operation busy flag (atomic) to prevent race conditions and UI blocking
 	// 0 = idle, 1 = busy
 	Busy int32
@@ -131,6 +200,7 @@ type NetworkLogEntry struct {
 	Duration  int64  // milliseconds
 	Failed    bool
 	ErrorText string
+	Timestamp time.Time // When the request was sent
 }
 
 /*
@@ -242,6 +312,7 @@ func (b *DevBrowser) Reload() error {
 	// (the about:blank "double window").
 	b.Mu.Lock()
 	ready := b.ready && b.Ctx != nil && b.IsOpenFlag
+	soft := b.softReload
 	if !ready {
 		if b.IsOpenFlag {
 			b.pendingReload = true
@@ -253,6 +324,14 @@ func (b *DevBrowser) Reload() error {
 	b.Mu.Unlock()
 
 	b.Logger("Reload")
+	if soft {
+		report, err := b.SoftReload()
+		if err != nil {
+			return errors.New("Reload " + err.Error())
+		}
+		b.Logger("Soft reload: " + report.String())
+		return nil
+	}
 	if err := chromedp.Run(b.Ctx, chromedp.Reload()); err != nil {
 		return errors.New("Reload " + err.Error())
 	}
@@ -355,3 +434,11 @@ func (b *DevBrowser) InitializeConsoleCapture() error {
 func (b *DevBrowser) InitializeInterceptCapture() {
 	b.initializeInterceptCapture()
 }
+
+func (b *DevBrowser) InitializeNetworkCapture() {
+	b.initializeNetworkCapture()
+}
+
+func (b *DevBrowser) InitializeDialogCapture() {
+	b.initializeDialogCapture()
+}
@@ -0,0 +1,212 @@
+package devbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tinywasm/devbrowser/cdproto/page"
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// maxDialogs caps the dialog log.
+const maxDialogs = 100
+
+// DialogPolicy is how JavaScript dialogs (alert, confirm, prompt and
+// beforeunload) are answered as they open. Unanswered, a dialog blocks
+// every action on the page until it times out.
+type DialogPolicy struct {
+	Action     string // accept (default), dismiss or manual (left open for HandleDialog)
+	PromptText string // Answer to prompt() when accepting; default its default value
+}
+
+// DialogEntry is a dialog the page opened and how it was answered.
+type DialogEntry struct {
+	Type          string // alert, confirm, prompt or beforeunload
+	Message       string
+	DefaultPrompt string // Default value of a prompt
+	URL           string // Frame that opened it
+	Response      string // accepted or dismissed; "" while open
+	UserInput     string // What a prompt was accepted with
+	Timestamp     time.Time
+}
+
+// String formats the entry for the dialog log.
+func (d DialogEntry) String() string {
+	s := fmt.Sprintf("%s %s(%q)", d.Timestamp.Format("15:04:05"), d.Type, d.Message)
+	switch {
+	case d.Response == "":
+		s += ": open"
+	case d.Type == "prompt" && d.Response == "accepted":
+		s += fmt.Sprintf(": accepted with %q", d.UserInput)
+	default:
+		s += ": " + d.Response
+	}
+	return s
+}
+
+// WithDialogPolicy sets how JavaScript dialogs are answered. An invalid
+// policy falls back to the default one; the error is logged when the
+// browser opens, as the logger is not set yet.
+func WithDialogPolicy(p DialogPolicy) Option {
+	return func(b *DevBrowser) {
+		if err := p.validate(); err != nil {
+			b.dialogPolicy = DialogPolicy{}
+			b.dialogPolicyErr = err
+			return
+		}
+		b.dialogPolicy = p
+		b.dialogPolicyErr = nil
+	}
+}
+
+// SetDialogPolicy sets how JavaScript dialogs are answered from now on.
+func (b *DevBrowser) SetDialogPolicy(p DialogPolicy) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	b.DialogMutex.Lock()
+	b.dialogPolicy = p
+	b.DialogMutex.Unlock()
+	return nil
+}
+
+// GetDialogPolicy returns how JavaScript dialogs are answered.
+func (b *DevBrowser) GetDialogPolicy() DialogPolicy {
+	b.DialogMutex.Lock()
+	defer b.DialogMutex.Unlock()
+	p := b.dialogPolicy
+	if p.Action == "" {
+		p.Action = "accept"
+	}
+	return p
+}
+
+func (p DialogPolicy) validate() error {
+	switch p.Action {
+	case "", "accept", "dismiss", "manual":
+		return nil
+	}
+	return fmt.Errorf("unsupported dialog policy: %s. Use accept, dismiss or manual", p.Action)
+}
+
+// GetDialogs returns the dialogs the page opened, oldest first.
+func (b *DevBrowser) GetDialogs() []DialogEntry {
+	b.DialogMutex.Lock()
+	defer b.DialogMutex.Unlock()
+	out := make([]DialogEntry, len(b.Dialogs))
+	copy(out, b.Dialogs)
+	return out
+}
+
+// openDialog returns the index of the dialog waiting for an answer, or -1.
+// The caller holds DialogMutex.
+func (b *DevBrowser) openDialog() int {
+	if n := len(b.Dialogs); n > 0 && b.Dialogs[n-1].Response == "" {
+		return n - 1
+	}
+	return -1
+}
+
+// HandleDialog answers the dialog that is open, for the manual policy.
+func (b *DevBrowser) HandleDialog(accept bool, promptText string) (DialogEntry, error) {
+	if b.Ctx == nil {
+		return DialogEntry{}, errors.New("context not initialized")
+	}
+	b.DialogMutex.Lock()
+	i := b.openDialog()
+	var d DialogEntry
+	if i >= 0 {
+		d = b.Dialogs[i]
+	}
+	b.DialogMutex.Unlock()
+	if i < 0 {
+		return d, errors.New("no dialog is open")
+	}
+
+	p := page.HandleJavaScriptDialog(accept)
+	if accept && d.Type == "prompt" {
+		if promptText == "" {
+			promptText = d.DefaultPrompt
+		}
+		p = p.WithPromptText(promptText)
+	}
+	ctx, cancel := context.WithTimeout(b.Ctx, 5*time.Second)
+	defer cancel()
+	if err := chromedp.Run(ctx, p); err != nil {
+		return d, fmt.Errorf("Error answering %s: %v", d.Type, err)
+	}
+
+	// The closed event fills in the response; report it even if it has
+	// not arrived yet.
+	d.Response = "dismissed"
+	if accept {
+		d.Response = "accepted"
+		d.UserInput = promptText
+	}
+	return d, nil
+}
+
+// initializeDialogCapture logs the dialogs the page opens and answers them
+// by the dialog policy.
+func (b *DevBrowser) initializeDialogCapture() {
+	b.DialogMutex.Lock()
+	b.Dialogs = []DialogEntry{}
+	policyErr := b.dialogPolicyErr
+	b.dialogPolicyErr = nil
+	b.DialogMutex.Unlock()
+	if policyErr != nil {
+		b.Logger(fmt.Sprintf("WithDialogPolicy: %v; dialogs are accepted", policyErr))
+	}
+
+	ctx := b.Ctx
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch ev := ev.(type) {
+		case *page.EventJavascriptDialogOpening:
+			b.DialogMutex.Lock()
+			b.Dialogs = append(b.Dialogs, DialogEntry{
+				Type:          string(ev.Type),
+				Message:       ev.Message,
+				DefaultPrompt: ev.DefaultPrompt,
+				URL:           ev.URL,
+				Timestamp:     time.Now(),
+			})
+			if len(b.Dialogs) > maxDialogs {
+				b.Dialogs = b.Dialogs[len(b.Dialogs)-maxDialogs:]
+			}
+			policy := b.dialogPolicy
+			b.DialogMutex.Unlock()
+
+			if policy.Action == "manual" {
+				return
+			}
+			accept := policy.Action != "dismiss"
+			p := page.HandleJavaScriptDialog(accept)
+			if accept && ev.Type == page.DialogTypePrompt {
+				text := policy.PromptText
+				if text == "" {
+					text = ev.DefaultPrompt
+				}
+				p = p.WithPromptText(text)
+			}
+			// Listeners must not block the event loop.
+			go func() {
+				if err := chromedp.Run(ctx, p); err != nil {
+					b.Logger(fmt.Sprintf("Failed to answer %s dialog: %v", ev.Type, err))
+				}
+			}()
+
+		case *page.EventJavascriptDialogClosed:
+			b.DialogMutex.Lock()
+			if i := b.openDialog(); i >= 0 {
+				b.Dialogs[i].Response = "dismissed"
+				if ev.Result {
+					b.Dialogs[i].Response = "accepted"
+					b.Dialogs[i].UserInput = ev.UserInput
+				}
+			}
+			b.DialogMutex.Unlock()
+		}
+	})
+}
@@ -0,0 +1,495 @@
+package devbrowser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tinywasm/devbrowser/cdproto/cdp"
+	"github.com/tinywasm/devbrowser/cdproto/dom"
+	"github.com/tinywasm/devbrowser/cdproto/page"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/cdproto/target"
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// refPrefix marks a selector argument as an element ref handed out by a
+// snapshot ("ref:e42") instead of a CSS selector.
+const refPrefix = "ref:"
+
+// refTargetsGlobal is where snapshot scripts park the elements that should
+// get a ref; collectRefTargets turns them into refs and deletes it.
+const refTargetsGlobal = "__devbrowserRefTargets"
+
+// refTargetsGroup is the runtime object group of the handles fetched by
+// collectRefTargets, released in one call once the refs are assigned.
+const refTargetsGroup = "devbrowser-ref-targets"
+
+// elementRef is the DOM node behind a ref, in the target that renders it.
+type elementRef struct {
+	target target.ID // Out-of-process frame, "" = the page
+	node   cdp.BackendNodeID
+}
+
+// elementRefs maps the refs shown in snapshots to DOM backend node ids.
+// Refs are numbered sequentially and a node keeps its ref across snapshots
+// of the same document. A navigation starts a new document: everything
+// below first belongs to an earlier one and is reported as stale.
+type elementRefs struct {
+	loader cdp.LoaderID // Main frame loader (document) the refs belong to
+	first  int
+	next   int
+	byRef  map[int]elementRef
+	byNode map[elementRef]int
+}
+
+// retire drops every ref while keeping the numbering, so old refs are stale
+// instead of silently pointing at whatever node gets their number next.
+func (r *elementRefs) retire() {
+	if r.next == 0 {
+		r.next = 1
+	}
+	r.loader = ""
+	r.first = r.next
+	r.byRef = nil
+	r.byNode = nil
+}
+
+// assignRefs returns the ref name ("e42") of each node of target tgt,
+// reusing the ref a node already has. loader identifies the document loaded
+// in the main frame; nodes of child frames live and die with it.
+func (b *DevBrowser) assignRefs(loader cdp.LoaderID, tgt target.ID, nodes []cdp.BackendNodeID) []string {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+
+	r := &b.refs
+	if r.byRef == nil || r.loader != loader {
+		r.retire()
+		r.loader = loader
+		r.byRef = make(map[int]elementRef)
+		r.byNode = make(map[elementRef]int)
+	}
+
+	names := make([]string, len(nodes))
+	for i, id := range nodes {
+		if id == 0 {
+			continue
+		}
+		key := elementRef{target: tgt, node: id}
+		n, ok := r.byNode[key]
+		if !ok {
+			n = r.next
+			r.next++
+			r.byRef[n] = key
+			r.byNode[key] = n
+		}
+		names[i] = "e" + strconv.Itoa(n)
+	}
+	return names
+}
+
+// nodeRefs names nodes of target tgt after the document currently loaded in
+// the main frame of the page ctx runs against.
+func (b *DevBrowser) nodeRefs(ctx context.Context, tgt target.ID, nodes []cdp.BackendNodeID) ([]string, error) {
+	var loader cdp.LoaderID
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		loader, err = mainLoaderID(ctx)
+		return err
+	})); err != nil {
+		return nil, err
+	}
+	return b.assignRefs(loader, tgt, nodes), nil
+}
+
+// isElementRef reports whether a selector argument is a "ref:eN" ref.
+func isElementRef(selector string) bool {
+	return strings.HasPrefix(selector, refPrefix)
+}
+
+// lookupRef parses "ref:eN" and returns the node and document it was
+// assigned to, without touching the browser.
+func (b *DevBrowser) lookupRef(selector string) (elementRef, cdp.LoaderID, error) {
+	name := strings.TrimPrefix(selector, refPrefix)
+	n, err := strconv.Atoi(strings.TrimPrefix(name, "e"))
+	if !strings.HasPrefix(name, "e") || err != nil || n < 1 {
+		return elementRef{}, "", fmt.Errorf("invalid ref %q: expected ref:e<number> from a snapshot", selector)
+	}
+
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+
+	ref, ok := b.refs.byRef[n]
+	switch {
+	case ok:
+		return ref, b.refs.loader, nil
+	case n < b.refs.first:
+		return elementRef{}, "", fmt.Errorf("ref %s is stale: the page navigated or the browser restarted since the snapshot; take a new snapshot", name)
+	default:
+		return elementRef{}, "", fmt.Errorf("unknown ref %s: refs come from browser_get_content, browser_get_accessibility_tree or browser_screenshot with marks", name)
+	}
+}
+
+// mainLoaderID identifies the document currently loaded in the main frame.
+func mainLoaderID(ctx context.Context) (cdp.LoaderID, error) {
+	tree, err := page.GetFrameTree().Do(ctx)
+	if err != nil {
+		return "", err
+	}
+	return tree.Frame.LoaderID, nil
+}
+
+// resolveRef checks that a ref still points at an element attached to the
+// current document and returns a handle to it. The caller releases it.
+func (b *DevBrowser) resolveRef(ctx context.Context, selector string) (*resolvedElement, error) {
+	ref, loader, err := b.lookupRef(selector)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimPrefix(selector, refPrefix)
+
+	var current cdp.LoaderID
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		current, err = mainLoaderID(ctx)
+		return err
+	})); err != nil {
+		return nil, fmt.Errorf("Failed to resolve ref %s: %v", name, err)
+	}
+	if current != loader {
+		return nil, elementMissing{fmt.Sprintf("ref %s is stale: the page navigated since the snapshot; take a new snapshot", name)}
+	}
+
+	stale := elementMissing{fmt.Sprintf("ref %s is stale: the element was removed from the page; take a new snapshot", name)}
+	tctx, err := b.targetContext(ctx, ref.target)
+	if err != nil {
+		return nil, stale
+	}
+	el := &resolvedElement{ctx: tctx, target: ref.target, node: ref.node}
+	err = chromedp.Run(tctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		obj, err := dom.ResolveNode().WithBackendNodeID(ref.node).Do(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil || obj.ObjectID == "" {
+			return stale
+		}
+		el.obj = obj
+		var connected bool
+		if err := chromedp.CallFunctionOn(`function() { return this.isConnected; }`, &connected, func(p *runtime.CallFunctionOnParams) *runtime.CallFunctionOnParams {
+			return p.WithObjectID(obj.ObjectID)
+		}).Do(ctx); ctx.Err() != nil {
+			return ctx.Err()
+		} else if err != nil || !connected {
+			return stale
+		}
+		return nil
+	}))
+	if err != nil {
+		el.release()
+		return nil, err
+	}
+	return el, nil
+}
+
+// elementQuery turns a selector argument into the selector and options of a
+// chromedp query: CSS selectors use by; refs and selector paths are resolved
+// first (chromedp would otherwise retry a dead node until the timeout) and
+// then matched by backend node id. Elements rendered by an out-of-process
+// frame cannot be queried through the page: remote is true and the caller
+// acts on them by page coordinates (elementCenter).
+func (b *DevBrowser) elementQuery(ctx context.Context, selector string, by chromedp.QueryOption) (sel string, opts []chromedp.QueryOption, remote bool, err error) {
+	if !isDeepSelector(selector) {
+		return selector, []chromedp.QueryOption{by}, false, nil
+	}
+
+	el, err := b.resolveElement(ctx, selector)
+	if err != nil {
+		return "", nil, false, err
+	}
+	el.release()
+	if el.target != "" {
+		return selector, nil, true, nil
+	}
+
+	id := el.node
+	return selector, []chromedp.QueryOption{chromedp.ByFunc(func(ctx context.Context, _ *cdp.Node) ([]cdp.NodeID, error) {
+		ids, err := dom.PushNodesByBackendIDsToFrontend([]cdp.BackendNodeID{id}).Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 || ids[0] == cdp.EmptyNodeID {
+			return []cdp.NodeID{}, nil
+		}
+		return ids, nil
+	})}, false, nil
+}
+
+// callOnElement calls the JS function declaration fn with this bound to the
+// element selector (CSS, ref or selector path) points at, storing the
+// returned value in res. It fails right away when nothing matches instead
+// of waiting.
+func (b *DevBrowser) callOnElement(ctx context.Context, selector, fn string, res any) error {
+	if isDeepSelector(selector) {
+		el, err := b.resolveElement(ctx, selector)
+		if err != nil {
+			return err
+		}
+		defer el.release()
+		return el.call(fn, res)
+	}
+
+	return chromedp.Run(ctx, chromedp.QueryAfter(selector, func(ctx context.Context, _ runtime.ExecutionContextID, nodes ...*cdp.Node) error {
+		if len(nodes) < 1 {
+			return elementMissing{"element not found: " + selector}
+		}
+		obj, err := dom.ResolveNode().WithNodeID(nodes[0].NodeID).Do(ctx)
+		if err != nil {
+			return err
+		}
+		defer runtime.ReleaseObject(obj.ObjectID).Do(ctx)
+		return chromedp.CallFunctionOn(fn, res, func(p *runtime.CallFunctionOnParams) *runtime.CallFunctionOnParams {
+			return p.WithObjectID(obj.ObjectID)
+		}).Do(ctx)
+	}, chromedp.ByQuery, chromedp.AtLeast(0)))
+}
+
+// callOnElementRefs is callOnElement for a script that marks elements with
+// ref placeholders, returning their refs. The targets are read from the
+// window of the element's document, so a selector path into an
+// out-of-process frame keeps its refs. Failing to assign refs only loses
+// the refs.
+func (b *DevBrowser) callOnElementRefs(ctx context.Context, selector, fn string, res any) ([]string, error) {
+	if !isDeepSelector(selector) {
+		if err := b.callOnElement(ctx, selector, fn, res); err != nil {
+			return nil, err
+		}
+		var refs []string
+		if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			refs, err = b.collectRefTargets(ctx)
+			return err
+		})); err != nil {
+			b.Logger(fmt.Sprintf("Warning: failed to assign element refs: %v", err))
+		}
+		return refs, nil
+	}
+
+	el, err := b.resolveElement(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	defer el.release()
+
+	if err := el.call(fn, res); err != nil {
+		return nil, err
+	}
+	nodes, err := el.refTargetNodes()
+	if err == nil {
+		var refs []string
+		if refs, err = b.nodeRefs(ctx, el.target, nodes); err == nil {
+			return refs, nil
+		}
+	}
+	b.Logger(fmt.Sprintf("Warning: failed to assign element refs: %v", err))
+	return nil, nil
+}
+
+// refTargetNodes returns the nodes a snapshot script left in
+// window.__devbrowserRefTargets of execution context world (0 = the page's
+// main world), in array order (0 for holes), and deletes the array.
+func refTargetNodes(ctx context.Context, world runtime.ExecutionContextID) ([]cdp.BackendNodeID, error) {
+	var nodes []cdp.BackendNodeID
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		eval := func(expr string) *runtime.EvaluateParams {
+			p := runtime.Evaluate(expr).WithObjectGroup(refTargetsGroup)
+			if world != 0 {
+				p = p.WithContextID(world)
+			}
+			return p
+		}
+		defer func() {
+			eval("delete window." + refTargetsGlobal).Do(ctx)
+			runtime.ReleaseObjectGroup(refTargetsGroup).Do(ctx)
+		}()
+
+		arr, exc, err := eval("window." + refTargetsGlobal).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exc != nil {
+			return exc
+		}
+		nodes, err = refArrayNodes(ctx, arr)
+		return err
+	}))
+	return nodes, err
+}
+
+// refArrayNodes describes the elements of a JS array handle, by index (0
+// for holes and non-nodes).
+func refArrayNodes(ctx context.Context, arr *runtime.RemoteObject) ([]cdp.BackendNodeID, error) {
+	if arr == nil || arr.ObjectID == "" {
+		return nil, nil
+	}
+	props, _, _, exc, err := runtime.GetProperties(arr.ObjectID).WithOwnProperties(true).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exc != nil {
+		return nil, exc
+	}
+
+	var nodes []cdp.BackendNodeID
+	for _, p := range props {
+		i, err := strconv.Atoi(p.Name)
+		if err != nil || p.Value == nil || p.Value.ObjectID == "" {
+			continue
+		}
+		n, err := dom.DescribeNode().WithObjectID(p.Value.ObjectID).Do(ctx)
+		if err != nil {
+			continue
+		}
+		for len(nodes) <= i {
+			nodes = append(nodes, 0)
+		}
+		nodes[i] = n.BackendNodeID
+	}
+	return nodes, nil
+}
+
+// refTargetNodes is the package-level refTargetNodes for a script called on
+// el, which leaves its targets in the window of el's document.
+func (e *resolvedElement) refTargetNodes() ([]cdp.BackendNodeID, error) {
+	var nodes []cdp.BackendNodeID
+	err := e.do(func(ctx context.Context) error {
+		defer runtime.ReleaseObjectGroup(refTargetsGroup).Do(ctx)
+		arr, exc, err := runtime.CallFunctionOn(`function() {
+			const targets = window.` + refTargetsGlobal + `;
+			delete window.` + refTargetsGlobal + `;
+			return targets;
+		}`).WithObjectID(e.obj.ObjectID).WithObjectGroup(refTargetsGroup).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exc != nil {
+			return exc
+		}
+		nodes, err = refArrayNodes(ctx, arr)
+		return err
+	})
+	return nodes, err
+}
+
+// collectRefTargets assigns refs to the elements a snapshot script left in
+// window.__devbrowserRefTargets of the page, in array order ("" for holes).
+func (b *DevBrowser) collectRefTargets(ctx context.Context) ([]string, error) {
+	nodes, err := refTargetNodes(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	return b.nodeRefs(ctx, "", nodes)
+}
+
+// collectFrameRefTargets is collectRefTargets for a script evaluated in the
+// isolated world of a child frame.
+func (b *DevBrowser) collectFrameRefTargets(ctx context.Context, f pageFrame) ([]string, error) {
+	nodes, err := refTargetNodes(f.ctx, f.world)
+	if err != nil {
+		return nil, err
+	}
+	return b.nodeRefs(ctx, f.target, nodes)
+}
+
+// refPlaceholder matches the " ref:@N" markers GetStructureJS writes for
+// the Nth element of window.__devbrowserRefTargets.
+var refPlaceholder = regexp.MustCompile(` ref:@(\d+)`)
+
+// ReplaceRefPlaceholders swaps the placeholders of a GetStructureJS snapshot
+// for the refs collected from the same run; placeholders without a ref are
+// dropped.
+func ReplaceRefPlaceholders(structure string, refs []string) string {
+	return refPlaceholder.ReplaceAllStringFunc(structure, func(m string) string {
+		i, _ := strconv.Atoi(m[len(" ref:@"):])
+		if i < len(refs) && refs[i] != "" {
+			return " " + refPrefix + refs[i]
+		}
+		return ""
+	})
+}
+
+// pageStructure evaluates GetStructureJS and gives its interactive
+// elements refs, then appends the frames the script cannot walk into.
+// Failing to assign refs only loses the refs.
+func (b *DevBrowser) pageStructure(ctx context.Context) (string, error) {
+	return b.pageSnapshot(ctx, GetStructureJS)
+}
+
+// pageSnapshot evaluates a snapshot script that marks elements with ref
+// placeholders (GetStructureJS, a content format) on the page and in each
+// frame it cannot walk into, and resolves the placeholders to refs.
+func (b *DevBrowser) pageSnapshot(ctx context.Context, expr string) (string, error) {
+	var snapshot string
+	if err := chromedp.Run(ctx, chromedp.Evaluate(expr, &snapshot)); err != nil {
+		return "", err
+	}
+	refs, err := b.collectRefTargets(ctx)
+	if err != nil {
+		b.Logger(fmt.Sprintf("Warning: failed to assign element refs: %v", err))
+	}
+	return ReplaceRefPlaceholders(snapshot, refs) + b.frameSnapshots(ctx, expr), nil
+}
+
+// elementSnapshot is pageSnapshot for the function declaration fn called on
+// the element of a selector argument, without other frames.
+func (b *DevBrowser) elementSnapshot(ctx context.Context, selector, fn string) (string, error) {
+	el, err := b.resolveElement(ctx, selector)
+	if err != nil {
+		return "", err
+	}
+	defer el.release()
+
+	var snapshot string
+	if err := el.call(fn, &snapshot); err != nil {
+		return "", err
+	}
+	nodes, err := el.refTargetNodes()
+	if err == nil {
+		var refs []string
+		if refs, err = b.nodeRefs(ctx, el.target, nodes); err == nil {
+			return ReplaceRefPlaceholders(snapshot, refs), nil
+		}
+	}
+	b.Logger(fmt.Sprintf("Warning: failed to assign element refs: %v", err))
+	return ReplaceRefPlaceholders(snapshot, nil), nil
+}
+
+// frameSnapshots renders each cross-origin or out-of-process frame as its
+// own section, evaluating the snapshot script inside the frame.
+func (b *DevBrowser) frameSnapshots(ctx context.Context, expr string) string {
+	frames, err := b.childFrames(ctx)
+	if err != nil {
+		b.Logger(fmt.Sprintf("Warning: failed to list frames: %v", err))
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, f := range frames {
+		if f.reachable {
+			continue
+		}
+		var snapshot string
+		if err := f.evaluate(expr, &snapshot); err != nil {
+			b.Logger(fmt.Sprintf("Warning: failed to read frame %s: %v", f.frame.URL, err))
+			continue
+		}
+		refs, err := b.collectFrameRefTargets(ctx, f)
+		if err != nil {
+			b.Logger(fmt.Sprintf("Warning: failed to assign element refs in frame %s: %v", f.frame.URL, err))
+		}
+		sb.WriteString("\n--- " + b.frameLabel(ctx, f) + " ---\n")
+		sb.WriteString(ReplaceRefPlaceholders(snapshot, refs))
+	}
+	return sb.String()
+}
@@ -0,0 +1,289 @@
+package devbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tinywasm/devbrowser/cdproto/browser"
+	"github.com/tinywasm/devbrowser/cdproto/emulation"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/devbrowser/chromedp/device"
+)
+
+// Environment is what the page sees of where and by whom it is browsed, on
+// top of the device emulation. Empty fields keep the real value.
+type Environment struct {
+	Geolocation       string `json:"geolocation,omitempty"`         // "lat,lon" or "lat,lon,accuracy" (meters), or unavailable
+	Timezone          string `json:"timezone,omitempty"`            // IANA id, e.g. Europe/Madrid
+	Locale            string `json:"locale,omitempty"`              // e.g. es-ES, for Intl and formatting
+	AcceptLanguage    string `json:"accept_language,omitempty"`     // Accept-Language and navigator.languages; default from Locale
+	UserAgent         string `json:"user_agent,omitempty"`          // Replaces the UA of the device emulation too
+	UAPlatform        string `json:"ua_platform,omitempty"`         // Client hints (Sec-CH-UA-*), sent when set: Android, macOS, Windows...
+	UAPlatformVersion string `json:"ua_platform_version,omitempty"` //
+	UAModel           string `json:"ua_model,omitempty"`            //
+	UAMobile          bool   `json:"ua_mobile,omitempty"`           //
+}
+
+var (
+	localeRe   = regexp.MustCompile(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]{2,8})*$`)
+	timezoneRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_+-]*(/[A-Za-z0-9_+-]+)*$`)
+	chromeRe   = regexp.MustCompile(`Chrome/(\d+)`)
+)
+
+// geolocation parses Geolocation. Without a position, it is unavailable.
+func (e Environment) geolocation() (lat, lon, accuracy float64, ok bool, err error) {
+	if e.Geolocation == "unavailable" {
+		return 0, 0, 0, false, nil
+	}
+	parts := strings.Split(e.Geolocation, ",")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, 0, 0, false, fmt.Errorf("invalid geolocation: %s. Use lat,lon or lat,lon,accuracy (e.g. 40.4168,-3.7038) or unavailable", e.Geolocation)
+	}
+	nums := make([]float64, 3)
+	nums[2] = 100
+	for i, p := range parts {
+		n, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return 0, 0, 0, false, fmt.Errorf("invalid geolocation: %s. Use lat,lon or lat,lon,accuracy (e.g. 40.4168,-3.7038) or unavailable", e.Geolocation)
+		}
+		nums[i] = n
+	}
+	if nums[0] < -90 || nums[0] > 90 || nums[1] < -180 || nums[1] > 180 || nums[2] <= 0 {
+		return 0, 0, 0, false, fmt.Errorf("geolocation out of range: %s. Latitude is -90 to 90, longitude -180 to 180 and accuracy positive", e.Geolocation)
+	}
+	return nums[0], nums[1], nums[2], true, nil
+}
+
+func (e Environment) validate() error {
+	if e.Geolocation != "" {
+		if _, _, _, _, err := e.geolocation(); err != nil {
+			return err
+		}
+	}
+	if e.Timezone != "" && !timezoneRe.MatchString(e.Timezone) {
+		return fmt.Errorf("invalid timezone: %s. Use an IANA id like Europe/Madrid or America/New_York", e.Timezone)
+	}
+	if e.Locale != "" && !localeRe.MatchString(e.Locale) {
+		return fmt.Errorf("invalid locale: %s. Use a language tag like es-ES or pt-BR", e.Locale)
+	}
+	if e.UAPlatform == "" && (e.UAPlatformVersion != "" || e.UAModel != "" || e.UAMobile) {
+		return errors.New("client hints need ua_platform")
+	}
+	return nil
+}
+
+// acceptLanguage is AcceptLanguage, or one derived from Locale: es-ES
+// gives "es-ES,es;q=0.9".
+func (e Environment) acceptLanguage() string {
+	if e.AcceptLanguage != "" || e.Locale == "" {
+		return e.AcceptLanguage
+	}
+	tag := strings.ReplaceAll(e.Locale, "_", "-")
+	lang, _, found := strings.Cut(tag, "-")
+	if !found {
+		return tag
+	}
+	return tag + "," + lang + ";q=0.9"
+}
+
+// clientHints builds the client hints of ua, with brands from its Chrome
+// version.
+func (e Environment) clientHints(ua string) *emulation.UserAgentMetadata {
+	m := &emulation.UserAgentMetadata{
+		Platform:        e.UAPlatform,
+		PlatformVersion: e.UAPlatformVersion,
+		Model:           e.UAModel,
+		Mobile:          e.UAMobile,
+	}
+	if v := chromeRe.FindStringSubmatch(ua); v != nil {
+		m.Brands = []*emulation.UserAgentBrandVersion{
+			{Brand: "Chromium", Version: v[1]},
+			{Brand: "Google Chrome", Version: v[1]},
+			{Brand: "Not-A.Brand", Version: "99"},
+		}
+	}
+	return m
+}
+
+// String lists the emulated settings, or says there are none.
+func (e Environment) String() string {
+	var parts []string
+	if e.Geolocation != "" {
+		parts = append(parts, "geolocation "+e.Geolocation)
+	}
+	if e.Timezone != "" {
+		parts = append(parts, "timezone "+e.Timezone)
+	}
+	if e.Locale != "" {
+		parts = append(parts, "locale "+e.Locale)
+	}
+	if lang := e.acceptLanguage(); lang != "" {
+		parts = append(parts, "Accept-Language "+lang)
+	}
+	if e.UserAgent != "" {
+		parts = append(parts, fmt.Sprintf("user agent %q", e.UserAgent))
+	}
+	if e.UAPlatform != "" {
+		hints := []string{e.UAPlatform}
+		if e.UAPlatformVersion != "" {
+			hints[0] += " " + e.UAPlatformVersion
+		}
+		if e.UAModel != "" {
+			hints = append(hints, e.UAModel)
+		}
+		if e.UAMobile {
+			hints = append(hints, "mobile")
+		}
+		parts = append(parts, "client hints "+strings.Join(hints, ", "))
+	}
+	if len(parts) == 0 {
+		return "no environment emulation"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SetEnvironment emulates env from now on, replacing the environment set
+// before. It is persisted in the Store and applied at once when the browser
+// is open; a setting the browser rejects is neither applied nor saved.
+func (b *DevBrowser) SetEnvironment(env Environment) error {
+	if err := env.validate(); err != nil {
+		return err
+	}
+	b.Mu.Lock()
+	prev := b.environment
+	b.environment = env
+	b.Mu.Unlock()
+
+	if b.IsOpen() && b.Ctx != nil {
+		if err := b.applyEnvironment(); err != nil {
+			b.Mu.Lock()
+			b.environment = prev
+			b.Mu.Unlock()
+			if err := b.applyEnvironment(); err != nil {
+				b.Logger(fmt.Sprintf("Failed to restore environment emulation: %v", err))
+			}
+			return fmt.Errorf("Error applying environment: %v", err)
+		}
+	}
+	if err := b.SaveConfig(); err != nil {
+		b.Logger(fmt.Sprintf("Error saving environment config: %v", err))
+	}
+	return nil
+}
+
+// GetEnvironment returns the emulated environment.
+func (b *DevBrowser) GetEnvironment() Environment {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+	return b.environment
+}
+
+// applyEnvironment applies the persisted environment.
+func (b *DevBrowser) applyEnvironment() error {
+	b.Mu.Lock()
+	env := b.environment
+	deviceUA := deviceUserAgent(b.ViewportMode, b.ViewportDevice)
+	b.Mu.Unlock()
+	return chromedp.Run(b.Ctx, environmentActions(env, deviceUA)...)
+}
+
+// environmentActions builds the CDP commands that emulate env. Overrides
+// are cleared before they are set, as Chrome refuses to replace a locale
+// override.
+func environmentActions(env Environment, deviceUA string) []chromedp.Action {
+	var actions []chromedp.Action
+	if env.Geolocation == "" {
+		actions = append(actions, emulation.ClearGeolocationOverride())
+	} else {
+		lat, lon, accuracy, ok, _ := env.geolocation()
+		if ok {
+			actions = append(actions,
+				emulation.SetGeolocationOverride().WithLatitude(lat).WithLongitude(lon).WithAccuracy(accuracy),
+				chromedp.ActionFunc(func(ctx context.Context) error {
+					// Without the permission the page gets a prompt, which
+					// automation cannot answer; not every session may grant it.
+					_ = browser.GrantPermissions([]browser.PermissionType{browser.PermissionTypeGeolocation}).Do(ctx)
+					return nil
+				}),
+			)
+		} else {
+			actions = append(actions, emulation.SetGeolocationOverride())
+		}
+	}
+
+	actions = append(actions, emulation.SetTimezoneOverride(""))
+	if env.Timezone != "" {
+		actions = append(actions, emulation.SetTimezoneOverride(env.Timezone))
+	}
+	actions = append(actions, emulation.SetLocaleOverride())
+	if env.Locale != "" {
+		actions = append(actions, emulation.SetLocaleOverride().WithLocale(strings.ReplaceAll(env.Locale, "-", "_")))
+	}
+	return append(actions, userAgentAction(env, deviceUA))
+}
+
+// userAgentAction sets the user agent, Accept-Language and client hints of
+// env on top of the device emulation, whose UA is deviceUA ("" for the
+// browser's own). Device emulation resets the UA, so it runs after it too.
+func userAgentAction(env Environment, deviceUA string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		lang := env.acceptLanguage()
+		if env.UserAgent == "" && lang == "" && env.UAPlatform == "" {
+			return emulation.SetUserAgentOverride(deviceUA).Do(ctx)
+		}
+		ua := env.UserAgent
+		if ua == "" {
+			ua = deviceUA
+		}
+		if ua == "" {
+			_, _, _, real, _, err := browser.GetVersion().Do(ctx)
+			if err != nil {
+				return err
+			}
+			ua = real
+		}
+		p := emulation.SetUserAgentOverride(ua)
+		if lang != "" {
+			p = p.WithAcceptLanguage(lang)
+		}
+		if env.UAPlatform != "" {
+			p = p.WithUserAgentMetadata(env.clientHints(ua))
+		}
+		return p.Do(ctx)
+	})
+}
+
+// deviceUserAgent is the UA the device emulation of mode or devName sets,
+// or "" when it keeps the browser's own.
+func deviceUserAgent(mode, devName string) string {
+	if devName != "" {
+		if d, _, err := resolveDevice(devName); err == nil {
+			return d.Device().UserAgent
+		}
+		return ""
+	}
+	switch mode {
+	case "mobile":
+		return device.IPhone15ProMax.Device().UserAgent
+	case "tablet":
+		return device.IPadPro.Device().UserAgent
+	}
+	return ""
+}
+
+// encodeEnvironment is the Store value of env, "" when nothing is emulated.
+func encodeEnvironment(env Environment) string {
+	if env == (Environment{}) {
+		return ""
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,562 @@
+package devbrowser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tinywasm/devbrowser/cdproto/dom"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// selectFieldJS focuses a form field and selects its content, so the next
+// key replaces it. It returns whether there was anything to select.
+const selectFieldJS = `function() {
+	this.focus();
+	if (this.isContentEditable) {
+		const range = document.createRange();
+		range.selectNodeContents(this);
+		const sel = getSelection();
+		sel.removeAllRanges();
+		sel.addRange(range);
+		return this.textContent !== '';
+	}
+	if (typeof this.select === 'function') this.select();
+	return this.value !== undefined && this.value !== '';
+}`
+
+// emptyFieldJS empties a field the Backspace after selectFieldJS left with
+// a value (input types without text selection, like number or date),
+// firing the events typing would.
+const emptyFieldJS = `function() {
+	const empty = this.isContentEditable ? this.textContent === '' : !this.value;
+	if (empty) return;
+	if (this.isContentEditable) this.textContent = '';
+	else this.value = '';
+	this.dispatchEvent(new Event('input', {bubbles: true}));
+	this.dispatchEvent(new Event('change', {bubbles: true}));
+}`
+
+// selectOptionJS selects the options of a <select> matching values by value
+// or by label (by = value, label or auto: value first, then label) and
+// fires input and change. It returns the labels of the selected options.
+const selectOptionJS = `function(values, by) {
+	if (this.tagName !== 'SELECT') throw new Error('not a <select>: ' + this.tagName.toLowerCase());
+	if (values.length > 1 && !this.multiple) throw new Error('only <select multiple> takes several values');
+	const opts = Array.from(this.options);
+	const label = o => (o.label || o.textContent).trim();
+	const picked = values.map(v => {
+		let o;
+		if (by !== 'label') o = opts.find(o => o.value === v);
+		if (!o && by !== 'value') o = opts.find(o => label(o) === v) || opts.find(o => label(o).toLowerCase() === v.trim().toLowerCase());
+		if (!o) throw new Error('no option ' + JSON.stringify(v) + '; options are ' + opts.map(o => o.value === label(o) ? JSON.stringify(o.value) : JSON.stringify(o.value) + ' (' + label(o) + ')').join(', '));
+		if (o.disabled) throw new Error('option ' + JSON.stringify(v) + ' is disabled');
+		return o;
+	});
+	this.focus();
+	for (const o of opts) o.selected = picked.includes(o);
+	this.dispatchEvent(new Event('input', {bubbles: true}));
+	this.dispatchEvent(new Event('change', {bubbles: true}));
+	return picked.map(label);
+}`
+
+// fieldKindJS describes a form field: checkbox, radio, file, select,
+// select-multiple or text (anything typed into).
+const fieldKindJS = `function() {
+	if (this.tagName === 'SELECT') return this.multiple ? 'select-multiple' : 'select';
+	if (this.tagName === 'INPUT' && ['checkbox', 'radio', 'file'].includes(this.type)) return this.type;
+	return 'text';
+}`
+
+// checkedStateJS returns whether a checkbox or radio is checked, failing on
+// anything else.
+const checkedStateJS = `function() {
+	if (this.tagName !== 'INPUT' || (this.type !== 'checkbox' && this.type !== 'radio')) throw new Error('not a checkbox or radio: ' + this.tagName.toLowerCase() + (this.type ? '[type=' + this.type + ']' : ''));
+	return this.checked;
+}`
+
+// clickCheckedJS clicks a checkbox or radio in JS, for controls a real click
+// cannot reach (hidden behind a styled label), and returns the new state.
+const clickCheckedJS = `function() { this.click(); return this.checked; }`
+
+// checkRadioJS checks the radio of the group of this radio whose value or
+// label is value, with a JS click, and returns its value.
+const checkRadioJS = `function(value) {
+	if (this.tagName !== 'INPUT' || this.type !== 'radio') throw new Error('not a radio');
+	const root = this.form || this.getRootNode();
+	const group = Array.from(root.querySelectorAll('input[type=radio]')).filter(r => r.name === this.name && (r.form || r.getRootNode()) === root);
+	const label = r => Array.from(r.labels || []).map(l => l.textContent.trim()).join(' ');
+	const r = group.find(r => r.value === value) || group.find(r => label(r).toLowerCase() === value.trim().toLowerCase());
+	if (!r) throw new Error('no radio ' + JSON.stringify(value) + ' in group ' + JSON.stringify(this.name) + '; values are ' + group.map(r => JSON.stringify(r.value)).join(', '));
+	if (r.disabled) throw new Error('radio ' + JSON.stringify(value) + ' is disabled');
+	if (!r.checked) r.click();
+	return r.value;
+}`
+
+// fileInputJS checks that an element takes count files.
+const fileInputJS = `function(count) {
+	if (this.tagName !== 'INPUT' || this.type !== 'file') throw new Error('not a file input: ' + this.tagName.toLowerCase() + (this.type ? '[type=' + this.type + ']' : ''));
+	if (count > 1 && !this.multiple) throw new Error('only <input type=file multiple> takes several files');
+	if (this.disabled) throw new Error('file input is disabled');
+}`
+
+// fieldValidityJS reports a field's value as the form would submit it and
+// its constraint validation.
+const fieldValidityJS = `function() {
+	let value;
+	if (this.tagName === 'SELECT') value = Array.from(this.selectedOptions).map(o => o.value).join(', ');
+	else if (this.type === 'checkbox' || this.type === 'radio') value = this.checked ? 'checked' : 'unchecked';
+	else if (this.type === 'file') value = Array.from(this.files).map(f => f.name).join(', ');
+	else if (this.type === 'password') value = this.value.replace(/./g, '*');
+	else value = this.isContentEditable ? this.textContent : this.value;
+	return {value: value === undefined ? '' : String(value), valid: this.validity ? this.validity.valid : true, message: this.validationMessage || ''};
+}`
+
+// fieldValidity is the result of fieldValidityJS.
+type fieldValidity struct {
+	Value   string `json:"value"`
+	Valid   bool   `json:"valid"`
+	Message string `json:"message"`
+}
+
+// callField is callOnElement with the errors thrown by the field scripts
+// reduced to their message.
+func (b *DevBrowser) callField(ctx context.Context, selector, fn string, res any) error {
+	err := b.callOnElement(ctx, selector, fn, res)
+	var exc *runtime.ExceptionDetails
+	if errors.As(err, &exc) && exc.Exception != nil {
+		msg, _, _ := strings.Cut(exc.Exception.Description, "\n")
+		return errors.New(strings.TrimPrefix(msg, "Error: "))
+	}
+	return err
+}
+
+// clearField empties a text field the way a user does: focus, select all,
+// Backspace. The element must be focusable by the page already (remote
+// frames take focus by a click).
+func (b *DevBrowser) clearField(ctx context.Context, selector string) error {
+	var filled bool
+	if err := b.callField(ctx, selector, selectFieldJS, &filled); err != nil {
+		return err
+	}
+	if !filled {
+		return nil
+	}
+	backspace, _ := parseKeyChord("Backspace")
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		for _, e := range backspace.events() {
+			if err := e.Do(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+	return b.callField(ctx, selector, emptyFieldJS, nil)
+}
+
+// typeInto types value into the field of a selector argument as real key
+// events, replacing its content when clear is set.
+func (b *DevBrowser) typeInto(ctx context.Context, selector, value string, clear bool) error {
+	sel, qopts, remote, err := b.elementQuery(ctx, selector, chromedp.ByQuery)
+	if err != nil {
+		return err
+	}
+
+	if remote {
+		// Out-of-process frame: focus by clicking, then type through the
+		// page, which routes keys to the focused frame.
+		if err := b.clickRemoteElement(ctx, selector); err != nil {
+			return err
+		}
+		if clear {
+			if err := b.clearField(ctx, selector); err != nil {
+				return err
+			}
+		}
+		return chromedp.Run(ctx, chromedp.KeyEvent(value))
+	}
+
+	if err := chromedp.Run(ctx, chromedp.WaitVisible(sel, qopts...)); err != nil {
+		return err
+	}
+	if clear {
+		if err := b.clearField(ctx, selector); err != nil {
+			return err
+		}
+	}
+	return chromedp.Run(ctx, chromedp.SendKeys(sel, value, qopts...))
+}
+
+// selectBy validates the by argument of browser_select_option.
+func selectBy(by string) (string, error) {
+	switch by {
+	case "":
+		return "auto", nil
+	case "auto", "value", "label":
+		return by, nil
+	}
+	return "", fmt.Errorf("unsupported by: %s. Use auto, value or label", by)
+}
+
+// selectOptions selects the options of the <select> of a selector argument
+// by value or label (see selectBy) and returns their labels.
+func (b *DevBrowser) selectOptions(ctx context.Context, selector string, values []string, by string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("value required: the value or label of an option")
+	}
+	if err := b.waitField(ctx, selector); err != nil {
+		return nil, err
+	}
+
+	fn, err := bindArgs(selectOptionJS, values, by)
+	if err != nil {
+		return nil, err
+	}
+	var labels []string
+	err = b.callField(ctx, selector, fn, &labels)
+	return labels, err
+}
+
+// setChecked checks or unchecks the checkbox or radio of a selector argument
+// with a real click, falling back to a JS click when the click misses it (a
+// hidden input styled through its label). It reports whether the state
+// changed.
+func (b *DevBrowser) setChecked(ctx context.Context, selector string, checked bool) (bool, error) {
+	if err := b.waitField(ctx, selector); err != nil {
+		return false, err
+	}
+	var state bool
+	if err := b.callField(ctx, selector, checkedStateJS, &state); err != nil {
+		return false, err
+	}
+	if state == checked {
+		return false, nil
+	}
+	if !checked {
+		var radio bool
+		b.callField(ctx, selector, `function() { return this.type === 'radio'; }`, &radio)
+		if radio {
+			return false, fmt.Errorf("a radio cannot be unchecked: check another radio of its group")
+		}
+	}
+
+	sel, qopts, remote, err := b.elementQuery(ctx, selector, chromedp.ByQuery)
+	if err != nil {
+		return false, err
+	}
+	var visible bool
+	b.callField(ctx, selector, `function() { const r = this.getBoundingClientRect(); return r.width > 0 && r.height > 0 && getComputedStyle(this).visibility !== 'hidden'; }`, &visible)
+	if visible {
+		if remote {
+			err = b.clickRemoteElement(ctx, selector)
+		} else {
+			err = chromedp.Run(ctx, chromedp.Click(sel, qopts...))
+		}
+		if err != nil {
+			return false, err
+		}
+		if err := b.callField(ctx, selector, checkedStateJS, &state); err != nil {
+			return false, err
+		}
+	}
+	if state != checked {
+		if err := b.callField(ctx, selector, clickCheckedJS, &state); err != nil {
+			return false, err
+		}
+	}
+	if state != checked {
+		return false, fmt.Errorf("the element did not change state; a click handler may prevent it")
+	}
+	return true, nil
+}
+
+// checkRadio checks the radio whose value or label is value in the group of
+// the radio of a selector argument, and returns its value.
+func (b *DevBrowser) checkRadio(ctx context.Context, selector, value string) (string, error) {
+	if err := b.waitField(ctx, selector); err != nil {
+		return "", err
+	}
+	fn, err := bindArgs(checkRadioJS, value)
+	if err != nil {
+		return "", err
+	}
+	var checked string
+	err = b.callField(ctx, selector, fn, &checked)
+	return checked, err
+}
+
+// uploadFiles sets the files of the file input of a selector argument, as
+// if picked in the file chooser. Chrome fires input and change. paths come
+// from validateUploadPaths.
+func (b *DevBrowser) uploadFiles(ctx context.Context, selector string, paths []string) error {
+	if err := b.waitField(ctx, selector); err != nil {
+		return err
+	}
+	fn, err := bindArgs(fileInputJS, len(paths))
+	if err != nil {
+		return err
+	}
+	if err := b.callField(ctx, selector, fn, nil); err != nil {
+		return err
+	}
+
+	el, err := b.resolveElement(ctx, selector)
+	if err != nil {
+		return err
+	}
+	defer el.release()
+	return el.do(func(ctx context.Context) error {
+		return dom.SetFileInputFiles(paths).WithBackendNodeID(el.node).Do(ctx)
+	})
+}
+
+// waitField waits for the element of a plain CSS selector to be in the
+// DOM; refs and selector paths are resolved right away.
+func (b *DevBrowser) waitField(ctx context.Context, selector string) error {
+	if isDeepSelector(selector) {
+		return nil
+	}
+	return chromedp.Run(ctx, chromedp.WaitReady(selector, chromedp.ByQuery))
+}
+
+// validateUploadPaths validates the paths of files to upload and returns
+// them absolute.
+func validateUploadPaths(files []string) ([]string, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("files required: one path per line")
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		p, err := validateUploadPath(f)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = p
+	}
+	return paths, nil
+}
+
+// validateUploadPath cleans a file path given for upload and resolves it to
+// an absolute path, rejecting ".." segments and anything that is not an
+// existing regular file.
+func validateUploadPath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", fmt.Errorf("empty file path")
+	}
+
+	// Reject any segment of ".." before cleaning folds it away
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == ".." {
+			return "", fmt.Errorf("path traversal (..) is not allowed: %s", path)
+		}
+	}
+
+	abs, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve absolute path for %s: %v", path, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", fmt.Errorf("cannot upload %s: %v", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("cannot upload %s: not a regular file", path)
+	}
+	return abs, nil
+}
+
+// bindArgs turns a JS function declaration taking arguments into one taking
+// none, with args JSON-encoded in, for callOnElement.
+func bindArgs(fn string, args ...any) (string, error) {
+	enc := make([]string, len(args))
+	for i, a := range args {
+		b, err := json.Marshal(a)
+		if err != nil {
+			return "", err
+		}
+		enc[i] = string(b)
+	}
+	return "function() { return (" + fn + ").call(this, " + strings.Join(enc, ", ") + "); }", nil
+}
+
+// splitLines splits a multi-line argument into its trimmed, non-empty lines.
+func splitLines(s string) []string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// formField is one entry of a browser_fill_form fields object: a field name
+// or selector argument and the value to give it.
+type formField struct {
+	key   string
+	value any // string, bool or []string
+}
+
+// parseFormFields decodes the JSON object of browser_fill_form, keeping the
+// order of its keys, which is the order fields are filled in. Values are
+// strings (numbers are taken as typed), booleans for checkboxes and radios
+// or arrays of strings for <select multiple> and file inputs.
+func parseFormFields(fields string) ([]formField, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(fields)))
+	dec.UseNumber()
+	invalid := func(err error) error {
+		return fmt.Errorf("invalid fields: %v. Use a JSON object like {\"email\": \"a@b.c\", \"#terms\": true}", err)
+	}
+
+	if tok, err := dec.Token(); err != nil {
+		return nil, invalid(err)
+	} else if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return nil, invalid(fmt.Errorf("not an object"))
+	}
+
+	var out []formField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, invalid(err)
+		}
+		key := strings.TrimSpace(tok.(string))
+		if key == "" {
+			return nil, invalid(fmt.Errorf("empty field name"))
+		}
+
+		var raw any
+		if err := dec.Decode(&raw); err != nil {
+			return nil, invalid(err)
+		}
+		f := formField{key: key}
+		switch v := raw.(type) {
+		case string, bool:
+			f.value = v
+		case json.Number:
+			f.value = v.String()
+		case []any:
+			list := make([]string, len(v))
+			for i, item := range v {
+				switch s := item.(type) {
+				case string:
+					list[i] = s
+				case json.Number:
+					list[i] = s.String()
+				default:
+					return nil, invalid(fmt.Errorf("%s: arrays hold strings", key))
+				}
+			}
+			f.value = list
+		default:
+			return nil, invalid(fmt.Errorf("%s: use a string, number, boolean or array of strings", key))
+		}
+		out = append(out, f)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, invalid(err)
+	}
+	if dec.More() {
+		return nil, invalid(fmt.Errorf("trailing data after the object"))
+	}
+	if len(out) == 0 {
+		return nil, invalid(fmt.Errorf("no fields"))
+	}
+	return out, nil
+}
+
+// fieldSelector turns a browser_fill_form key into a selector argument: a
+// bare name (email, user[name], address.zip) that matches a field's name
+// attribute selects it by name, anything else is a selector argument.
+func (b *DevBrowser) fieldSelector(ctx context.Context, key string) (string, error) {
+	if isDeepSelector(key) || strings.ContainsAny(key, " #>~+*:,='\"") {
+		return key, b.checkSelector(key)
+	}
+	byName := "[name=" + strconv.Quote(key) + "]"
+	var found bool
+	if err := chromedp.Run(ctx, chromedp.Evaluate("document.querySelector("+strconv.Quote(byName)+") !== null", &found)); err != nil {
+		return "", err
+	}
+	if found {
+		return byName, nil
+	}
+	return key, nil
+}
+
+// fillField gives the field of a selector argument a browser_fill_form
+// value according to its kind. For a radio checked by value it returns
+// that value, since the field the key names may be another radio of the
+// group.
+func (b *DevBrowser) fillField(ctx context.Context, selector string, value any) (string, error) {
+	if err := b.waitField(ctx, selector); err != nil {
+		return "", err
+	}
+	var kind string
+	if err := b.callField(ctx, selector, fieldKindJS, &kind); err != nil {
+		return "", err
+	}
+
+	switch kind {
+	case "checkbox", "radio":
+		switch v := value.(type) {
+		case bool:
+			_, err := b.setChecked(ctx, selector, v)
+			return "", err
+		case string:
+			if kind == "radio" {
+				return b.checkRadio(ctx, selector, v)
+			}
+			if on, err := strconv.ParseBool(v); err == nil {
+				_, err := b.setChecked(ctx, selector, on)
+				return "", err
+			}
+		}
+		return "", fmt.Errorf("a %s takes true or false", kind)
+
+	case "file":
+		var files []string
+		switch v := value.(type) {
+		case string:
+			files = []string{v}
+		case []string:
+			files = v
+		default:
+			return "", fmt.Errorf("a file input takes a path or an array of paths")
+		}
+		paths, err := validateUploadPaths(files)
+		if err != nil {
+			return "", err
+		}
+		return "", b.uploadFiles(ctx, selector, paths)
+
+	case "select", "select-multiple":
+		var values []string
+		switch v := value.(type) {
+		case string:
+			values = []string{v}
+		case []string:
+			values = v
+		default:
+			return "", fmt.Errorf("a <select> takes an option value or label")
+		}
+		_, err := b.selectOptions(ctx, selector, values, "auto")
+		return "", err
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("a text field takes a string")
+	}
+	return "", b.typeInto(ctx, selector, s, true)
+}
@@ -0,0 +1,300 @@
+package devbrowser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tinywasm/devbrowser/cdproto/cdp"
+	"github.com/tinywasm/devbrowser/cdproto/dom"
+	"github.com/tinywasm/devbrowser/cdproto/page"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/cdproto/target"
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// frameWorldName is the isolated world devbrowser scripts run in inside
+// child frames: it sees the frame's DOM whatever its origin, without
+// touching the page's own globals.
+const frameWorldName = "devbrowser"
+
+// frameTarget is an out-of-process iframe (OOPIF). With site isolation
+// Chrome renders cross-site frames in another process, reachable only
+// through their own CDP target; the page target sees a remote frame.
+type frameTarget struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	parent target.ID         // Target hosting the owner <iframe>, "" = the page
+	owner  cdp.BackendNodeID // Owner <iframe> element inside parent
+}
+
+// pageFrame is a child frame of the page, wherever it is rendered.
+type pageFrame struct {
+	ctx         context.Context // Context of the hosting target
+	target      target.ID       // Hosting target, "" = the page
+	frame       *cdp.Frame
+	world       runtime.ExecutionContextID // Isolated world inside the frame
+	owner       cdp.BackendNodeID          // Owner <iframe>, 0 if unknown
+	ownerTarget target.ID                  // Target the owner element lives in
+	// reachable frames are same-origin with their parent and in the same
+	// process: page scripts walk into them through contentDocument.
+	reachable bool
+}
+
+// targetContext returns the context that runs commands against target id,
+// ctx itself for the page ("").
+func (b *DevBrowser) targetContext(ctx context.Context, id target.ID) (context.Context, error) {
+	if id == "" {
+		return ctx, nil
+	}
+	b.Mu.Lock()
+	ft := b.frameTargets[id]
+	b.Mu.Unlock()
+	if ft == nil || ft.ctx.Err() != nil {
+		return nil, fmt.Errorf("frame %s is gone", id)
+	}
+	return ft.ctx, nil
+}
+
+// syncFrameTargets attaches to the out-of-process frames of the page and
+// forgets the ones that are gone. It returns them parents first.
+func (b *DevBrowser) syncFrameTargets(ctx context.Context) ([]target.ID, error) {
+	infos, err := chromedp.Targets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list frame targets: %v", err)
+	}
+
+	live := make(map[target.ID]bool)
+	var pending []target.ID
+	for _, in := range infos {
+		if in.Type == "iframe" {
+			live[in.TargetID] = true
+			pending = append(pending, in.TargetID)
+		}
+	}
+
+	b.Mu.Lock()
+	if b.frameTargets == nil {
+		b.frameTargets = make(map[target.ID]*frameTarget)
+	}
+	// Cancelling a frame context closes its target, which for an iframe
+	// closes the whole tab: only frames that are already gone are cancelled.
+	var order []target.ID
+	var gone []context.CancelFunc
+	for _, id := range b.frameOrder {
+		ft := b.frameTargets[id]
+		if !live[id] {
+			gone = append(gone, ft.cancel)
+		}
+		if !live[id] || ft.ctx.Err() != nil {
+			delete(b.frameTargets, id)
+			continue
+		}
+		order = append(order, id)
+	}
+	b.frameOrder = order
+	b.Mu.Unlock()
+	for _, cancel := range gone {
+		cancel()
+	}
+
+	// A frame is attached once its owner <iframe> shows up in the page or in
+	// an already attached frame; iframe targets of other tabs never do.
+	for progress := true; progress; {
+		progress = false
+		for i := 0; i < len(pending); i++ {
+			id := pending[i]
+			if _, err := b.targetContext(ctx, id); err == nil {
+				pending = append(pending[:i], pending[i+1:]...)
+				i--
+				continue
+			}
+			parent, owner, ok := b.findFrameOwner(ctx, cdp.FrameID(id))
+			if !ok {
+				continue
+			}
+			fctx, cancel := chromedp.NewContext(b.Ctx, chromedp.WithTargetID(id))
+			if err := chromedp.Run(fctx); err != nil {
+				cancel()
+				b.Logger(fmt.Sprintf("Warning: failed to attach to frame %s: %v", id, err))
+				continue
+			}
+			b.Mu.Lock()
+			b.frameTargets[id] = &frameTarget{ctx: fctx, cancel: cancel, parent: parent, owner: owner}
+			b.frameOrder = append(b.frameOrder, id)
+			b.Mu.Unlock()
+			pending = append(pending[:i], pending[i+1:]...)
+			i--
+			progress = true
+		}
+	}
+
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+	return append([]target.ID(nil), b.frameOrder...), nil
+}
+
+// findFrameOwner looks for the <iframe> element of frame id in the page and
+// in the attached frames.
+func (b *DevBrowser) findFrameOwner(ctx context.Context, id cdp.FrameID) (target.ID, cdp.BackendNodeID, bool) {
+	b.Mu.Lock()
+	hosts := append([]target.ID{""}, b.frameOrder...)
+	b.Mu.Unlock()
+
+	for _, host := range hosts {
+		hctx, err := b.targetContext(ctx, host)
+		if err != nil {
+			continue
+		}
+		var owner cdp.BackendNodeID
+		if err := chromedp.Run(hctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			owner, _, err = dom.GetFrameOwner(id).Do(ctx)
+			return err
+		})); err == nil && owner != 0 {
+			return host, owner, true
+		}
+	}
+	return "", 0, false
+}
+
+// childFrames lists every child frame of the page in document order, page
+// process first, then each out-of-process frame with its own subframes.
+func (b *DevBrowser) childFrames(ctx context.Context) ([]pageFrame, error) {
+	frameIDs, err := b.syncFrameTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []pageFrame
+	var walk func(tctx context.Context, host target.ID, tree *page.FrameTree) error
+	walk = func(tctx context.Context, host target.ID, tree *page.FrameTree) error {
+		for _, c := range tree.ChildFrames {
+			f := pageFrame{
+				ctx:         tctx,
+				target:      host,
+				frame:       c.Frame,
+				ownerTarget: host,
+				reachable:   c.Frame.SecurityOrigin == tree.Frame.SecurityOrigin && c.Frame.SecurityOrigin != "null",
+			}
+			err := chromedp.Run(tctx, chromedp.ActionFunc(func(ctx context.Context) error {
+				var err error
+				if f.world, err = page.CreateIsolatedWorld(c.Frame.ID).WithWorldName(frameWorldName).Do(ctx); err != nil {
+					return err
+				}
+				f.owner, _, _ = dom.GetFrameOwner(c.Frame.ID).Do(ctx)
+				return nil
+			}))
+			if err != nil {
+				// Frames detach while we walk; skip them.
+				continue
+			}
+			out = append(out, f)
+			if err := walk(tctx, host, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	frameTree := func(tctx context.Context) (*page.FrameTree, error) {
+		var tree *page.FrameTree
+		err := chromedp.Run(tctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			tree, err = page.GetFrameTree().Do(ctx)
+			return err
+		}))
+		return tree, err
+	}
+
+	tree, err := frameTree(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get frame tree: %v", err)
+	}
+	if err := walk(ctx, "", tree); err != nil {
+		return nil, err
+	}
+
+	for _, id := range frameIDs {
+		fctx, err := b.targetContext(ctx, id)
+		if err != nil {
+			continue
+		}
+		tree, err := frameTree(fctx)
+		if err != nil {
+			continue
+		}
+		b.Mu.Lock()
+		ft := b.frameTargets[id]
+		b.Mu.Unlock()
+		root := pageFrame{ctx: fctx, target: id, frame: tree.Frame, owner: ft.owner, ownerTarget: ft.parent}
+		if err := chromedp.Run(fctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			root.world, err = page.CreateIsolatedWorld(tree.Frame.ID).WithWorldName(frameWorldName).Do(ctx)
+			return err
+		})); err != nil {
+			continue
+		}
+		out = append(out, root)
+		if err := walk(fctx, id, tree); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// evaluate runs expression in the frame's isolated world.
+func (f pageFrame) evaluate(expression string, res any) error {
+	return chromedp.Run(f.ctx, chromedp.Evaluate(expression, res, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithContextID(f.world)
+	}))
+}
+
+// frameOffset is where the viewport of target id starts inside the page
+// viewport: out-of-process frames report coordinates relative to their own
+// viewport, frames in the page process already use the page's.
+func (b *DevBrowser) frameOffset(ctx context.Context, id target.ID) (float64, float64, error) {
+	if id == "" {
+		return 0, 0, nil
+	}
+	b.Mu.Lock()
+	ft := b.frameTargets[id]
+	b.Mu.Unlock()
+	if ft == nil {
+		return 0, 0, fmt.Errorf("frame %s is gone", id)
+	}
+
+	pctx, err := b.targetContext(ctx, ft.parent)
+	if err != nil {
+		return 0, 0, err
+	}
+	var box *dom.BoxModel
+	if err := chromedp.Run(pctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		box, err = dom.GetBoxModel().WithBackendNodeID(ft.owner).Do(ctx)
+		return err
+	})); err != nil {
+		return 0, 0, fmt.Errorf("Failed to locate frame %s: %v", id, err)
+	}
+	if len(box.Content) < 2 {
+		return 0, 0, fmt.Errorf("frame %s has no box", id)
+	}
+
+	px, py, err := b.frameOffset(ctx, ft.parent)
+	if err != nil {
+		return 0, 0, err
+	}
+	return px + box.Content[0], py + box.Content[1], nil
+}
+
+// frameLabel names a frame in reports, with the ref of its <iframe> element
+// when it has one: "frame https://pay.example/ (iframe ref:e7)".
+func (b *DevBrowser) frameLabel(ctx context.Context, f pageFrame) string {
+	label := "frame " + f.frame.URL
+	if f.owner == 0 {
+		return label
+	}
+	if refs, err := b.nodeRefs(ctx, f.ownerTarget, []cdp.BackendNodeID{f.owner}); err == nil && refs[0] != "" {
+		label += " (iframe " + refPrefix + refs[0] + ")"
+	}
+	return label
+}
@@ -0,0 +1,303 @@
+package devbrowser
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/tinywasm/devbrowser/cdproto/input"
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// gestureDefaults are the duration (ms) and distance (px) of each gesture
+// when the caller gives none. The duration is the contact time of taps and
+// presses and the travel time of moving gestures, so it sets the velocity:
+// a fling is a short, fast swipe.
+var gestureDefaults = map[string]struct{ duration, distance int64 }{
+	"tap":        {50, 0},
+	"double_tap": {50, 0},
+	"long_press": {800, 0},
+	"swipe":      {300, 200},
+	"fling":      {80, 200},
+	"pinch_in":   {400, 100},
+	"pinch_out":  {400, 100},
+	"rotate":     {400, 100},
+}
+
+// gestureFrame is the time between two moves of a moving gesture, one
+// display frame.
+const gestureFrame = 16 * time.Millisecond
+
+// gestureTapGap is the pause between the taps of a double tap.
+const gestureTapGap = 100 * time.Millisecond
+
+// gesturePinchGap is the distance between two pinching fingers at their
+// closest.
+const gesturePinchGap = 40
+
+// gestureOptions describes a gesture on the center of an element.
+type gestureOptions struct {
+	name      string        // One of gestureDefaults
+	direction string        // up, down, left or right for swipe and fling
+	distance  float64       // Travel of swipe and fling, spread change of pinches, finger spread of rotate
+	angle     float64       // Degrees of rotate, clockwise
+	duration  time.Duration // Contact or travel time
+	input     string        // auto, touch or mouse
+}
+
+// newGestureOptions validates the arguments of a gesture and fills in the
+// defaults.
+func newGestureOptions(name, direction string, distance, durationMs int64, angle float64, inputMode string) (gestureOptions, error) {
+	def, ok := gestureDefaults[name]
+	if !ok {
+		return gestureOptions{}, fmt.Errorf("unsupported gesture: %s. Use tap, double_tap, long_press, swipe, fling, pinch_in, pinch_out or rotate", name)
+	}
+	o := gestureOptions{name: name, direction: direction, angle: angle, input: inputMode}
+
+	if name == "swipe" || name == "fling" {
+		switch direction {
+		case "up", "down", "left", "right":
+		default:
+			return o, fmt.Errorf("unsupported direction: %q. Use up, down, left or right", direction)
+		}
+	}
+	switch inputMode {
+	case "":
+		o.input = "auto"
+	case "auto", "touch", "mouse":
+	default:
+		return o, fmt.Errorf("unsupported input: %s. Use auto, touch or mouse", inputMode)
+	}
+	if distance < 0 || durationMs < 0 {
+		return o, fmt.Errorf("distance and duration must be positive")
+	}
+	if distance == 0 {
+		distance = def.distance
+	}
+	if durationMs == 0 {
+		durationMs = def.duration
+	}
+	if name == "rotate" && angle == 0 {
+		o.angle = 90
+	}
+	o.distance = float64(distance)
+	o.duration = time.Duration(durationMs) * time.Millisecond
+	return o, nil
+}
+
+// moving reports whether the fingers travel, rather than only touch down.
+func (o gestureOptions) moving() bool {
+	return o.name != "tap" && o.name != "double_tap" && o.name != "long_press"
+}
+
+// fingers returns the position of each finger at progress t (0 to 1) of a
+// gesture centered on (x, y).
+func (o gestureOptions) fingers(x, y, t float64) [][2]float64 {
+	switch o.name {
+	case "swipe", "fling":
+		dx, dy := 0.0, 0.0
+		switch o.direction {
+		case "up":
+			dy = -o.distance
+		case "down":
+			dy = o.distance
+		case "left":
+			dx = -o.distance
+		case "right":
+			dx = o.distance
+		}
+		return [][2]float64{{x + dx*t, y + dy*t}}
+
+	case "pinch_in", "pinch_out":
+		// Two fingers on a horizontal line, spreading or closing.
+		near, far := gesturePinchGap/2.0, gesturePinchGap/2.0+o.distance/2
+		if o.name == "pinch_in" {
+			near, far = far, near
+		}
+		r := near + (far-near)*t
+		return [][2]float64{{x - r, y}, {x + r, y}}
+
+	case "rotate":
+		// Two fingers on opposite ends of a diameter, turning around (x, y).
+		r := o.distance / 2
+		a := o.angle * t * math.Pi / 180
+		return [][2]float64{{x - r*math.Cos(a), y - r*math.Sin(a)}, {x + r*math.Cos(a), y + r*math.Sin(a)}}
+	}
+	return [][2]float64{{x, y}}
+}
+
+// steps is the number of moves of a moving gesture: one per frame.
+func (o gestureOptions) steps() int {
+	return max(int(o.duration/gestureFrame), 3)
+}
+
+// touchInput reports whether the page takes touch input: a touch device is
+// emulated or the screen is a touch screen.
+func touchInput(ctx context.Context) (bool, error) {
+	var touch bool
+	err := chromedp.Run(ctx, chromedp.Evaluate(`navigator.maxTouchPoints > 0`, &touch))
+	return touch, err
+}
+
+// performGesture runs a gesture on the center of the element of a selector
+// argument, with touch events when the page takes touch input (or
+// input=touch) and the closest mouse equivalent otherwise. It returns the
+// input used.
+func (b *DevBrowser) performGesture(ctx context.Context, selector string, o gestureOptions) (string, error) {
+	x, y, err := b.elementPoint(ctx, selector)
+	if err != nil {
+		return "", err
+	}
+
+	kind := o.input
+	if kind == "auto" {
+		kind = "mouse"
+		if touch, err := touchInput(ctx); err != nil {
+			return "", err
+		} else if touch {
+			kind = "touch"
+		}
+	}
+	if kind == "touch" {
+		return kind, touchGesture(ctx, o, x, y)
+	}
+	return kind, b.mouseGesture(ctx, o, x, y)
+}
+
+// touchGesture dispatches a gesture as input.DispatchTouchEvent sequences,
+// one touch point per finger.
+func touchGesture(ctx context.Context, o gestureOptions, x, y float64) error {
+	points := func(t float64) []*input.TouchPoint {
+		var pts []*input.TouchPoint
+		for i, f := range o.fingers(x, y, t) {
+			pts = append(pts, &input.TouchPoint{X: f[0], Y: f[1], ID: float64(i + 1)})
+		}
+		return pts
+	}
+	touch := func(typ input.TouchType, pts []*input.TouchPoint) error {
+		return input.DispatchTouchEvent(typ, pts).Do(ctx)
+	}
+
+	// A gesture that fails with the fingers down cancels the touch, or they
+	// would stay down for every later gesture.
+	down := false
+	defer func() {
+		if down {
+			cctx, cancel := cleanupContext(ctx)
+			defer cancel()
+			input.DispatchTouchEvent(input.TouchCancel, []*input.TouchPoint{}).Do(cctx)
+		}
+	}()
+
+	taps := 1
+	if o.name == "double_tap" {
+		taps = 2
+	}
+	for i := 0; i < taps; i++ {
+		if i > 0 {
+			if err := chromedp.Sleep(gestureTapGap).Do(ctx); err != nil {
+				return err
+			}
+		}
+		if err := touch(input.TouchStart, points(0)); err != nil {
+			return err
+		}
+		down = true
+		if !o.moving() {
+			if err := chromedp.Sleep(o.duration).Do(ctx); err != nil {
+				return err
+			}
+		}
+		if o.moving() {
+			steps := o.steps()
+			for s := 1; s <= steps; s++ {
+				if err := chromedp.Sleep(o.duration / time.Duration(steps)).Do(ctx); err != nil {
+					return err
+				}
+				if err := touch(input.TouchMove, points(float64(s)/float64(steps))); err != nil {
+					return err
+				}
+			}
+		}
+		if err := touch(input.TouchEnd, []*input.TouchPoint{}); err != nil {
+			return err
+		}
+		down = false
+	}
+	return nil
+}
+
+// mouseGesture dispatches the mouse equivalent of a gesture: clicks for
+// taps, a held press for long_press, a left-button drag for swipe and fling
+// and Control+wheel, the way Chrome reports trackpad pinches, for pinches.
+// rotate has no mouse equivalent.
+func (b *DevBrowser) mouseGesture(ctx context.Context, o gestureOptions, x, y float64) error {
+	if o.name == "rotate" {
+		return fmt.Errorf("rotate needs touch input: emulate a touch device or set input=touch")
+	}
+	if err := b.mouseMove(ctx, x, y, pointerSteps, mouseButton{}); err != nil {
+		return err
+	}
+
+	left := mouseButtons["left"]
+	steps := o.steps()
+	pause := o.duration / time.Duration(steps)
+	switch o.name {
+	case "tap":
+		return mouseClick(ctx, x, y, left, 1, 0)
+	case "double_tap":
+		return mouseClick(ctx, x, y, left, 2, 0)
+	case "long_press":
+		return mouseClick(ctx, x, y, left, 1, o.duration)
+
+	case "pinch_in", "pinch_out":
+		// Negative deltaY zooms in, like spreading two fingers.
+		delta := o.distance / float64(steps)
+		if o.name == "pinch_out" {
+			delta = -delta
+		}
+		for s := 0; s < steps; s++ {
+			if err := chromedp.Sleep(pause).Do(ctx); err != nil {
+				return err
+			}
+			if err := input.DispatchMouseEvent(input.MouseWheel, x, y).
+				WithDeltaX(0).WithDeltaY(delta).WithModifiers(input.ModifierCtrl).Do(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := input.DispatchMouseEvent(input.MousePressed, x, y).
+		WithButton(left.name).WithButtons(left.bit).WithClickCount(1).Do(ctx); err != nil {
+		return err
+	}
+	released := false
+	defer func() {
+		if !released {
+			b.Mu.Lock()
+			atX, atY := b.mouseX, b.mouseY
+			b.Mu.Unlock()
+			releaseMouse(ctx, atX, atY, left)
+		}
+	}()
+	end := o.fingers(x, y, 1)[0]
+	for s := 1; s <= steps; s++ {
+		if err := chromedp.Sleep(pause).Do(ctx); err != nil {
+			return err
+		}
+		p := o.fingers(x, y, float64(s)/float64(steps))[0]
+		if err := input.DispatchMouseEvent(input.MouseMoved, p[0], p[1]).
+			WithButton(left.name).WithButtons(left.bit).Do(ctx); err != nil {
+			return err
+		}
+		b.Mu.Lock()
+		b.mouseX, b.mouseY = p[0], p[1]
+		b.Mu.Unlock()
+	}
+	err := input.DispatchMouseEvent(input.MouseReleased, end[0], end[1]).
+		WithButton(left.name).WithClickCount(1).Do(ctx)
+	released = err == nil
+	return err
+}
@@ -0,0 +1,125 @@
+package devbrowser
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg"
+	"image/png"
+)
+
+// contactSheetPadding is the gap in pixels between cells and around the sheet.
+const contactSheetPadding = 16
+
+// ContactSheet lays the encoded images (PNG or JPEG) out in a grid of the
+// given number of columns, scaling each one to cellWidth pixels wide while
+// keeping its aspect ratio, and returns the sheet encoded as PNG. Cells are
+// placed in the same order as images, left to right and top to bottom.
+func ContactSheet(images [][]byte, columns, cellWidth int) ([]byte, error) {
+	if len(images) == 0 {
+		return nil, fmt.Errorf("contact sheet needs at least one image")
+	}
+	if columns <= 0 {
+		columns = 1
+	}
+	if columns > len(images) {
+		columns = len(images)
+	}
+	if cellWidth <= 0 {
+		cellWidth = 320
+	}
+
+	cells := make([]image.Image, len(images))
+	for i, data := range images {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image %d: %v", i+1, err)
+		}
+		cells[i] = scaleToWidth(img, cellWidth)
+	}
+
+	rows := (len(cells) + columns - 1) / columns
+	rowHeights := make([]int, rows)
+	for i, c := range cells {
+		if h := c.Bounds().Dy(); h > rowHeights[i/columns] {
+			rowHeights[i/columns] = h
+		}
+	}
+
+	sheetW := columns*cellWidth + (columns+1)*contactSheetPadding
+	sheetH := contactSheetPadding
+	for _, h := range rowHeights {
+		sheetH += h + contactSheetPadding
+	}
+
+	sheet := image.NewRGBA(image.Rect(0, 0, sheetW, sheetH))
+	draw.Draw(sheet, sheet.Bounds(), &image.Uniform{color.RGBA{0xf0, 0xf0, 0xf0, 0xff}}, image.Point{}, draw.Src)
+
+	y := contactSheetPadding
+	for row := 0; row < rows; row++ {
+		for col := 0; col < columns; col++ {
+			i := row*columns + col
+			if i >= len(cells) {
+				break
+			}
+			x := contactSheetPadding + col*(cellWidth+contactSheetPadding)
+			c := cells[i]
+			r := image.Rect(x, y, x+c.Bounds().Dx(), y+c.Bounds().Dy())
+			draw.Draw(sheet, r, c, c.Bounds().Min, draw.Over)
+		}
+		y += rowHeights[row] + contactSheetPadding
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sheet); err != nil {
+		return nil, fmt.Errorf("failed to encode contact sheet: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleToWidth resizes img to width w keeping its aspect ratio. Each
+// destination pixel averages the source pixels it covers, which keeps text
+// readable when high-DPR device captures are shrunk into a sheet cell.
+func scaleToWidth(img image.Image, w int) image.Image {
+	src := img.Bounds()
+	if src.Dx() == 0 || src.Dy() == 0 {
+		return img
+	}
+	h := src.Dy() * w / src.Dx()
+	if h < 1 {
+		h = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for dy := 0; dy < h; dy++ {
+		sy0 := src.Min.Y + dy*src.Dy()/h
+		sy1 := src.Min.Y + (dy+1)*src.Dy()/h
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for dx := 0; dx < w; dx++ {
+			sx0 := src.Min.X + dx*src.Dx()/w
+			sx1 := src.Min.X + (dx+1)*src.Dx()/w
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+			var r, g, b, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a = r+cr, g+cg, b+cb, a+ca
+					n++
+				}
+			}
+			dst.SetRGBA(dx, dy, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
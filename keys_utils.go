@@ -0,0 +1,239 @@
+package devbrowser
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tinywasm/devbrowser/cdproto/input"
+	"github.com/tinywasm/devbrowser/chromedp/kb"
+)
+
+// keyAliases maps common spellings of keys to their DOM key name.
+var keyAliases = map[string]string{
+	"esc":      "Escape",
+	"return":   "Enter",
+	"space":    " ",
+	"spacebar": " ",
+	"plus":     "+",
+	"ctrl":     "Control",
+	"cmd":      "Meta",
+	"command":  "Meta",
+	"super":    "Meta",
+	"win":      "Meta",
+	"option":   "Alt",
+	"opt":      "Alt",
+	"up":       "ArrowUp",
+	"down":     "ArrowDown",
+	"left":     "ArrowLeft",
+	"right":    "ArrowRight",
+	"del":      "Delete",
+	"ins":      "Insert",
+	"pgup":     "PageUp",
+	"pgdn":     "PageDown",
+}
+
+// keyModifiers are the keys that can hold a chord, by DOM key name.
+var keyModifiers = map[string]struct {
+	key  rune
+	flag input.Modifier
+}{
+	"Alt":     {[]rune(kb.Alt)[0], input.ModifierAlt},
+	"Control": {[]rune(kb.Control)[0], input.ModifierCtrl},
+	"Meta":    {[]rune(kb.Meta)[0], input.ModifierMeta},
+	"Shift":   {[]rune(kb.Shift)[0], input.ModifierShift},
+}
+
+// keyMetaCommands are the editing commands of Meta shortcuts. Blink maps
+// Control shortcuts (Control+A selects all) itself, but Meta ones are
+// handled by the macOS menu, which synthetic events never reach.
+var keyMetaCommands = map[rune]string{
+	'a': "selectAll",
+	'c': "copy",
+	'x': "cut",
+	'v': "paste",
+	'z': "undo",
+	'Z': "redo",
+	'y': "redo",
+}
+
+var (
+	keyNamesOnce sync.Once
+	keyNames     map[string]rune // Lowercase DOM key name or code
+
+	shiftedKeysOnce sync.Once
+	shiftedKeys     map[rune]rune // Unshifted rune to the rune Shift types
+)
+
+// lookupKey finds the rune of the kb key table for a key name: a single
+// character, a DOM key name ("Enter", "ArrowDown", "F5"), a code ("KeyK",
+// "Digit1") or an alias ("Esc", "Ctrl", "Up"), case-insensitively.
+func lookupKey(name string) (rune, error) {
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		return r, nil
+	}
+	if alias, ok := keyAliases[strings.ToLower(name)]; ok {
+		return lookupKey(alias)
+	}
+
+	keyNamesOnce.Do(func() {
+		keyNames = make(map[string]rune)
+		add := func(name string, r rune, k *kb.Key) {
+			name = strings.ToLower(name)
+			// Several runes share a code ('a' and 'A'); prefer the
+			// unshifted one, then the lowest, so lookups are stable.
+			old, ok := keyNames[name]
+			if ok && (kb.Keys[old].Shift != k.Shift && k.Shift || kb.Keys[old].Shift == k.Shift && old < r) {
+				return
+			}
+			keyNames[name] = r
+		}
+		for r, k := range kb.Keys {
+			if utf8.RuneCountInString(k.Key) > 1 {
+				add(k.Key, r, k)
+			}
+			add(k.Code, r, k)
+		}
+	})
+	if r, ok := keyNames[strings.ToLower(name)]; ok {
+		return r, nil
+	}
+	return 0, fmt.Errorf("unknown key %q: use a character or a key name like Enter, Escape, Tab, ArrowDown, F5", name)
+}
+
+// shiftKey is the character key types with Shift held on the US layout of
+// the kb key table: 'a' gives 'A', '1' gives '!' and '/' gives '?'. Keys
+// without a shifted character, like Enter, are returned unchanged.
+func shiftKey(key rune) rune {
+	shiftedKeysOnce.Do(func() {
+		byCode := make(map[string]rune)
+		for r, k := range kb.Keys {
+			if !k.Shift || !k.Print {
+				continue
+			}
+			if old, ok := byCode[k.Code]; !ok || r < old {
+				byCode[k.Code] = r
+			}
+		}
+		shiftedKeys = make(map[rune]rune)
+		for r, k := range kb.Keys {
+			if s, ok := byCode[k.Code]; ok && !k.Shift && k.Print {
+				shiftedKeys[r] = s
+			}
+		}
+	})
+	if s, ok := shiftedKeys[key]; ok {
+		return s
+	}
+	return unicode.ToUpper(key)
+}
+
+// keyChord is a key pressed while holding modifiers, like Control+Shift+P.
+type keyChord struct {
+	modifiers []string // DOM key names, in press order
+	key       rune
+}
+
+// parseKeyChord parses "Control+Shift+P", "Enter" or "Control++". Names are
+// case-insensitive; every part but the last must be a modifier.
+func parseKeyChord(chord string) (keyChord, error) {
+	var c keyChord
+	parts := strings.Split(chord, "+")
+	if chord == "+" || strings.HasSuffix(chord, "++") {
+		// The key itself is "+": "+" or "Control++".
+		parts = append(parts[:len(parts)-2], "+")
+	}
+
+	for i, part := range parts {
+		if part == "" {
+			return c, fmt.Errorf("invalid key chord %q: empty key", chord)
+		}
+		r, err := lookupKey(part)
+		if err != nil {
+			return c, fmt.Errorf("invalid key chord %q: %v", chord, err)
+		}
+		if i == len(parts)-1 {
+			c.key = r
+			break
+		}
+		k := kb.Keys[r]
+		if k == nil || keyModifiers[k.Key].flag == 0 {
+			return c, fmt.Errorf("invalid key chord %q: %s is not a modifier (Control, Shift, Alt or Meta)", chord, part)
+		}
+		c.modifiers = append(c.modifiers, k.Key)
+	}
+	return c, nil
+}
+
+// parseKeys parses a space-separated sequence of chords ("Tab Tab Enter").
+func parseKeys(keys string) ([]keyChord, error) {
+	var chords []keyChord
+	for _, chord := range strings.Fields(keys) {
+		c, err := parseKeyChord(chord)
+		if err != nil {
+			return nil, err
+		}
+		chords = append(chords, c)
+	}
+	if len(chords) == 0 {
+		return nil, fmt.Errorf("keys required, e.g. Enter, Control+K or \"Tab Tab Enter\"")
+	}
+	return chords, nil
+}
+
+// events is the input.DispatchKeyEvent sequence of the chord: modifiers
+// down in order, the key down, char and up, modifiers up in reverse. A key
+// held with Control, Alt or Meta is a shortcut and types no text.
+func (c keyChord) events() []*input.DispatchKeyEventParams {
+	var events []*input.DispatchKeyEventParams
+	var mods input.Modifier
+	for _, name := range c.modifiers {
+		m := keyModifiers[name]
+		mods |= m.flag
+		down := kb.Encode(m.key)[0]
+		down.Modifiers = mods
+		events = append(events, down)
+	}
+
+	key := c.key
+	if mods&input.ModifierShift != 0 {
+		key = shiftKey(key)
+	}
+	shortcut := mods&(input.ModifierCtrl|input.ModifierAlt|input.ModifierMeta) != 0
+	for _, e := range kb.Encode(key) {
+		if e.Type == input.KeyChar && shortcut {
+			continue
+		}
+		e.Modifiers |= mods
+		if e.Type == input.KeyDown && mods&input.ModifierMeta != 0 {
+			if cmd, ok := keyMetaCommands[key]; ok {
+				e.Commands = []string{cmd}
+			}
+		}
+		events = append(events, e)
+	}
+
+	for i := len(c.modifiers) - 1; i >= 0; i-- {
+		m := keyModifiers[c.modifiers[i]]
+		mods &^= m.flag
+		up := kb.Encode(m.key)[1]
+		up.Modifiers = mods
+		events = append(events, up)
+	}
+	return events
+}
+
+func (c keyChord) String() string {
+	name := kb.Keys[c.key]
+	key := string(c.key)
+	switch {
+	case name != nil && utf8.RuneCountInString(name.Key) > 1:
+		key = name.Key
+	case c.key == ' ':
+		key = "Space"
+	}
+	return strings.Join(append(append([]string{}, c.modifiers...), key), "+")
+}
@@ -0,0 +1,41 @@
+package devbrowser
+
+import (
+	"testing"
+
+	"github.com/tinywasm/devbrowser/cdproto/input"
+)
+
+// TestKeyChordShift guards the character a Shift chord types: the shifted
+// character of the US layout, not the unshifted key upper-cased, which
+// leaves digits and symbols unchanged.
+func TestKeyChordShift(t *testing.T) {
+	cases := map[string]string{
+		"Shift+a":      "A",
+		"Shift+1":      "!",
+		"Shift+2":      "@",
+		"Shift+0":      ")",
+		"Shift+/":      "?",
+		"Shift+;":      ":",
+		"Shift+'":      `"`,
+		"Shift+Digit9": "(",
+		"Shift+Enter":  "\r",
+		"Shift+é":      "É",
+		"1":            "1",
+	}
+	for keys, want := range cases {
+		chords, err := parseKeys(keys)
+		if err != nil {
+			t.Fatalf("%s: %v", keys, err)
+		}
+		var text string
+		for _, e := range chords[0].events() {
+			if e.Type == input.KeyChar {
+				text += e.Text
+			}
+		}
+		if text != want {
+			t.Errorf("%s: expected to type %q, got %q", keys, want, text)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package devbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tinywasm/devbrowser/cdproto/accessibility"
+	"github.com/tinywasm/devbrowser/cdproto/cdp"
+	"github.com/tinywasm/devbrowser/cdproto/dom"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/cdproto/target"
+)
+
+// locatorKinds are the prefixes of locator steps, which find elements the
+// way a user does instead of by CSS: text=Save, role=button[name="Save"],
+// label=Email, placeholder=Search, testid=submit and xpath=//form/button.
+var locatorKinds = map[string]bool{
+	"text":        true,
+	"role":        true,
+	"label":       true,
+	"placeholder": true,
+	"testid":      true,
+	"xpath":       true,
+}
+
+// labelRoles are the AX roles of the form controls label= finds.
+var labelRoles = map[string]bool{
+	"textbox":    true,
+	"searchbox":  true,
+	"combobox":   true,
+	"listbox":    true,
+	"checkbox":   true,
+	"radio":      true,
+	"switch":     true,
+	"slider":     true,
+	"spinbutton": true,
+}
+
+// locatorXPathJS evaluates an XPath with this (a document or shadow root)
+// as context node and returns the matching elements, rendered ones first.
+// Text nodes stand for their parent element.
+const locatorXPathJS = `function(xp) {
+	const doc = this.ownerDocument || this;
+	const res = doc.evaluate(xp, this, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+	const els = [];
+	for (let i = 0; i < res.snapshotLength; i++) {
+		let n = res.snapshotItem(i);
+		if (n.nodeType === Node.TEXT_NODE) n = n.parentElement;
+		if (n && n.nodeType === Node.ELEMENT_NODE && !els.includes(n)) els.push(n);
+	}
+	const shown = els.filter(e => e.getClientRects().length > 0);
+	return shown.concat(els.filter(e => !shown.includes(e)));
+}`
+
+// textMatch matches visible text or an accessible name: exact (after
+// collapsing whitespace) for quoted values, case-insensitive substring
+// otherwise.
+type textMatch struct {
+	text  string
+	exact bool
+}
+
+// parseTextMatch reads the value of a locator: "Save" or 'Save' is exact,
+// Save is a substring.
+func parseTextMatch(value string) (textMatch, error) {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = strings.Join(strings.Fields(value[1:len(value)-1]), " ")
+		if value == "" {
+			return textMatch{}, fmt.Errorf("empty value")
+		}
+		return textMatch{text: value, exact: true}, nil
+	}
+	if value == "" {
+		return textMatch{}, fmt.Errorf("empty value")
+	}
+	return textMatch{text: strings.Join(strings.Fields(value), " ")}, nil
+}
+
+func (m textMatch) matches(s string) bool {
+	s = strings.Join(strings.Fields(s), " ")
+	if m.exact {
+		return s == m.text
+	}
+	return strings.Contains(asciiLower(s), asciiLower(m.text))
+}
+
+// xpath is the XPath condition of the match on the string value of expr.
+// XPath 1.0 only folds case by translate, so both sides fold ASCII only.
+func (m textMatch) xpath(expr string) string {
+	if m.exact {
+		return "normalize-space(" + expr + ")=" + xpathLiteral(m.text)
+	}
+	return "contains(translate(normalize-space(" + expr + "), 'ABCDEFGHIJKLMNOPQRSTUVWXYZ', 'abcdefghijklmnopqrstuvwxyz'), " + xpathLiteral(asciiLower(m.text)) + ")"
+}
+
+func asciiLower(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+// xpathLiteral quotes s as an XPath 1.0 string, which has no escapes:
+// strings with both quote kinds are built with concat().
+func xpathLiteral(s string) string {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	if !strings.Contains(s, `"`) {
+		return `"` + s + `"`
+	}
+	parts := strings.Split(s, "'")
+	for i, p := range parts {
+		parts[i] = "'" + p + "'"
+	}
+	return "concat(" + strings.Join(parts, `, "'", `) + ")"
+}
+
+// locator is a parsed locator step. XPath kinds are matched by xpath, role
+// and label through the accessibility tree.
+type locator struct {
+	xpath string
+	role  string          // role=: the AX role
+	roles map[string]bool // label=: the AX roles accepted
+	name  *textMatch      // Accessible name, nil = any
+}
+
+// isLocator reports whether a selector path step is a locator.
+func isLocator(step string) bool {
+	kind, _, ok := strings.Cut(step, "=")
+	return ok && locatorKinds[kind]
+}
+
+// parseLocator parses a locator step.
+func parseLocator(step string) (locator, error) {
+	kind, value, _ := strings.Cut(step, "=")
+	var l locator
+	invalid := func(err error) error {
+		return fmt.Errorf("invalid locator %q: %v", step, err)
+	}
+
+	switch kind {
+	case "xpath":
+		if strings.TrimSpace(value) == "" {
+			return l, invalid(fmt.Errorf("empty XPath"))
+		}
+		l.xpath = value
+
+	case "testid":
+		m, err := parseTextMatch(value)
+		if err != nil {
+			return l, invalid(err)
+		}
+		l.xpath = ".//*[@data-testid=" + xpathLiteral(m.text) + "]"
+
+	case "placeholder":
+		m, err := parseTextMatch(value)
+		if err != nil {
+			return l, invalid(err)
+		}
+		l.xpath = ".//*[@placeholder][" + m.xpath("@placeholder") + "]"
+
+	case "text":
+		m, err := parseTextMatch(value)
+		if err != nil {
+			return l, invalid(err)
+		}
+		// The innermost elements holding the text, plus buttons whose text
+		// is their value.
+		own := m.xpath(".")
+		l.xpath = ".//*[not(self::script or self::style or self::noscript or self::template or self::head or self::title)]" +
+			"[(" + own + " and not(*[" + own + "])) or (self::input and (@type='submit' or @type='button' or @type='reset') and " + m.xpath("@value") + ")]"
+
+	case "label":
+		m, err := parseTextMatch(value)
+		if err != nil {
+			return l, invalid(err)
+		}
+		l.roles, l.name = labelRoles, &m
+
+	case "role":
+		role, attr, hasAttr := strings.Cut(value, "[")
+		l.role = strings.TrimSpace(role)
+		if l.role == "" {
+			return l, invalid(fmt.Errorf("empty role"))
+		}
+		if hasAttr {
+			attr, ok := strings.CutSuffix(strings.TrimSpace(attr), "]")
+			if !ok {
+				return l, invalid(fmt.Errorf("missing ]"))
+			}
+			// [name="Save"] and [name=Save] are exact, [name*=save] a
+			// case-insensitive substring, as in CSS.
+			var m textMatch
+			var err error
+			if v, ok := strings.CutPrefix(attr, "name*="); ok {
+				m, err = parseTextMatch(v)
+				m.exact = false
+			} else if v, ok := strings.CutPrefix(attr, "name="); ok {
+				m, err = parseTextMatch(v)
+				m.exact = true
+			} else {
+				err = fmt.Errorf("use [name=\"...\"] or [name*=\"...\"]")
+			}
+			if err != nil {
+				return l, invalid(err)
+			}
+			l.name = &m
+		}
+
+	default:
+		return l, invalid(fmt.Errorf("unknown kind %s. Use text, role, label, placeholder, testid or xpath", kind))
+	}
+	return l, nil
+}
+
+// queryLocator resolves a locator step inside root (a document or shadow
+// root), or inside the document of the target tctx runs against when root
+// is nil. Of several matches it takes the first rendered one.
+func queryLocator(tctx context.Context, tgt target.ID, root *runtime.RemoteObject, step string) (*resolvedElement, error) {
+	l, err := parseLocator(step)
+	if err != nil {
+		return nil, err
+	}
+
+	el := &resolvedElement{ctx: tctx, target: tgt}
+	err = el.do(func(ctx context.Context) error {
+		nodes, err := locatorNodes(ctx, root, l)
+		if err != nil {
+			return err
+		}
+		if len(nodes) == 0 {
+			return elementMissing{"element not found: " + step}
+		}
+
+		el.node = nodes[0]
+		el.obj, err = dom.ResolveNode().WithBackendNodeID(el.node).Do(ctx)
+		return err
+	})
+	if err != nil {
+		el.release()
+		return nil, err
+	}
+	return el, nil
+}
+
+// locatorNodes returns the elements l matches inside root, or inside the
+// document when root is nil, rendered ones first.
+func locatorNodes(ctx context.Context, root *runtime.RemoteObject, l locator) ([]cdp.BackendNodeID, error) {
+	scope := root
+	if scope == nil {
+		obj, exc, err := runtime.Evaluate("document").Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if exc != nil {
+			return nil, exc
+		}
+		defer runtime.ReleaseObject(obj.ObjectID).Do(ctx)
+		scope = obj
+	}
+	if l.xpath != "" {
+		return xpathNodes(ctx, scope, l.xpath)
+	}
+	return axNodes(ctx, scope, l)
+}
+
+// xpathNodes evaluates an XPath in scope with locatorXPathJS.
+func xpathNodes(ctx context.Context, scope *runtime.RemoteObject, xpath string) ([]cdp.BackendNodeID, error) {
+	arg, err := json.Marshal(xpath)
+	if err != nil {
+		return nil, err
+	}
+	arr, exc, err := runtime.CallFunctionOn(locatorXPathJS).
+		WithObjectID(scope.ObjectID).
+		WithArguments([]*runtime.CallArgument{{Value: arg}}).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exc != nil {
+		return nil, fmt.Errorf("invalid XPath %q: %v", xpath, exc)
+	}
+	defer runtime.ReleaseObject(arr.ObjectID).Do(ctx)
+	return refArrayNodes(ctx, arr)
+}
+
+// axNodes queries the accessibility tree under scope for the role and name
+// of a role= or label= locator. The AX tree of a shadow root is the one of
+// its host, which it pierces.
+func axNodes(ctx context.Context, scope *runtime.RemoteObject, l locator) ([]cdp.BackendNodeID, error) {
+	host, exc, err := runtime.CallFunctionOn(`function() { return this.nodeType === 11 ? this.host : this; }`).
+		WithObjectID(scope.ObjectID).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exc != nil {
+		return nil, exc
+	}
+	defer runtime.ReleaseObject(host.ObjectID).Do(ctx)
+	self, err := dom.DescribeNode().WithObjectID(host.ObjectID).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p := accessibility.QueryAXTree().WithObjectID(host.ObjectID)
+	if l.role != "" {
+		p = p.WithRole(l.role)
+	}
+	found, err := p.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []cdp.BackendNodeID
+	for _, n := range found {
+		if n.Ignored || n.BackendDOMNodeID == 0 || n.BackendDOMNodeID == self.BackendNodeID {
+			continue
+		}
+		if l.roles != nil && !l.roles[axValueString(n.Role)] {
+			continue
+		}
+		if l.name != nil && !l.name.matches(axValueString(n.Name)) {
+			continue
+		}
+		nodes = append(nodes, n.BackendDOMNodeID)
+	}
+	return nodes, nil
+}
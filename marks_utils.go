@@ -0,0 +1,256 @@
+package devbrowser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// marksMaxElements caps the set-of-marks badges; past that the image is
+// unreadable and the table too long to be useful.
+const marksMaxElements = 150
+
+// pageMark is one numbered interactive element of a set-of-marks snapshot.
+type pageMark struct {
+	Index    int    `json:"index"`
+	Selector string `json:"selector"` // Stable selector, the fallback when no element ref was assigned
+	Ref      string `json:"-"`        // Element ref ("e12") assigned after the snapshot
+	Role     string `json:"role"`
+	Name     string `json:"name"`
+	X        int    `json:"x"` // Viewport coordinates (document with fullpage), CSS px
+	Y        int    `json:"y"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// SetOfMarksJS numbers every visible interactive element (isClickable, the
+// GetStructureJS heuristic, plus textareas) with a badge and returns index,
+// a stable selector, role and accessible name for each. Badges live in one
+// container that removeMarksJS deletes. The marked elements are left, in
+// order, for collectRefTargets.
+const SetOfMarksJS = `(fullpage, maxMarks) => {
+	document.getElementById('__devbrowser_marks')?.remove();
+	const refTargets = window.` + refTargetsGlobal + ` = [];
+` + clickableHeuristicJS + `
+	const native = ['button', 'a', 'input', 'select', 'textarea'];
+
+	const uniq = (sel) => {
+		try { return document.querySelectorAll(sel).length === 1; } catch (e) { return false; }
+	};
+	const stableSelector = (el) => {
+		const tag = el.tagName.toLowerCase();
+		if (el.id && uniq('#' + CSS.escape(el.id))) return '#' + CSS.escape(el.id);
+		for (const attr of ['data-testid', 'data-test', 'data-cy', 'name', 'aria-label']) {
+			const v = el.getAttribute(attr);
+			if (!v) continue;
+			const sel = tag + '[' + attr + '="' + v.replace(/["\\]/g, '\\$&') + '"]';
+			if (uniq(sel)) return sel;
+		}
+		const parts = [];
+		for (let cur = el; cur && cur !== document.documentElement; cur = cur.parentElement) {
+			if (cur !== el && cur.id && uniq('#' + CSS.escape(cur.id))) {
+				parts.unshift('#' + CSS.escape(cur.id));
+				break;
+			}
+			let part = cur.tagName.toLowerCase();
+			const parent = cur.parentElement;
+			if (parent) {
+				const same = Array.from(parent.children).filter(c => c.tagName === cur.tagName);
+				if (same.length > 1) part += ':nth-of-type(' + (same.indexOf(cur) + 1) + ')';
+			}
+			parts.unshift(part);
+		}
+		return parts.join(' > ');
+	};
+	const roleOf = (el) => {
+		const explicit = el.getAttribute('role');
+		if (explicit) return explicit;
+		const tag = el.tagName.toLowerCase();
+		if (tag === 'a') return el.hasAttribute('href') ? 'link' : 'generic';
+		if (tag === 'button') return 'button';
+		if (tag === 'select') return 'combobox';
+		if (tag === 'textarea') return 'textbox';
+		if (tag === 'input') {
+			const t = (el.getAttribute('type') || 'text').toLowerCase();
+			if (['button', 'submit', 'reset', 'image'].includes(t)) return 'button';
+			if (t === 'checkbox' || t === 'radio') return t;
+			if (t === 'range') return 'slider';
+			return 'textbox';
+		}
+		return 'clickable';
+	};
+	const nameOf = (el) => {
+		let n = el.getAttribute('aria-label');
+		const labelledBy = el.getAttribute('aria-labelledby');
+		if (!n && labelledBy) {
+			n = labelledBy.split(/\s+/).map(id => document.getElementById(id)?.textContent || '').join(' ');
+		}
+		if (!n && el.labels && el.labels.length) n = el.labels[0].textContent;
+		if (!n && !['INPUT', 'SELECT', 'TEXTAREA'].includes(el.tagName)) n = el.innerText;
+		if (!n) n = el.getAttribute('placeholder') || el.getAttribute('title') || el.getAttribute('alt');
+		if (!n && (el.type === 'submit' || el.type === 'button')) n = el.value;
+		return (n || '').replace(/\s+/g, ' ').trim().slice(0, 60);
+	};
+
+	const root = document.createElement('div');
+	root.id = '__devbrowser_marks';
+	root.style.cssText = 'position:absolute;left:0;top:0;width:0;height:0;overflow:visible;z-index:2147483647;pointer-events:none;';
+
+	const marks = [];
+	for (const el of document.querySelectorAll('body *')) {
+		if (marks.length >= maxMarks) break;
+		if (el.closest('#__devbrowser_annotations')) continue;
+		const style = window.getComputedStyle(el);
+		const tag = el.tagName.toLowerCase();
+		if (!isClickable(el, style) && !native.includes(tag)) continue;
+		// cursor:pointer is inherited: only the outermost element of a pointer
+		// region gets a mark, native controls inside it still get their own.
+		if (!native.includes(tag) && el.parentElement && window.getComputedStyle(el.parentElement).cursor === 'pointer') continue;
+		if (el.disabled || (tag === 'input' && el.type === 'hidden')) continue;
+		if (style.visibility === 'hidden' || style.opacity === '0') continue;
+
+		const r = el.getBoundingClientRect();
+		if (r.width < 1 || r.height < 1) continue;
+		const inViewport = r.bottom > 0 && r.right > 0 && r.top < window.innerHeight && r.left < window.innerWidth;
+		if (!fullpage && !inViewport) continue;
+		// Skip elements covered by something else (modal backdrop, sticky header).
+		const cx = r.left + r.width / 2, cy = r.top + r.height / 2;
+		if (cx >= 0 && cy >= 0 && cx < window.innerWidth && cy < window.innerHeight) {
+			const hit = document.elementFromPoint(cx, cy);
+			if (hit && hit !== el && !el.contains(hit) && !hit.contains(el)) continue;
+		}
+
+		const index = marks.length + 1;
+		const px = r.left + window.scrollX, py = r.top + window.scrollY;
+		const box = document.createElement('div');
+		box.style.cssText = 'position:absolute;box-sizing:border-box;' +
+			'left:' + px + 'px;top:' + py + 'px;width:' + r.width + 'px;height:' + r.height + 'px;' +
+			'border:1px dashed #ff1744;';
+		const badge = document.createElement('div');
+		badge.textContent = index;
+		badge.style.cssText = 'position:absolute;left:-1px;top:-1px;min-width:12px;height:14px;padding:0 3px;' +
+			'font:bold 11px/14px sans-serif;color:#fff;background:#ff1744;text-align:center;border-radius:0 0 3px 0;';
+		box.appendChild(badge);
+		root.appendChild(box);
+
+		refTargets.push(el);
+		marks.push({
+			index: index,
+			selector: stableSelector(el),
+			role: roleOf(el),
+			name: nameOf(el),
+			x: Math.round(fullpage ? px : r.left), y: Math.round(fullpage ? py : r.top),
+			width: Math.round(r.width), height: Math.round(r.height)
+		});
+	}
+	document.documentElement.appendChild(root);
+	return marks;
+}`
+
+const removeMarksJS = `document.getElementById('__devbrowser_marks')?.remove()`
+
+// drawMarks overlays the set-of-marks badges. The caller captures and then
+// calls removeMarks.
+func (b *DevBrowser) drawMarks(fullpage bool) ([]pageMark, error) {
+	var marks []pageMark
+	js := fmt.Sprintf("(%s)(%t, %d)", SetOfMarksJS, fullpage, marksMaxElements)
+	if err := chromedp.Run(b.Ctx, chromedp.Evaluate(js, &marks)); err != nil {
+		b.removeMarks()
+		return nil, fmt.Errorf("Failed to draw marks: %v", err)
+	}
+
+	var refs []string
+	if err := chromedp.Run(b.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		refs, err = b.collectRefTargets(ctx)
+		return err
+	})); err != nil {
+		b.Logger(fmt.Sprintf("Warning: failed to assign element refs to marks: %v", err))
+	}
+	for i := range marks {
+		if i < len(refs) {
+			marks[i].Ref = refs[i]
+		}
+	}
+	return marks, nil
+}
+
+func (b *DevBrowser) removeMarks() {
+	if err := chromedp.Run(b.Ctx, chromedp.Evaluate(removeMarksJS, nil)); err != nil {
+		b.Logger(fmt.Sprintf("Warning: failed to remove marks: %v", err))
+	}
+}
+
+// rememberMarks keeps the snapshot so ref indices can be resolved later.
+func (b *DevBrowser) rememberMarks(marks []pageMark, pageURL string) {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+	b.lastMarks = marks
+	b.lastMarksURL = pageURL
+}
+
+// resolveElementTarget returns the selector an interaction tool should act
+// on: the selector itself, or the element ref (or, failing that, the stable
+// selector) of mark ref from the last set-of-marks snapshot.
+func (b *DevBrowser) resolveElementTarget(selector string, ref int64) (string, error) {
+	if selector != "" && ref != 0 {
+		return "", fmt.Errorf("use either selector or ref, not both")
+	}
+	if ref == 0 {
+		if selector == "" {
+			return "", fmt.Errorf("selector or ref required")
+		}
+		return selector, nil
+	}
+
+	b.Mu.Lock()
+	marks := b.lastMarks
+	marksURL := b.lastMarksURL
+	b.Mu.Unlock()
+
+	if len(marks) == 0 {
+		return "", fmt.Errorf("ref %d: no marks snapshot, take browser_screenshot with marks first", ref)
+	}
+	if ref < 1 || int(ref) > len(marks) {
+		return "", fmt.Errorf("ref %d out of range: last marks snapshot has refs 1-%d", ref, len(marks))
+	}
+
+	mark := marks[ref-1]
+	if mark.Ref != "" {
+		// The element ref resolver reports removed elements and navigations.
+		return refPrefix + mark.Ref, nil
+	}
+
+	var currentURL string
+	if err := chromedp.Run(b.Ctx, chromedp.Location(&currentURL)); err == nil && currentURL != marksURL {
+		return "", fmt.Errorf("ref %d is stale: marks were taken on %s, page is now %s; take a new browser_screenshot with marks", ref, marksURL, currentURL)
+	}
+
+	return mark.Selector, nil
+}
+
+// formatMarksTable renders "[index] role "name" -> selector" lines.
+func formatMarksTable(marks []pageMark) string {
+	if len(marks) == 0 {
+		return "Marks: no visible interactive elements\n"
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Marks (%d, use ref=<index> with browser_click_element / browser_fill_element):\n", len(marks)))
+	for _, m := range marks {
+		sb.WriteString(fmt.Sprintf("[%d] %s", m.Index, m.Role))
+		if m.Name != "" {
+			sb.WriteString(fmt.Sprintf(" %q", m.Name))
+		}
+		sb.WriteString(" -> " + m.Selector)
+		if m.Ref != "" {
+			sb.WriteString(" " + refPrefix + m.Ref)
+		}
+		sb.WriteString("\n")
+	}
+	if len(marks) == marksMaxElements {
+		sb.WriteString(fmt.Sprintf("(limited to %d elements)\n", marksMaxElements))
+	}
+	return sb.String()
+}
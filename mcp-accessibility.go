@@ -0,0 +1,171 @@
+package devbrowser
+
+import (
+	stdctx "context"
+	"fmt"
+
+	"github.com/tinywasm/context"
+	"github.com/tinywasm/devbrowser/cdproto/accessibility"
+	"github.com/tinywasm/devbrowser/cdproto/cdp"
+	"github.com/tinywasm/devbrowser/cdproto/dom"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/cdproto/target"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func (b *DevBrowser) GetAccessibilityTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_get_accessibility_tree",
+			Description: "Get Chrome's computed accessibility tree as an indented outline: role, accessible name, states (checked, expanded, disabled, focusable...) and value, exactly as assistive technology sees them. More reliable than browser_get_content for roles and labels. Unnamed wrappers and ignored nodes are pruned unless all is set. Element nodes carry a ref (ref:e12) that any selector argument accepts. selector (CSS, ref, locator or a path through iframes and shadow roots like iframe#pay >>> form) limits the tree to one element; max_depth limits nesting.",
+			Args:        new(GetAccessibilityTreeArgs),
+			Resource:    "browser",
+			Action:      'r',
+			Execute: func(Ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args GetAccessibilityTreeArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				nodes, rootID, tgt, err := b.getAXTree(args.Selector)
+				if err != nil {
+					return nil, err
+				}
+
+				refs := b.axNodeRefs(nodes, tgt)
+				tree := FormatAXTree(nodes, rootID, AXTreeOptions{
+					InterestingOnly: !args.All,
+					MaxDepth:        int(args.MaxDepth),
+					Ref:             func(id cdp.BackendNodeID) string { return refs[id] },
+				})
+				if tree == "" {
+					return mcp.Text("Accessibility tree is empty (the element and its descendants are hidden from assistive technology)"), nil
+				}
+
+				var pageURL string
+				chromedp.Run(b.Ctx, chromedp.Location(&pageURL))
+
+				header := "Url: " + pageURL + "\n"
+				if args.Selector != "" {
+					header += "Root: " + args.Selector + "\n"
+				}
+				return mcp.Text(header + "\n" + tree), nil
+			},
+		},
+	}
+}
+
+// getAXTree fetches the full AX tree and returns the node to start from: the
+// document root, or the AX node of the element selector points at. Behind a
+// ref or selector path the tree is the one of the element's document, which
+// may live in a frame target (tgt).
+func (b *DevBrowser) getAXTree(selector string) ([]*accessibility.Node, accessibility.NodeID, target.ID, error) {
+	var nodes []*accessibility.Node
+	var backendID cdp.BackendNodeID
+	var frameID cdp.FrameID
+	var tgt target.ID
+	tctx := b.Ctx
+
+	var actions []chromedp.Action
+	switch {
+	case isDeepSelector(selector):
+		el, err := b.resolveElement(b.Ctx, selector)
+		if err != nil {
+			return nil, "", "", err
+		}
+		defer el.release()
+		tctx, tgt, backendID = el.ctx, el.target, el.node
+		// The frame of the element's document: Accessibility.getFullAXTree
+		// covers one document, the main frame's by default.
+		if err := el.do(func(ctx stdctx.Context) error {
+			html, exc, err := runtime.CallFunctionOn(`function() { return this.ownerDocument.documentElement; }`).
+				WithObjectID(el.obj.ObjectID).Do(ctx)
+			if err != nil {
+				return err
+			}
+			if exc != nil {
+				return exc
+			}
+			defer runtime.ReleaseObject(html.ObjectID).Do(ctx)
+			n, err := dom.DescribeNode().WithObjectID(html.ObjectID).Do(ctx)
+			if err != nil {
+				return err
+			}
+			frameID = n.FrameID
+			return nil
+		}); err != nil {
+			return nil, "", "", fmt.Errorf("Failed to get accessibility tree: %v", err)
+		}
+
+	case selector != "":
+		actions = append(actions, chromedp.QueryAfter(selector, func(ctx stdctx.Context, _ runtime.ExecutionContextID, found ...*cdp.Node) error {
+			if len(found) < 1 {
+				return fmt.Errorf("selector %q did not return any nodes", selector)
+			}
+			backendID = found[0].BackendNodeID
+			return nil
+		}, chromedp.ByQuery, chromedp.AtLeast(0)))
+	}
+	actions = append(actions,
+		accessibility.Enable(),
+		chromedp.ActionFunc(func(ctx stdctx.Context) error {
+			p := accessibility.GetFullAXTree()
+			if frameID != "" {
+				p = p.WithFrameID(frameID)
+			}
+			var err error
+			nodes, err = p.Do(ctx)
+			return err
+		}),
+	)
+
+	if err := chromedp.Run(tctx, actions...); err != nil {
+		return nil, "", "", fmt.Errorf("Failed to get accessibility tree: %v", err)
+	}
+	if len(nodes) == 0 {
+		return nil, "", "", fmt.Errorf("Failed to get accessibility tree: no nodes returned")
+	}
+
+	if selector == "" {
+		for _, n := range nodes {
+			if n.ParentID == "" {
+				return nodes, n.NodeID, tgt, nil
+			}
+		}
+		return nodes, nodes[0].NodeID, tgt, nil
+	}
+
+	for _, n := range nodes {
+		if n.BackendDOMNodeID == backendID {
+			return nodes, n.NodeID, tgt, nil
+		}
+	}
+	return nil, "", "", fmt.Errorf("element %s has no accessibility node (hidden or not rendered)", selector)
+}
+
+// axNodeRefs assigns element refs to the DOM nodes behind the AX nodes.
+// Without refs the tree is still useful, so failures are only logged.
+func (b *DevBrowser) axNodeRefs(nodes []*accessibility.Node, tgt target.ID) map[cdp.BackendNodeID]string {
+	var ids []cdp.BackendNodeID
+	for _, n := range nodes {
+		if n.BackendDOMNodeID != 0 && !n.Ignored && !axNoRefRoles[axValueString(n.Role)] {
+			ids = append(ids, n.BackendDOMNodeID)
+		}
+	}
+
+	names, err := b.nodeRefs(b.Ctx, tgt, ids)
+	if err != nil {
+		b.Logger(fmt.Sprintf("Warning: failed to assign element refs: %v", err))
+		return nil
+	}
+	refs := make(map[cdp.BackendNodeID]string, len(ids))
+	for i, id := range ids {
+		refs[id] = names[i]
+	}
+	return refs
+}
@@ -0,0 +1,78 @@
+package devbrowser
+
+import (
+	"fmt"
+
+	"github.com/tinywasm/context"
+	"github.com/tinywasm/mcp"
+)
+
+func (b *DevBrowser) GetAssertTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_assert",
+			Description: "Check the page and get PASS or FAIL with expected and actual values, without waiting (use browser_wait_for first for things that take time). type: exists, visible (default with a selector), count (expected is a number; op equals, at_least or at_most), text (of selector, or of the page), attribute, property or style (computed CSS) named name of selector, url, local_storage (key name), no_console_errors or no_failed_requests (since a checkpoint, default the page load). Value checks compare with op: equals (default; a url starting with / compares the path), contains or matches (regexp). not=true negates. selector takes a CSS selector, element ref (ref:e12), locator or selector path; ref takes an index from the last browser_screenshot with marks. checkpoint saves a named point in time after the check (alone, it only saves it) for later since.",
+			Args:        new(AssertArgs),
+			Resource:    "browser",
+			Action:      'r',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args AssertArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				a := Assertion{
+					Type:     args.Type,
+					Name:     args.Name,
+					Op:       args.Op,
+					Expected: args.Expected,
+					Not:      args.Not,
+					Since:    args.Since,
+				}
+				if args.Selector != "" || args.Ref != 0 {
+					selector, err := b.resolveElementTarget(args.Selector, args.Ref)
+					if err != nil {
+						return nil, err
+					}
+					a.Selector = selector
+					if a.Type == "" {
+						a.Type = "visible"
+					}
+				}
+
+				if a.Type == "" && args.Checkpoint != "" {
+					b.Mu.Lock()
+					b.markCheckpoint(args.Checkpoint)
+					b.Mu.Unlock()
+					return mcp.Text(fmt.Sprintf("Saved checkpoint %s", args.Checkpoint)), nil
+				}
+				if err := a.validate(); err != nil {
+					return nil, err
+				}
+				if err := b.checkSelector(a.Selector); err != nil {
+					return nil, err
+				}
+
+				res, err := b.Assert(a)
+				if err != nil {
+					return nil, err
+				}
+				text := res.String()
+				if args.Checkpoint != "" {
+					b.Mu.Lock()
+					b.markCheckpoint(args.Checkpoint)
+					b.Mu.Unlock()
+					text += "\nSaved checkpoint " + args.Checkpoint
+				}
+
+				result := mcp.Text(text)
+				result.IsError = !res.Pass
+				return result, nil
+			},
+		},
+	}
+}
@@ -9,9 +9,10 @@ import (
 	"github.com/tinywasm/mcp"
 )
 
-// GetAuditMobileJS is the mobile compatibility audit script.
+// GetAuditMobileJS is the mobile compatibility audit script, called with
+// this bound to the root of the audited sub-tree.
 const GetAuditMobileJS = `
-(selector) => {
+function() {
 	const report = {
 		viewportMeta: { missing: false, missingFit: false },
 		vhUnits: [],
@@ -21,8 +22,7 @@ const GetAuditMobileJS = `
 		fixedVh: []
 	};
 
-	const root = selector ? document.querySelector(selector) : document.body;
-	if (!root) return report;
+	const root = this;
 
 	// 1. viewport-meta check
 	const meta = document.querySelector('meta[name="viewport"]');
@@ -35,11 +35,34 @@ const GetAuditMobileJS = `
 		}
 	}
 
+	// Collect the sub-tree, through open shadow roots and same-origin
+	// frames, and the documents and shadow roots whose style sheets apply.
+	const allElements = [];
+	const scopes = [document];
+	const collect = (el) => {
+		allElements.push(el);
+		Array.from(el.children).forEach(collect);
+		if (el.shadowRoot) {
+			scopes.push(el.shadowRoot);
+			Array.from(el.shadowRoot.children).forEach(collect);
+		}
+		if (el.tagName === 'IFRAME' || el.tagName === 'FRAME') {
+			let doc = null;
+			try { doc = el.contentDocument; } catch (e) {}
+			if (doc && doc.documentElement) {
+				scopes.push(doc);
+				collect(doc.documentElement);
+			}
+		}
+	};
+	collect(root);
+
 	// Helper to find selectors with safe-area-inset or vh
 	const vhSelectors = [];
 	const safeAreaSelectors = [];
 	try {
-		for (const sheet of document.styleSheets) {
+		const sheets = scopes.flatMap(s => [...s.styleSheets, ...(s.adoptedStyleSheets || [])]);
+		for (const sheet of sheets) {
 			try {
 				const rules = sheet.cssRules || sheet.rules;
 				if (!rules) continue;
@@ -90,7 +113,7 @@ const GetAuditMobileJS = `
 		return false;
 	};
 
-	const getSelector = (el) => {
+	const shortSelector = (el) => {
 		if (el.id) return '#' + el.id;
 		if (el.className) {
 			const firstClass = String(el.className).split(' ')[0];
@@ -99,12 +122,18 @@ const GetAuditMobileJS = `
 		return el.tagName.toLowerCase();
 	};
 
+	// The short selector is often ambiguous: every reported element also
+	// gets a ref placeholder, resolved on the Go side (collectRefTargets).
+	const refTargets = window.` + refTargetsGlobal + ` = [];
+	const refIndex = new Map();
+	const getSelector = (el) => {
+		if (!refIndex.has(el)) refIndex.set(el, refTargets.push(el) - 1);
+		return shortSelector(el) + ' ref:@' + refIndex.get(el);
+	};
+
 	// Walk tree
-	const elements = root.querySelectorAll('*');
-	const allElements = [root, ...Array.from(elements)];
 	allElements.forEach((el) => {
-		if (!el || el === document || el === window) return;
-		const style = window.getComputedStyle(el);
+		const style = el.ownerDocument.defaultView.getComputedStyle(el);
 
 		// 2. vh-units
 		if (hasVhUnit(el)) {
@@ -252,7 +281,7 @@ func (b *DevBrowser) GetAuditTools() []mcp.Tool {
 	return []mcp.Tool{
 		{
 			Name:        "browser_audit_mobile",
-			Description: "Run mobile compatibility audits on the page or a sub-tree to identify issues with missing viewport metas, unsafe areas under notch/home bar, auto-zooming inputs under 16px, or small tapping target sizes. Returns a compact text report.",
+			Description: "Run mobile compatibility audits on the page or a sub-tree (selector: CSS or element ref) to identify issues with missing viewport metas, unsafe areas under notch/home bar, auto-zooming inputs under 16px, or small tapping target sizes. Open shadow roots and same-origin iframes are audited with their host page; auditing the whole page also covers cross-origin iframes. Returns a compact text report; each flagged element carries a ref (ref:e12) for the other tools.",
 			Args:        new(AuditMobileArgs),
 			Resource:    "browser",
 			Action:      'r',
@@ -269,14 +298,26 @@ func (b *DevBrowser) GetAuditTools() []mcp.Tool {
 				var pageURL string
 				var report AuditMobileReport
 
-				js := fmt.Sprintf("(%s)(%q)", GetAuditMobileJS, args.Selector)
-				err := chromedp.Run(b.Ctx,
-					chromedp.Location(&pageURL),
-					chromedp.Evaluate(js, &report),
-				)
+				root := args.Selector
+				if root == "" {
+					root = "body"
+				}
+				var refs []string
+				err := chromedp.Run(b.Ctx, chromedp.Location(&pageURL))
+				if err == nil {
+					refs, err = b.callOnElementRefs(b.Ctx, root, GetAuditMobileJS, &report)
+				}
 				if err != nil {
 					return nil, fmt.Errorf("Failed to run mobile audit: %v", err)
 				}
+				for _, list := range [][]string{report.VhUnits, report.SafeArea, report.InputZoom, report.TapTarget, report.FixedVh} {
+					for i := range list {
+						list[i] = ReplaceRefPlaceholders(list[i], refs)
+					}
+				}
+				if args.Selector == "" {
+					b.auditFrames(&report)
+				}
 
 				reportStr := FormatAuditMobileReport(pageURL, &report)
 				return mcp.Text(reportStr), nil
@@ -284,3 +325,45 @@ func (b *DevBrowser) GetAuditTools() []mcp.Tool {
 		},
 	}
 }
+
+// auditFrames runs the audit inside each cross-origin or out-of-process
+// frame, which the page script cannot reach, and adds the findings to
+// report tagged with their frame. The viewport meta stays the page's.
+func (b *DevBrowser) auditFrames(report *AuditMobileReport) {
+	frames, err := b.childFrames(b.Ctx)
+	if err != nil {
+		b.Logger(fmt.Sprintf("Warning: failed to list frames for audit: %v", err))
+		return
+	}
+
+	for _, f := range frames {
+		if f.reachable {
+			continue
+		}
+		var fr AuditMobileReport
+		if err := f.evaluate("("+GetAuditMobileJS+").call(document.body || document.documentElement)", &fr); err != nil {
+			b.Logger(fmt.Sprintf("Warning: failed to audit frame %s: %v", f.frame.URL, err))
+			continue
+		}
+		refs, err := b.collectFrameRefTargets(b.Ctx, f)
+		if err != nil {
+			b.Logger(fmt.Sprintf("Warning: failed to assign element refs to audit: %v", err))
+		}
+
+		label := " in " + b.frameLabel(b.Ctx, f)
+		for _, l := range []struct {
+			dst *[]string
+			src []string
+		}{
+			{&report.VhUnits, fr.VhUnits},
+			{&report.SafeArea, fr.SafeArea},
+			{&report.InputZoom, fr.InputZoom},
+			{&report.TapTarget, fr.TapTarget},
+			{&report.FixedVh, fr.FixedVh},
+		} {
+			for _, item := range l.src {
+				*l.dst = append(*l.dst, ReplaceRefPlaceholders(item, refs)+label)
+			}
+		}
+	}
+}
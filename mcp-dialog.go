@@ -0,0 +1,86 @@
+package devbrowser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tinywasm/context"
+	"github.com/tinywasm/mcp"
+)
+
+func (b *DevBrowser) GetDialogTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_handle_dialog",
+			Description: "Answer and configure JavaScript dialogs (alert, confirm, prompt, beforeunload), which otherwise block every action on the page. By default they are accepted as they open (prompts with their default value). policy sets that for the session: accept, dismiss or manual (dialogs stay open until answered here; other tools block meanwhile). action (accept or dismiss) answers the dialog that is open, with prompt_text as the answer to a prompt; with policy=accept, prompt_text answers future prompts. Always returns the policy, the open dialog and the log of dialogs shown.",
+			Args:        new(HandleDialogArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args HandleDialogArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				var accept bool
+				switch args.Action {
+				case "":
+				case "accept":
+					accept = true
+				case "dismiss":
+				default:
+					return nil, fmt.Errorf("unsupported action: %s. Use accept or dismiss", args.Action)
+				}
+
+				var out []string
+				if args.Policy != "" {
+					p := DialogPolicy{Action: args.Policy}
+					if args.Policy == "accept" {
+						p.PromptText = args.PromptText
+					}
+					if err := b.SetDialogPolicy(p); err != nil {
+						return nil, err
+					}
+				}
+				if args.Action != "" {
+					d, err := b.HandleDialog(accept, args.PromptText)
+					if err != nil {
+						return nil, err
+					}
+					out = append(out, "Answered "+strings.TrimPrefix(d.String(), d.Timestamp.Format("15:04:05")+" "))
+				}
+
+				p := b.GetDialogPolicy()
+				policy := "Policy: " + p.Action
+				if p.Action == "accept" && p.PromptText != "" {
+					policy += fmt.Sprintf(" (prompts answered %q)", p.PromptText)
+				}
+				out = append(out, policy)
+
+				dialogs := b.GetDialogs()
+				if n := len(dialogs); n > 0 && dialogs[n-1].Response == "" {
+					d := dialogs[n-1]
+					open := fmt.Sprintf("Open dialog: %s(%q)", d.Type, d.Message)
+					if d.Type == "prompt" {
+						open += fmt.Sprintf(" default %q", d.DefaultPrompt)
+					}
+					out = append(out, open)
+				}
+				if len(dialogs) == 0 {
+					out = append(out, "No dialogs shown")
+				} else {
+					shown := dialogs[max(0, len(dialogs)-20):]
+					out = append(out, fmt.Sprintf("Dialogs shown (%d):", len(dialogs)))
+					for _, d := range shown {
+						out = append(out, "  "+d.String())
+					}
+				}
+				return mcp.Text(strings.Join(out, "\n")), nil
+			},
+		},
+	}
+}
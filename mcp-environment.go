@@ -0,0 +1,89 @@
+package devbrowser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tinywasm/context"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+// environmentReadJS reads back what the page sees of the environment.
+const environmentReadJS = `(() => {
+	const o = Intl.DateTimeFormat().resolvedOptions();
+	return {timezone: o.timeZone, locale: o.locale, languages: navigator.languages.join(','), userAgent: navigator.userAgent};
+})()`
+
+type environmentSeen struct {
+	Timezone  string `json:"timezone"`
+	Locale    string `json:"locale"`
+	Languages string `json:"languages"`
+	UserAgent string `json:"userAgent"`
+}
+
+func (b *DevBrowser) GetEnvironmentTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_emulate_environment",
+			Description: "Emulate where and by whom the page is browsed: geolocation (lat,lon or lat,lon,accuracy in meters, e.g. 40.4168,-3.7038; or unavailable), timezone (IANA id, e.g. Europe/Madrid), locale (e.g. es-ES; also sets Accept-Language unless accept_language is given), accept_language (e.g. es-ES,es;q=0.9), user_agent, and client hints (ua_platform e.g. Android, ua_platform_version, ua_model, ua_mobile). Only the given settings change; 'default' clears one and reset clears them all. Stays on top of browser_emulate_device. This change is persisted and re-applied every time the browser opens.",
+			Args:        new(EmulateEnvironmentArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				var args EmulateEnvironmentArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				env := b.GetEnvironment()
+				if args.Reset {
+					env = Environment{}
+				}
+				set := func(field *string, value string) {
+					switch value {
+					case "":
+					case "default":
+						*field = ""
+					default:
+						*field = value
+					}
+				}
+				set(&env.Geolocation, args.Geolocation)
+				set(&env.Timezone, args.Timezone)
+				set(&env.Locale, args.Locale)
+				set(&env.AcceptLanguage, args.AcceptLanguage)
+				set(&env.UserAgent, args.UserAgent)
+				if args.UaPlatform != "" {
+					// Client hints are set as a whole.
+					env.UAPlatform, env.UAPlatformVersion, env.UAModel, env.UAMobile = "", "", "", false
+					if args.UaPlatform != "default" {
+						env.UAPlatform = args.UaPlatform
+						env.UAPlatformVersion = args.UaPlatformVersion
+						env.UAModel = args.UaModel
+						env.UAMobile = args.UaMobile
+					}
+				} else if args.UaPlatformVersion != "" || args.UaModel != "" || args.UaMobile {
+					return nil, fmt.Errorf("client hints need ua_platform (e.g. Android, macOS or Windows)")
+				}
+
+				if err := b.SetEnvironment(env); err != nil {
+					return nil, err
+				}
+
+				if !b.IsOpenFlag || b.Ctx == nil {
+					return mcp.Text(fmt.Sprintf("Environment: %s (applied when the browser opens)", env)), nil
+				}
+				var seen environmentSeen
+				if err := chromedp.Run(b.Ctx, chromedp.Evaluate(environmentReadJS, &seen)); err != nil {
+					return mcp.Text(fmt.Sprintf("Environment: %s", env)), nil
+				}
+				var sb strings.Builder
+				fmt.Fprintf(&sb, "Environment: %s\n", env)
+				fmt.Fprintf(&sb, "Page sees: timezone %s, locale %s, languages %s\n", seen.Timezone, seen.Locale, seen.Languages)
+				fmt.Fprintf(&sb, "User agent: %s", seen.UserAgent)
+				return mcp.Text(sb.String()), nil
+			},
+		},
+	}
+}
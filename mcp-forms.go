@@ -0,0 +1,255 @@
+package devbrowser
+
+import (
+	stdctx "context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tinywasm/context"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func (b *DevBrowser) GetFormTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_select_option",
+			Description: "Select an option of a <select>, given by CSS selector, element ref (ref:e12), locator (text=Save, role=button[name=\"Save\"], label=Email) or a path through iframes and shadow roots (iframe#pay >>> select[name=country]), by its value or visible label, and fire input and change. by is auto (value first, then label), value or label. For <select multiple>, put one value per line. Instead of a selector, ref takes an index from the last browser_screenshot with marks.",
+			Args:        new(SelectOptionArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args SelectOptionArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				selector, err := b.resolveElementTarget(args.Selector, args.Ref)
+				if err != nil {
+					return nil, err
+				}
+				if err := b.checkSelector(selector); err != nil {
+					return nil, err
+				}
+				by, err := selectBy(args.By)
+				if err != nil {
+					return nil, err
+				}
+
+				waitAfter := args.WaitAfter
+				if waitAfter == 0 {
+					waitAfter = 100
+				}
+
+				timeout := args.Timeout
+				if timeout == 0 {
+					timeout = 5000
+				}
+
+				tctx, cancel := stdctx.WithTimeout(b.Ctx, time.Duration(timeout)*time.Millisecond)
+				defer cancel()
+
+				labels, err := b.selectOptions(tctx, selector, splitLines(args.Value), by)
+				if err != nil {
+					return nil, fmt.Errorf("Error selecting option of %s: %v", selector, err)
+				}
+				if waitAfter > 0 {
+					chromedp.Run(tctx, chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond))
+				}
+
+				return mcp.Text(fmt.Sprintf("Selected %s in %s", strings.Join(labels, ", "), selector)), nil
+			},
+		},
+		{
+			Name:        "browser_check_element",
+			Description: "Check a checkbox or radio, or uncheck a checkbox with uncheck=true, given by CSS selector, element ref (ref:e12), locator (text=Save, role=button[name=\"Save\"], label=Email) or a path through iframes and shadow roots. Clicks it like a user only when its state differs, so handlers run; inputs hidden behind a styled label are clicked in JS. Instead of a selector, ref takes an index from the last browser_screenshot with marks.",
+			Args:        new(CheckElementArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args CheckElementArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				selector, err := b.resolveElementTarget(args.Selector, args.Ref)
+				if err != nil {
+					return nil, err
+				}
+				if err := b.checkSelector(selector); err != nil {
+					return nil, err
+				}
+
+				waitAfter := args.WaitAfter
+				if waitAfter == 0 {
+					waitAfter = 100
+				}
+
+				timeout := args.Timeout
+				if timeout == 0 {
+					timeout = 5000
+				}
+
+				tctx, cancel := stdctx.WithTimeout(b.Ctx, time.Duration(timeout)*time.Millisecond)
+				defer cancel()
+
+				state := "checked"
+				if args.Uncheck {
+					state = "unchecked"
+				}
+				changed, err := b.setChecked(tctx, selector, !args.Uncheck)
+				if err != nil {
+					return nil, fmt.Errorf("Error setting %s %s: %v", selector, state, err)
+				}
+				if !changed {
+					return mcp.Text(fmt.Sprintf("%s was already %s", selector, state)), nil
+				}
+				if waitAfter > 0 {
+					chromedp.Run(tctx, chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond))
+				}
+
+				return mcp.Text(fmt.Sprintf("%s is now %s", selector, state)), nil
+			},
+		},
+		{
+			Name:        "browser_upload_files",
+			Description: "Upload local files through an <input type=file>, given by CSS selector, element ref (ref:e12), locator (text=Save, role=button[name=\"Save\"], label=Email) or a path through iframes and shadow roots, as if picked in the file chooser; input and change fire. files is one path per line, absolute or relative to the working directory; .. is not allowed and the files must exist. Several files need <input multiple>.",
+			Args:        new(UploadFilesArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args UploadFilesArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				selector, err := b.resolveElementTarget(args.Selector, args.Ref)
+				if err != nil {
+					return nil, err
+				}
+				if err := b.checkSelector(selector); err != nil {
+					return nil, err
+				}
+				paths, err := validateUploadPaths(splitLines(args.Files))
+				if err != nil {
+					return nil, err
+				}
+
+				waitAfter := args.WaitAfter
+				if waitAfter == 0 {
+					waitAfter = 100
+				}
+
+				timeout := args.Timeout
+				if timeout == 0 {
+					timeout = 5000
+				}
+
+				tctx, cancel := stdctx.WithTimeout(b.Ctx, time.Duration(timeout)*time.Millisecond)
+				defer cancel()
+
+				if err := b.uploadFiles(tctx, selector, paths); err != nil {
+					return nil, fmt.Errorf("Error uploading to %s: %v", selector, err)
+				}
+				if waitAfter > 0 {
+					chromedp.Run(tctx, chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond))
+				}
+
+				return mcp.Text(fmt.Sprintf("Uploaded %d file(s) to %s:\n%s", len(paths), selector, strings.Join(paths, "\n"))), nil
+			},
+		},
+		{
+			Name:        "browser_fill_form",
+			Description: "Fill several form fields in one call and report, per field, whether it worked and its validationMessage. fields is a JSON object filled in order: keys are field names (matched by name attribute) or selectors (CSS, ref:e12, iframe#pay >>> input); values are text to type (replacing the current value), the value or label of a <select> option or radio, true/false for checkboxes and radios, or an array for <select multiple> and file paths to upload. Example: {\"email\": \"a@b.c\", \"country\": \"Spain\", \"#terms\": true}. A failing field does not stop the others. timeout applies to each field.",
+			Args:        new(FillFormArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args FillFormArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				fields, err := parseFormFields(args.Fields)
+				if err != nil {
+					return nil, err
+				}
+
+				waitAfter := args.WaitAfter
+				if waitAfter == 0 {
+					waitAfter = 100
+				}
+
+				timeout := args.Timeout
+				if timeout == 0 {
+					timeout = 5000
+				}
+
+				var report strings.Builder
+				filled, invalid := 0, 0
+				for _, f := range fields {
+					line, ok, valid := b.fillFormField(f, time.Duration(timeout)*time.Millisecond)
+					if ok {
+						filled++
+					}
+					if !valid {
+						invalid++
+					}
+					report.WriteString(line + "\n")
+				}
+				if waitAfter > 0 {
+					chromedp.Run(b.Ctx, chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond))
+				}
+
+				header := fmt.Sprintf("Filled %d of %d fields", filled, len(fields))
+				if invalid > 0 {
+					header += fmt.Sprintf(", %d invalid", invalid)
+				}
+				return mcp.Text(header + ":\n" + report.String()), nil
+			},
+		},
+	}
+}
+
+// fillFormField fills one browser_fill_form field and returns its report
+// line, whether filling worked and whether the field is valid afterwards.
+func (b *DevBrowser) fillFormField(f formField, timeout time.Duration) (line string, ok, valid bool) {
+	tctx, cancel := stdctx.WithTimeout(b.Ctx, timeout)
+	defer cancel()
+
+	selector, err := b.fieldSelector(tctx, f.key)
+	if err == nil {
+		var value string
+		value, err = b.fillField(tctx, selector, f.value)
+		if err == nil {
+			var v fieldValidity
+			if err = b.callField(tctx, selector, fieldValidityJS, &v); err == nil {
+				if value == "" {
+					value = v.Value
+				}
+				if !v.Valid {
+					return fmt.Sprintf("invalid %s = %q: %s", f.key, value, v.Message), true, false
+				}
+				return fmt.Sprintf("ok      %s = %q", f.key, value), true, true
+			}
+		}
+	}
+	return fmt.Sprintf("error   %s: %v", f.key, err), false, true
+}
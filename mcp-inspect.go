@@ -4,19 +4,20 @@ import (
 	"fmt"
 
 	"github.com/tinywasm/context"
-	"github.com/tinywasm/devbrowser/chromedp"
 	"github.com/tinywasm/mcp"
 )
 
 // InspectElementJS extracts detailed element information like Chrome DevTools.
 // Returns a JSON-like string with box model, position, styles, and accessibility info.
+// It is a function declaration called with this bound to the element.
 const InspectElementJS = `
-(selector) => {
-	const el = document.querySelector(selector);
-	if (!el) return JSON.stringify({ error: 'Element not found: ' + selector });
+function() {
+	const el = this;
 
+	// The element may live in a frame: use its own window.
+	const view = el.ownerDocument.defaultView;
 	const rect = el.getBoundingClientRect();
-	const style = window.getComputedStyle(el);
+	const style = view.getComputedStyle(el);
 
 	// Box Model
 	const boxModel = {
@@ -96,11 +97,16 @@ const InspectElementJS = `
 	};
 
 	// Element identity
+	const host = el.getRootNode().host;
 	const identity = {
 		tagName: el.tagName.toLowerCase(),
 		id: el.id || null,
 		className: el.className || null,
-		name: el.getAttribute('name')
+		name: el.getAttribute('name'),
+		// Where the element lives: the shadow host it is rendered in and
+		// the document of the frame it belongs to (null on the page itself).
+		shadowHost: host ? host.tagName.toLowerCase() + (host.id ? '#' + host.id : '') : null,
+		frame: view !== view.top ? el.ownerDocument.URL : null
 	};
 
 	return JSON.stringify({
@@ -119,7 +125,7 @@ func (b *DevBrowser) GetInspectTools() []mcp.Tool {
 	return []mcp.Tool{
 		{
 			Name:        "browser_inspect_element",
-			Description: "Inspect a specific element to get detailed CSS properties like Chrome DevTools. Returns box model (width, height, padding, margin, border), position (top, left, offset), layout (display, flex, grid), typography (font, color), and accessibility info.",
+			Description: "Inspect a specific element to get detailed CSS properties like Chrome DevTools. Returns box model (width, height, padding, margin, border), position (top, left, offset), layout (display, flex, grid), typography (font, color), and accessibility info. selector takes a CSS selector, an element ref (ref:e12), locator (text=Save, role=button[name=\"Save\"], label=Email) or a path through iframes and shadow roots (iframe#pay >>> button.submit); positions inside a frame are relative to the frame.",
 			Args: new(InspectElementArgs),
 			Resource:    "browser",
 			Action:      'r',
@@ -134,11 +140,7 @@ func (b *DevBrowser) GetInspectTools() []mcp.Tool {
 				}
 
 				var result string
-				js := fmt.Sprintf("(%s)(%q)", InspectElementJS, args.Selector)
-
-				err := chromedp.Run(b.Ctx,
-					chromedp.Evaluate(js, &result),
-				)
+				err := b.callOnElement(b.Ctx, args.Selector, InspectElementJS, &result)
 
 				if err != nil {
 					return nil, fmt.Errorf("Failed to inspect element: %v", err)
@@ -3,7 +3,7 @@ package devbrowser
 import (
 	stdctx "context"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tinywasm/context"
@@ -16,7 +16,7 @@ func (b *DevBrowser) GetInteractionTools() []mcp.Tool {
 	return []mcp.Tool{
 		{
 			Name:        "browser_click_element",
-			Description: "Click DOM element by CSS selector to test interactions, trigger events, or simulate user actions. Useful for testing buttons, links, and interactive components.",
+			Description: "Click DOM element by CSS selector, element ref (ref:e12), locator (text=Save, role=button[name=\"Save\"], label=Email) or a path through iframes and shadow roots (iframe#pay >>> button.submit) to test interactions, trigger events, or simulate user actions. Useful for testing buttons, links, and interactive components. Instead of a selector, ref takes an index from the last browser_screenshot with marks. button=right opens context menus, click_count=2 double-clicks and hold (ms) presses and holds (long press); these are real mouse event sequences.",
 			Args: new(ClickElementArgs),
 			Resource:    "browser",
 			Action:      'u',
@@ -30,6 +30,29 @@ func (b *DevBrowser) GetInteractionTools() []mcp.Tool {
 					return nil, err
 				}
 
+				selector, err := b.resolveElementTarget(args.Selector, args.Ref)
+				if err != nil {
+					return nil, err
+				}
+				btn, err := parseMouseButton(args.Button)
+				if err != nil {
+					return nil, err
+				}
+				count := int(args.ClickCount)
+				if count == 0 {
+					count = 1
+				}
+				if count < 1 || count > 3 {
+					return nil, fmt.Errorf("click_count must be between 1 and 3, got %d", args.ClickCount)
+				}
+				if args.Hold < 0 {
+					return nil, fmt.Errorf("hold must be positive, got %d", args.Hold)
+				}
+				sel, qopts, remote, err := b.elementQuery(b.Ctx, selector, chromedp.ByQuery)
+				if err != nil {
+					return nil, err
+				}
+
 				waitAfter := args.WaitAfter
 				if waitAfter == 0 {
 					waitAfter = 100
@@ -40,34 +63,75 @@ func (b *DevBrowser) GetInteractionTools() []mcp.Tool {
 					timeout = 5000
 				}
 
-				tctx, cancel := stdctx.WithTimeout(b.Ctx, time.Duration(timeout)*time.Millisecond)
+				// The timeout covers finding the element, not the hold.
+				hold := time.Duration(args.Hold) * time.Millisecond
+				tctx, cancel := stdctx.WithTimeout(b.Ctx, time.Duration(timeout)*time.Millisecond+hold*time.Duration(count))
 				defer cancel()
 
+				if btn.name != input.Left || count > 1 || hold > 0 {
+					// No JS fallback here: .click() cannot right-click,
+					// double-click or hold.
+					err := chromedp.Run(tctx, chromedp.ActionFunc(func(ctx stdctx.Context) error {
+						x, y, err := b.elementPoint(ctx, selector)
+						if err == nil {
+							err = b.mouseMove(ctx, x, y, pointerSteps, mouseButton{})
+						}
+						if err == nil {
+							err = mouseClick(ctx, x, y, btn, count, hold)
+						}
+						return err
+					}))
+					if err != nil {
+						return nil, fmt.Errorf("Error clicking element %s: %v", selector, err)
+					}
+					if waitAfter > 0 {
+						chromedp.Run(tctx, chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond))
+					}
+
+					verb := [...]string{1: "Clicked", 2: "Double-clicked", 3: "Triple-clicked"}[count]
+					if btn.name != input.Left {
+						verb += " with the " + string(btn.name) + " button"
+					}
+					if hold > 0 {
+						verb += fmt.Sprintf(" holding %v", hold)
+					}
+					return mcp.Text(fmt.Sprintf("%s element: %s", verb, selector)), nil
+				}
+
+				if remote {
+					// Out-of-process frame: chromedp cannot query it, click
+					// its center through the page.
+					if err := b.clickRemoteElement(tctx, selector); err != nil {
+						return nil, fmt.Errorf("Error clicking element %s: %v", selector, err)
+					}
+					if waitAfter > 0 {
+						chromedp.Run(tctx, chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond))
+					}
+					return mcp.Text(fmt.Sprintf("Clicked element: %s", selector)), nil
+				}
+
 				// 1. Wait for element to be present in DOM (WaitReady)
-				err := chromedp.Run(tctx, chromedp.WaitReady(args.Selector, chromedp.ByQuery))
+				err = chromedp.Run(tctx, chromedp.WaitReady(sel, qopts...))
 				if err != nil {
-					return nil, fmt.Errorf("Error waiting for element %s: %v", args.Selector, err)
+					return nil, fmt.Errorf("Error waiting for element %s: %v", selector, err)
 				}
 
 				// 2. Attempt standard click
 				// Use chromedp.MouseClickNode if we want more robust clicking?
 				// No, let's just use Click but maybe it's being blocked.
-				err = chromedp.Run(tctx, chromedp.Click(args.Selector, chromedp.ByQuery))
+				err = chromedp.Run(tctx, chromedp.Click(sel, qopts...))
 
 				var msg string
 				if err == nil {
-					msg = fmt.Sprintf("Clicked element: %s", args.Selector)
+					msg = fmt.Sprintf("Clicked element: %s", selector)
 				} else {
 					// 3. Fallback: JavaScript click
-					b.Logger(fmt.Sprintf("Standard click failed (%v), attempting JS fallback for: %s", err, args.Selector))
-
-					// Use strconv.Quote to safely escape the selector for JS string
-					jsClick := fmt.Sprintf("document.querySelector(%s).click()", strconv.Quote(args.Selector))
+					b.Logger(fmt.Sprintf("Standard click failed (%v), attempting JS fallback for: %s", err, selector))
 
-					if err := chromedp.Run(tctx, chromedp.Evaluate(jsClick, nil)); err != nil {
-						return nil, fmt.Errorf("JS click fallback failed for %s: %v", args.Selector, err)
+					if err := b.callOnElement(tctx, selector, `function() { this.click(); }`, nil); err != nil {
+						return nil, fmt.Errorf("JS click fallback failed for %s: %v", selector, err)
 					}
-					msg = fmt.Sprintf("Clicked element (JS fallback): %s", args.Selector)
+					msg = fmt.Sprintf("Clicked element (JS fallback): %s", selector)
 				}
 
 				// Wait after action
@@ -78,9 +142,158 @@ func (b *DevBrowser) GetInteractionTools() []mcp.Tool {
 				return mcp.Text(msg), nil
 			},
 		},
+		{
+			Name:        "browser_hover_element",
+			Description: "Move the mouse over an element, given by CSS selector, element ref (ref:e12), locator (text=Save), path through iframes and shadow roots (my-app >>> .menu) or marks ref, along a path of real mouse moves, to trigger :hover styles, tooltips and hover menus. Use wait_after (ms) for delayed tooltips; the mouse stays there until the next pointer action.",
+			Args: new(HoverElementArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args HoverElementArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				selector, err := b.resolveElementTarget(args.Selector, args.Ref)
+				if err != nil {
+					return nil, err
+				}
+				if err := b.checkSelector(selector); err != nil {
+					return nil, err
+				}
+
+				waitAfter := args.WaitAfter
+				if waitAfter == 0 {
+					waitAfter = 100
+				}
+
+				timeout := args.Timeout
+				if timeout == 0 {
+					timeout = 5000
+				}
+
+				tctx, cancel := stdctx.WithTimeout(b.Ctx, time.Duration(timeout)*time.Millisecond)
+				defer cancel()
+
+				err = chromedp.Run(tctx, chromedp.ActionFunc(func(ctx stdctx.Context) error {
+					x, y, err := b.elementPoint(ctx, selector)
+					if err != nil {
+						return err
+					}
+					return b.mouseMove(ctx, x, y, pointerSteps, mouseButton{})
+				}))
+				if err != nil {
+					return nil, fmt.Errorf("Error hovering element %s: %v", selector, err)
+				}
+				if waitAfter > 0 {
+					chromedp.Run(tctx, chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond))
+				}
+
+				return mcp.Text(fmt.Sprintf("Hovered element: %s", selector)), nil
+			},
+		},
+		{
+			Name:        "browser_drag_element",
+			Description: "Drag an element (CSS selector, element ref, locator, path through iframes and shadow roots, or marks ref) with the left mouse button and drop it on another element (to) or at viewport coordinates (to_x, to_y). Works for pointer/mouse-based drag handlers (sliders, sortable lists, canvases) and for HTML5 draggable elements, whose drag is completed with real dragenter, dragover and drop events. steps sets the number of intermediate mouse moves (default 10); hold (ms) keeps the button down before moving, for press-and-hold-to-drag UIs.",
+			Args: new(DragElementArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args DragElementArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				selector, err := b.resolveElementTarget(args.Selector, args.Ref)
+				if err != nil {
+					return nil, err
+				}
+				if err := b.checkSelector(selector); err != nil {
+					return nil, err
+				}
+				hasPoint := args.ToX != 0 || args.ToY != 0
+				switch {
+				case args.To != "" && hasPoint:
+					return nil, fmt.Errorf("use either to or to_x/to_y, not both")
+				case args.To == "" && !hasPoint:
+					return nil, fmt.Errorf("to or to_x/to_y required")
+				case args.To != "":
+					if err := b.checkSelector(args.To); err != nil {
+						return nil, err
+					}
+				}
+				if args.Steps < 0 || args.Hold < 0 {
+					return nil, fmt.Errorf("steps and hold must be positive")
+				}
+				steps := int(args.Steps)
+				if steps == 0 {
+					steps = pointerSteps
+				}
+
+				waitAfter := args.WaitAfter
+				if waitAfter == 0 {
+					waitAfter = 100
+				}
+
+				timeout := args.Timeout
+				if timeout == 0 {
+					timeout = 5000
+				}
+
+				hold := time.Duration(args.Hold) * time.Millisecond
+				tctx, cancel := stdctx.WithTimeout(b.Ctx, time.Duration(timeout)*time.Millisecond+hold)
+				defer cancel()
+
+				dest := fmt.Sprintf("(%d, %d)", args.ToX, args.ToY)
+				var html5 bool
+				err = chromedp.Run(tctx, chromedp.ActionFunc(func(ctx stdctx.Context) error {
+					// The drop target is measured first: scrolling the source
+					// into view afterwards keeps it in place when both fit.
+					toX, toY := float64(args.ToX), float64(args.ToY)
+					if args.To != "" {
+						var err error
+						if toX, toY, err = b.elementPoint(ctx, args.To); err != nil {
+							return fmt.Errorf("drop target %s: %v", args.To, err)
+						}
+					}
+					x, y, err := b.elementPoint(ctx, selector)
+					if err != nil {
+						return err
+					}
+					if err := b.mouseMove(ctx, x, y, pointerSteps, mouseButton{}); err != nil {
+						return err
+					}
+					html5, err = b.mouseDrag(ctx, toX, toY, steps, hold)
+					return err
+				}))
+				if args.To != "" {
+					dest = args.To
+				}
+				if err != nil {
+					return nil, fmt.Errorf("Error dragging element %s to %s: %v", selector, dest, err)
+				}
+				if waitAfter > 0 {
+					chromedp.Run(tctx, chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond))
+				}
+
+				kind := "mouse drag"
+				if html5 {
+					kind = "HTML5 drag and drop"
+				}
+				return mcp.Text(fmt.Sprintf("Dragged %s to %s (%s)", selector, dest, kind)), nil
+			},
+		},
 		{
 			Name:        "browser_fill_element",
-			Description: "Fill a form field (input, textarea) with text. Simulates typing.",
+			Description: "Fill a form field (input, textarea), given by CSS selector, element ref (ref:e12), locator (text=Save, role=button[name=\"Save\"], label=Email) or a path through iframes and shadow roots (iframe#pay >>> input[name=card]), with text. Simulates typing: the current value is selected and deleted first, unless append is set. Instead of a selector, ref takes an index from the last browser_screenshot with marks. For selects, checkboxes, radios and file inputs use browser_select_option, browser_check_element and browser_upload_files, or browser_fill_form for a whole form.",
 			Args: new(FillElementArgs),
 			Resource:    "browser",
 			Action:      'u',
@@ -94,6 +307,70 @@ func (b *DevBrowser) GetInteractionTools() []mcp.Tool {
 					return nil, err
 				}
 
+				selector, err := b.resolveElementTarget(args.Selector, args.Ref)
+				if err != nil {
+					return nil, err
+				}
+				if err := b.checkSelector(selector); err != nil {
+					return nil, err
+				}
+
+				waitAfter := args.WaitAfter
+				if waitAfter == 0 {
+					waitAfter = 100
+				}
+
+				timeout := args.Timeout
+				if timeout == 0 {
+					timeout = 5000
+				}
+
+				tctx, cancel := stdctx.WithTimeout(b.Ctx, time.Duration(timeout)*time.Millisecond)
+				defer cancel()
+
+				if err := b.typeInto(tctx, selector, args.Value, !args.Append); err != nil {
+					return nil, fmt.Errorf("Error filling element %s: %v", selector, err)
+				}
+				if waitAfter > 0 {
+					chromedp.Run(tctx, chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond))
+				}
+
+				if args.Append {
+					return mcp.Text(fmt.Sprintf("Appended '%s' to element %s", args.Value, selector)), nil
+				}
+				return mcp.Text(fmt.Sprintf("Filled element %s with '%s'", selector, args.Value)), nil
+			},
+		},
+		{
+			Name:        "browser_press_key",
+			Description: "Press keys and shortcuts as real keyboard events (keydown, keypress, keyup) to test keyboard navigation, form submission, modal dismissal and hotkeys. keys is one chord or several separated by spaces: Enter, Escape, Tab, ArrowDown, PageUp, F5, a, Control+K, Control+Shift+P, Meta+A, \"Tab Tab Enter\". Modifiers are Control (Ctrl), Shift, Alt (Option) and Meta (Cmd); Space and Plus name those keys. Shift types the US-layout shifted character (Shift+1 types !). With a CSS selector, element ref (ref:e12), locator (text=Save), path through iframes and shadow roots (iframe#pay >>> input) or marks ref, the element is focused first; otherwise keys go to the focused element.",
+			Args: new(PressKeyArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args PressKeyArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				chords, err := parseKeys(args.Keys)
+				if err != nil {
+					return nil, err
+				}
+				var selector string
+				if args.Selector != "" || args.Ref != 0 {
+					if selector, err = b.resolveElementTarget(args.Selector, args.Ref); err != nil {
+						return nil, err
+					}
+					if err := b.checkSelector(selector); err != nil {
+						return nil, err
+					}
+				}
+
 				waitAfter := args.WaitAfter
 				if waitAfter == 0 {
 					waitAfter = 100
@@ -107,22 +384,42 @@ func (b *DevBrowser) GetInteractionTools() []mcp.Tool {
 				tctx, cancel := stdctx.WithTimeout(b.Ctx, time.Duration(timeout)*time.Millisecond)
 				defer cancel()
 
-				err := chromedp.Run(tctx,
-					chromedp.WaitVisible(args.Selector, chromedp.ByQuery),
-					chromedp.SendKeys(args.Selector, args.Value, chromedp.ByQuery),
-					chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond),
-				)
+				if selector != "" {
+					if err := b.focusElement(tctx, selector); err != nil {
+						return nil, fmt.Errorf("Error focusing element %s: %v", selector, err)
+					}
+				}
 
+				names := make([]string, len(chords))
+				err = chromedp.Run(tctx, chromedp.ActionFunc(func(ctx stdctx.Context) error {
+					for i, c := range chords {
+						names[i] = c.String()
+						for _, e := range c.events() {
+							if err := e.Do(ctx); err != nil {
+								return err
+							}
+						}
+					}
+					return nil
+				}))
 				if err != nil {
-					return nil, fmt.Errorf("Error filling element %s: %v", args.Selector, err)
+					return nil, fmt.Errorf("Error pressing %s: %v", args.Keys, err)
+				}
+
+				if waitAfter > 0 {
+					chromedp.Run(tctx, chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond))
 				}
 
-				return mcp.Text(fmt.Sprintf("Filled element %s with '%s'", args.Selector, args.Value)), nil
+				msg := "Pressed " + strings.Join(names, " ")
+				if selector != "" {
+					msg += " on " + selector
+				}
+				return mcp.Text(msg), nil
 			},
 		},
 		{
 			Name:        "browser_swipe_element",
-			Description: "Simulate a swipe gesture on an element (up, down, left, right).",
+			Description: "Simulate a swipe gesture on an element (up, down, left, right), given by CSS selector, element ref (ref:e12), locator (text=Save, role=button[name=\"Save\"], label=Email) or a path through iframes and shadow roots (my-app >>> .list). Uses touch events when a touch device is emulated and a mouse drag otherwise; see browser_gesture for flings, pinches and more.",
 			Args: new(SwipeElementArgs),
 			Resource:    "browser",
 			Action:      'u',
@@ -136,73 +433,82 @@ func (b *DevBrowser) GetInteractionTools() []mcp.Tool {
 					return nil, err
 				}
 
-				// Swipe Logic
-				err := chromedp.Run(b.Ctx,
-					chromedp.WaitVisible(args.Selector, chromedp.ByQuery),
-					chromedp.ActionFunc(func(ctx stdctx.Context) error {
-						// 1. Get element dimensions to find center
-						// We use javascript to get bounding client rect as it is reliable
-						script := fmt.Sprintf(`
-							(function() {
-								const el = document.querySelector('%s');
-								if (!el) return null;
-								const rect = el.getBoundingClientRect();
-								return {x: rect.left + rect.width/2, y: rect.top + rect.height/2};
-							})()
-						`, args.Selector)
-
-						var res map[string]float64
-						// Need to pass standard context.Context here
-						if err := chromedp.Evaluate(script, &res).Do(ctx); err != nil {
-							return err
-						}
+				if err := b.checkSelector(args.Selector); err != nil {
+					return nil, err
+				}
+				o, err := newGestureOptions("swipe", args.Direction, args.Distance, 0, 0, "auto")
+				if err != nil {
+					return nil, err
+				}
 
-						startX := res["x"]
-						startY := res["y"]
-						endX := startX
-						endY := startY
-
-						switch args.Direction {
-						case "up":
-							endY -= float64(args.Distance)
-						case "down":
-							endY += float64(args.Distance)
-						case "left":
-							endX -= float64(args.Distance)
-						case "right":
-							endX += float64(args.Distance)
-						}
+				var kind string
+				err = chromedp.Run(b.Ctx, chromedp.ActionFunc(func(ctx stdctx.Context) error {
+					var err error
+					kind, err = b.performGesture(ctx, args.Selector, o)
+					return err
+				}))
+				if err != nil {
+					return nil, fmt.Errorf("Error swiping element %s: %v", args.Selector, err)
+				}
 
-						// Perform Mouse sequence using cdproto/input
-						// Move to start
-						p1 := input.DispatchMouseEvent(input.MouseMoved, startX, startY)
-						if err := p1.Do(ctx); err != nil {
-							return err
-						}
-						// Mouse Down
-						p2 := input.DispatchMouseEvent(input.MousePressed, startX, startY).WithButton("left").WithClickCount(1)
-						if err := p2.Do(ctx); err != nil {
-							return err
-						}
-						// Move to end (swipe)
-						p3 := input.DispatchMouseEvent(input.MouseMoved, endX, endY).WithButton("left")
-						if err := p3.Do(ctx); err != nil {
-							return err
-						}
-						// Mouse Up
-						p4 := input.DispatchMouseEvent(input.MouseReleased, endX, endY).WithButton("left").WithClickCount(1)
-						if err := p4.Do(ctx); err != nil {
-							return err
-						}
-						return nil
-					}),
-				)
+				return mcp.Text(fmt.Sprintf("Swiped %s on %s by %gpx (%s)", args.Direction, args.Selector, o.distance, kind)), nil
+			},
+		},
+		{
+			Name:        "browser_gesture",
+			Description: "Perform a gesture on the center of an element (CSS selector, element ref, locator, path through iframes and shadow roots, or marks ref): tap, double_tap, long_press, swipe and fling (with direction up/down/left/right and distance px), pinch_in and pinch_out (distance = change of finger spread in px) or rotate (two fingers, angle in degrees, clockwise; default 90). duration (ms) is the contact time of taps and presses and the travel time of moving gestures, so it sets the velocity. input=auto (default) sends multi-touch events (touchstart/touchmove/touchend) when a touch device is emulated and the mouse equivalent otherwise (clicks, a drag, Control+wheel for pinches); touch or mouse force one.",
+			Args: new(GestureArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args GestureArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
 
+				selector, err := b.resolveElementTarget(args.Selector, args.Ref)
 				if err != nil {
-					return nil, fmt.Errorf("Error swiping element %s: %v", args.Selector, err)
+					return nil, err
+				}
+				if err := b.checkSelector(selector); err != nil {
+					return nil, err
+				}
+				o, err := newGestureOptions(args.Gesture, args.Direction, args.Distance, args.Duration, args.Angle, args.Input)
+				if err != nil {
+					return nil, err
+				}
+
+				waitAfter := args.WaitAfter
+				if waitAfter == 0 {
+					waitAfter = 100
+				}
+
+				timeout := args.Timeout
+				if timeout == 0 {
+					timeout = 5000
+				}
+
+				tctx, cancel := stdctx.WithTimeout(b.Ctx, time.Duration(timeout)*time.Millisecond+o.duration)
+				defer cancel()
+
+				var kind string
+				err = chromedp.Run(tctx, chromedp.ActionFunc(func(ctx stdctx.Context) error {
+					var err error
+					kind, err = b.performGesture(ctx, selector, o)
+					return err
+				}))
+				if err != nil {
+					return nil, fmt.Errorf("Error performing %s on %s: %v", o.name, selector, err)
+				}
+				if waitAfter > 0 {
+					chromedp.Run(tctx, chromedp.Sleep(time.Duration(waitAfter)*time.Millisecond))
 				}
 
-				return mcp.Text(fmt.Sprintf("Swiped %s on %s by %dpx", args.Direction, args.Selector, args.Distance)), nil
+				return mcp.Text(fmt.Sprintf("Performed %s on %s with %s input", o.name, selector, kind)), nil
 			},
 		},
 	}
@@ -127,14 +127,29 @@ func (b *DevBrowser) applyDeviceEmulation() error {
 	b.Mu.Lock()
 	mode := b.ViewportMode
 	devName := b.ViewportDevice
+	env := b.environment
 	b.Mu.Unlock()
 
+	actions, err := deviceEmulationActions(mode, devName)
+	if err != nil {
+		return err
+	}
+	// The device sets its own user agent; keep the emulated one on top.
+	actions = append(actions, userAgentAction(env, deviceUserAgent(mode, devName)))
+
+	return chromedp.Run(b.Ctx, actions...)
+}
+
+// deviceEmulationActions builds the CDP commands that emulate mode or devName
+// without touching the persisted viewport state, so callers such as the
+// screenshot matrix can switch emulation temporarily.
+func deviceEmulationActions(mode, devName string) ([]chromedp.Action, error) {
 	var actions []chromedp.Action
 
 	if devName != "" {
 		d, _, err := resolveDevice(devName)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		actions = append(actions, chromedp.Emulate(d))
 	} else {
@@ -158,17 +173,17 @@ func (b *DevBrowser) applyDeviceEmulation() error {
 				emulation.SetUserAgentOverride(""),
 			)
 		default:
-			return fmt.Errorf("unsupported mode: %s", mode)
+			return nil, fmt.Errorf("unsupported mode: %s", mode)
 		}
 	}
 
-	return chromedp.Run(b.Ctx, actions...)
+	return actions, nil
 }
 
 // EmulationViewportSize returns the CSS pixel viewport size that mode/devName
 // will render at once applied by applyDeviceEmulation, so the physical
 // window can be grown to fit BEFORE the CDP emulation override is issued.
-// Keep this in sync with the switch in applyDeviceEmulation — same modes,
+// Keep this in sync with the switch in deviceEmulationActions — same modes,
 // same device shortcuts.
 func EmulationViewportSize(mode, devName string) (int, int, error) {
 	if devName != "" {
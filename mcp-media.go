@@ -0,0 +1,73 @@
+package devbrowser
+
+import (
+	"fmt"
+
+	"github.com/tinywasm/context"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+// mediaReadJS reads back which media queries the page matches.
+const mediaReadJS = `(() => {
+	const q = (f, vs) => vs.find(v => matchMedia('(' + f + ': ' + v + ')').matches) || '?';
+	return [
+		'prefers-color-scheme: ' + q('prefers-color-scheme', ['dark', 'light']),
+		'prefers-reduced-motion: ' + q('prefers-reduced-motion', ['reduce', 'no-preference']),
+		'prefers-contrast: ' + q('prefers-contrast', ['more', 'less', 'custom', 'no-preference']),
+		'forced-colors: ' + q('forced-colors', ['active', 'none']),
+		'media type: ' + (matchMedia('print').matches ? 'print' : 'screen'),
+	].join(', ');
+})()`
+
+func (b *DevBrowser) GetMediaTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_emulate_media",
+			Description: "Emulate CSS media to test dark mode, motion, contrast and print layouts: color_scheme (light, dark, no-preference), reduced_motion (reduce, no-preference), contrast (more, less, custom, no-preference), forced_colors (active, none) and media_type (screen, print). vision simulates how the page looks with a vision deficiency: protanopia, deuteranopia, tritanopia, achromatopsia, blurred-vision, reduced-contrast or none. Only the given settings change; 'default' clears one and reset clears them all. browser_screenshot and browser_screenshot_matrix take a media argument to override it for a single capture. This change is persisted and re-applied every time the browser opens.",
+			Args:        new(EmulateMediaArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				var args EmulateMediaArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				m := b.GetMedia()
+				if args.Reset {
+					m = Media{}
+				}
+				for field, value := range map[*string]string{
+					&m.ColorScheme:   args.ColorScheme,
+					&m.ReducedMotion: args.ReducedMotion,
+					&m.Contrast:      args.Contrast,
+					&m.ForcedColors:  args.ForcedColors,
+					&m.Type:          args.MediaType,
+					&m.Vision:        args.Vision,
+				} {
+					switch value {
+					case "":
+					case "default":
+						*field = ""
+					default:
+						*field = value
+					}
+				}
+
+				if err := b.SetMedia(m); err != nil {
+					return nil, err
+				}
+
+				if !b.IsOpenFlag || b.Ctx == nil {
+					return mcp.Text(fmt.Sprintf("Media: %s (applied when the browser opens)", m)), nil
+				}
+				var seen string
+				if err := chromedp.Run(b.Ctx, chromedp.Evaluate(mediaReadJS, &seen)); err != nil {
+					return mcp.Text(fmt.Sprintf("Media: %s", m)), nil
+				}
+				return mcp.Text(fmt.Sprintf("Media: %s\nPage matches: %s", m, seen)), nil
+			},
+		},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/tinywasm/context"
 	"github.com/tinywasm/mcp"
@@ -13,7 +14,7 @@ func (b *DevBrowser) GetNavigationTools() []mcp.Tool {
 	return []mcp.Tool{
 		{
 			Name:        "browser_navigate",
-			Description: "Navigate the browser to a specific Url or relative path (e.g. /login)",
+			Description: "Navigate the browser to a specific Url or relative path (e.g. /login), or use action: back, forward, reload or hard_reload (ignores the cache). wait_until: load (default), domcontentloaded, network_idle (load, then no request for 500ms) or selector (the selector element visible). timeout in ms (default 30000). Returns the final Url, the HTTP status of the page and its redirect chain.",
 			Args: new(NavigateArgs),
 			Resource:    "browser",
 			Action:      'u',
@@ -27,8 +28,18 @@ func (b *DevBrowser) GetNavigationTools() []mcp.Tool {
 					return nil, err
 				}
 
-				targetURL := args.Url
-				if !strings.Contains(targetURL, "://") {
+				n := Navigation{
+					URL:       args.Url,
+					Action:    args.Action,
+					WaitUntil: args.WaitUntil,
+					Selector:  args.Selector,
+					Timeout:   time.Duration(args.Timeout) * time.Millisecond,
+				}
+				if err := n.validate(); err != nil {
+					return nil, err
+				}
+
+				if n.URL != "" && !strings.Contains(n.URL, "://") {
 					if b.LastPort == "" {
 						return nil, fmt.Errorf("browser has no active app port; open the app first")
 					}
@@ -43,25 +54,26 @@ func (b *DevBrowser) GetNavigationTools() []mcp.Tool {
 						return nil, fmt.Errorf("failed to parse base Url: %v", err)
 					}
 
-					rel, err := url.Parse(targetURL)
+					rel, err := url.Parse(n.URL)
 					if err != nil {
 						return nil, fmt.Errorf("failed to parse target path: %v", err)
 					}
 
-					targetURL = base.ResolveReference(rel).String()
+					n.URL = base.ResolveReference(rel).String()
 				}
 
-				err := b.NavigateToURL(targetURL)
+				res, err := b.Navigate(n)
 				if err != nil {
-					return nil, fmt.Errorf("Error navigating to %s: %v", targetURL, err)
+					return nil, fmt.Errorf("Error %s: %v", n, err)
 				}
 
-				current, err := b.CurrentURL()
-				if err != nil {
-					return mcp.Text(fmt.Sprintf("Navigated to %s (could not get current Url: %v)", targetURL, err)), nil
+				switch n.Action {
+				case "":
+					return mcp.Text("Navigated to " + res.String()), nil
+				case "back", "forward":
+					return mcp.Text(fmt.Sprintf("Went %s to %s", n.Action, res)), nil
 				}
-
-				return mcp.Text(fmt.Sprintf("Navigated to %s (current: %s)", targetURL, current)), nil
+				return mcp.Text("Reloaded " + res.String()), nil
 			},
 		},
 	}
@@ -2,6 +2,7 @@ package devbrowser
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -91,6 +92,25 @@ func (b *DevBrowser) initializeNetworkCapture() {
 	requests := make(map[network.RequestID]requestInfo)
 	var mutex sync.Mutex
 
+	b.NetworkMutex.Lock()
+	b.inflight = make(map[network.RequestID]string)
+	b.networkActivity = time.Now()
+	b.failedRequests = nil
+	b.NetworkMutex.Unlock()
+
+	// finished ends a request for network idle waits.
+	finished := func(id network.RequestID) {
+		mutex.Lock()
+		delete(requests, id)
+		mutex.Unlock()
+		b.NetworkMutex.Lock()
+		if _, ok := b.inflight[id]; ok {
+			delete(b.inflight, id)
+			b.networkActivity = time.Now()
+		}
+		b.NetworkMutex.Unlock()
+	}
+
 	chromedp.ListenTarget(b.Ctx, func(ev interface{}) {
 		switch ev := ev.(type) {
 		case *network.EventRequestWillBeSent:
@@ -101,11 +121,16 @@ func (b *DevBrowser) initializeNetworkCapture() {
 				Time:   time.Now(),
 			}
 			mutex.Unlock()
+			b.NetworkMutex.Lock()
 			if ev.Type == "Document" {
-				b.NetworkMutex.Lock()
 				b.NetworkLogs = []NetworkLogEntry{}
-				b.NetworkMutex.Unlock()
 			}
+			// Server-sent event streams (live reload) never finish.
+			if ev.Type != network.ResourceTypeEventSource {
+				b.inflight[ev.RequestID] = ev.Request.URL
+				b.networkActivity = time.Now()
+			}
+			b.NetworkMutex.Unlock()
 
 		case *network.EventResponseReceived:
 			mutex.Lock()
@@ -114,16 +139,24 @@ func (b *DevBrowser) initializeNetworkCapture() {
 			if ok {
 				duration := time.Since(reqInfo.Time).Milliseconds()
 				b.NetworkMutex.Lock()
-				b.NetworkLogs = append(b.NetworkLogs, NetworkLogEntry{
-					URL:      ev.Response.URL,
-					Method:   reqInfo.Method,
-					Status:   int(ev.Response.Status),
-					Type:     string(ev.Type),
-					Duration: duration,
-				})
+				entry := NetworkLogEntry{
+					URL:       ev.Response.URL,
+					Method:    reqInfo.Method,
+					Status:    int(ev.Response.Status),
+					Type:      string(ev.Type),
+					Duration:  duration,
+					Timestamp: reqInfo.Time,
+				}
+				b.NetworkLogs = append(b.NetworkLogs, entry)
+				if entry.Status >= 400 {
+					b.addFailedRequest(entry)
+				}
 				b.NetworkMutex.Unlock()
 			}
 
+		case *network.EventLoadingFinished:
+			finished(ev.RequestID)
+
 		case *network.EventLoadingFailed:
 			mutex.Lock()
 			reqInfo, ok := requests[ev.RequestID]
@@ -131,16 +164,66 @@ func (b *DevBrowser) initializeNetworkCapture() {
 			if ok {
 				duration := time.Since(reqInfo.Time).Milliseconds()
 				b.NetworkMutex.Lock()
-				b.NetworkLogs = append(b.NetworkLogs, NetworkLogEntry{
+				entry := NetworkLogEntry{
 					URL:       reqInfo.Url,
 					Method:    reqInfo.Method,
 					Type:      string(ev.Type),
 					Duration:  duration,
 					Failed:    true,
 					ErrorText: ev.ErrorText,
-				})
+					Timestamp: reqInfo.Time,
+				}
+				b.NetworkLogs = append(b.NetworkLogs, entry)
+				b.addFailedRequest(entry)
 				b.NetworkMutex.Unlock()
 			}
+			finished(ev.RequestID)
 		}
 	})
 }
+
+// maxFailedRequests caps the failed requests kept for assertions.
+const maxFailedRequests = 200
+
+// addFailedRequest records a failed request for assertions. The caller
+// holds NetworkMutex.
+func (b *DevBrowser) addFailedRequest(e NetworkLogEntry) {
+	b.failedRequests = append(b.failedRequests, e)
+	if len(b.failedRequests) > maxFailedRequests {
+		b.failedRequests = b.failedRequests[len(b.failedRequests)-maxFailedRequests:]
+	}
+}
+
+// failedRequestsSince describes the failed requests sent at or after since.
+func (b *DevBrowser) failedRequestsSince(since time.Time) []string {
+	b.NetworkMutex.Lock()
+	defer b.NetworkMutex.Unlock()
+	var found []string
+	for _, l := range b.failedRequests {
+		if l.Timestamp.Before(since) {
+			continue
+		}
+		if l.Failed {
+			found = append(found, fmt.Sprintf("%s %s (%s)", l.Method, l.URL, l.ErrorText))
+		} else {
+			found = append(found, fmt.Sprintf("%d %s %s", l.Status, l.Method, l.URL))
+		}
+	}
+	return found
+}
+
+// networkState returns the URLs of the requests in flight and how long the
+// network has been quiet, for network idle waits.
+func (b *DevBrowser) networkState() ([]string, time.Duration) {
+	b.NetworkMutex.Lock()
+	defer b.NetworkMutex.Unlock()
+	urls := make([]string, 0, len(b.inflight))
+	for _, u := range b.inflight {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+	if b.networkActivity.IsZero() {
+		return urls, 0
+	}
+	return urls, time.Since(b.networkActivity)
+}
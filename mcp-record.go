@@ -0,0 +1,457 @@
+package devbrowser
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	twcontext "github.com/tinywasm/context"
+	"github.com/tinywasm/devbrowser/cdproto/network"
+	"github.com/tinywasm/devbrowser/cdproto/page"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+// Recording limits. Frames are held in memory until browser_record_stop, so
+// the duration and frame caps bound what a forgotten recording can cost.
+const (
+	recordDefaultFPS      = 10
+	recordMaxFPS          = 30
+	recordDefaultDuration = 10 * time.Second
+	recordMaxDuration     = 120 * time.Second
+	recordMaxFrames       = 1800
+	recordDefaultQuality  = 80
+	recordReportEvents    = 40 // timeline lines returned inline; the file has all
+)
+
+// screencastFrame is one screencast frame kept by the fps limiter.
+type screencastFrame struct {
+	Data   []byte // JPEG
+	Offset time.Duration
+}
+
+// recordedEvent is a console, error or network event seen while recording.
+// Frame is the 1-based frame on screen when it happened (0 = before the
+// first frame arrived).
+type recordedEvent struct {
+	Offset time.Duration
+	Frame  int
+	Kind   string
+	Text   string
+}
+
+func (e recordedEvent) line() string {
+	return fmt.Sprintf("+%.3fs frame %04d  [%s] %s", e.Offset.Seconds(), e.Frame, e.Kind, e.Text)
+}
+
+type screencastRecording struct {
+	started    time.Time
+	duration   time.Duration // set when the recording stops
+	interval   time.Duration // minimum gap between kept frames (1/fps)
+	fps        int
+	frames     []screencastFrame
+	events     []recordedEvent
+	skipped    int // frames dropped by the fps limit
+	requests   map[network.RequestID]string
+	stopped    bool
+	stopReason string
+
+	ctx    context.Context // listener context, cancelled on stop
+	cancel context.CancelFunc
+	timer  *time.Timer
+}
+
+func (b *DevBrowser) GetRecordTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_record_start",
+			Description: "Start recording the page as a screencast to capture animations, transitions or a multi-step interaction. Frames are taken only when the page repaints, limited to fps (default 10, max 30), and console, JS error and network events are logged against the frame on screen. Recording stops by itself after max_duration seconds (default 10, max 120). scale (0.1-1) shrinks frames, quality (1-100) sets the JPEG quality. Collect the result with browser_record_stop.",
+			Args:        new(RecordStartArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(Ctx *twcontext.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args RecordStartArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				fps := int(args.Fps)
+				if fps <= 0 {
+					fps = recordDefaultFPS
+				}
+				if fps > recordMaxFPS {
+					fps = recordMaxFPS
+				}
+
+				maxDuration := time.Duration(args.MaxDuration) * time.Second
+				if maxDuration <= 0 {
+					maxDuration = recordDefaultDuration
+				}
+				if maxDuration > recordMaxDuration {
+					maxDuration = recordMaxDuration
+				}
+
+				scale := args.Scale
+				if scale <= 0 || scale > 1 {
+					scale = 1
+				}
+				if scale < 0.1 {
+					scale = 0.1
+				}
+
+				quality := int(args.Quality)
+				if quality <= 0 || quality > 100 {
+					quality = recordDefaultQuality
+				}
+
+				if err := b.startRecording(fps, maxDuration, scale, quality); err != nil {
+					return nil, err
+				}
+
+				return mcp.Text(fmt.Sprintf("Recording started (fps %d, max %s, scale %.2f, quality %d). Interact with the page, then call browser_record_stop.",
+					fps, maxDuration, scale, quality)), nil
+			},
+		},
+		{
+			Name:        "browser_record_stop",
+			Description: "Stop the running screencast and write it to disk: <dir>/<name>/frame-NNNN-<ms>ms.jpg files plus timeline.txt linking each frame to the console, error and network events around it. encode 'gif' or 'apng' also writes <dir>/<name>.gif or <dir>/<name>.png as a looping animation that keeps the recorded timing. name defaults to 'recording'.",
+			Args:        new(RecordStopArgs),
+			Resource:    "browser_file",
+			Action:      'c',
+			Execute: func(Ctx *twcontext.Context, req mcp.Request) (*mcp.Result, error) {
+				var args RecordStopArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				encode := strings.ToLower(strings.TrimSpace(args.Encode))
+				var animExt string
+				switch encode {
+				case "", "frames":
+					encode = ""
+				case "gif":
+					animExt = ".gif"
+				case "apng":
+					animExt = ".png"
+				default:
+					return nil, fmt.Errorf("Unknown encode: %s. Use 'frames', 'gif' or 'apng'", args.Encode)
+				}
+
+				name := args.Name
+				if name == "" {
+					name = "recording"
+				}
+
+				framesDir, err := cleanAndValidatePath(args.Dir, name, "")
+				if err != nil {
+					return nil, err
+				}
+				var animPath string
+				if animExt != "" {
+					if animPath, err = cleanAndValidatePath(args.Dir, name, animExt); err != nil {
+						return nil, err
+					}
+				}
+				if !args.Overwrite {
+					for _, p := range []string{framesDir, animPath} {
+						if p == "" {
+							continue
+						}
+						if _, err := os.Stat(p); err == nil {
+							return nil, fmt.Errorf("file already exists: %s", p)
+						}
+					}
+				}
+
+				b.RecordMutex.Lock()
+				rec := b.recording
+				b.RecordMutex.Unlock()
+				if rec == nil {
+					return nil, fmt.Errorf("no recording in progress: call browser_record_start first")
+				}
+
+				b.haltRecording(rec, "stopped by browser_record_stop")
+
+				b.RecordMutex.Lock()
+				b.recording = nil
+				b.RecordMutex.Unlock()
+
+				return b.writeRecording(rec, framesDir, animPath, encode)
+			},
+		},
+	}
+}
+
+// startRecording enables the screencast and a listener, bound to its own
+// cancelable context, that keeps frames and events until haltRecording.
+func (b *DevBrowser) startRecording(fps int, maxDuration time.Duration, scale float64, quality int) error {
+	b.RecordMutex.Lock()
+	if b.recording != nil && !b.recording.stopped {
+		b.RecordMutex.Unlock()
+		return fmt.Errorf("a recording is already running: call browser_record_stop first")
+	}
+	b.RecordMutex.Unlock()
+
+	start := page.StartScreencast().
+		WithFormat(page.ScreencastFormatJpeg).
+		WithQuality(int64(quality)).
+		WithEveryNthFrame(1)
+	if scale < 1 {
+		var vw, vh int
+		if err := chromedp.Run(b.Ctx,
+			chromedp.Evaluate(`window.innerWidth`, &vw),
+			chromedp.Evaluate(`window.innerHeight`, &vh),
+		); err != nil {
+			return fmt.Errorf("Failed to read viewport size: %v", err)
+		}
+		start = start.
+			WithMaxWidth(int64(float64(vw) * scale)).
+			WithMaxHeight(int64(float64(vh) * scale))
+	}
+
+	ctx, cancel := context.WithCancel(b.Ctx)
+	rec := &screencastRecording{
+		started:  time.Now(),
+		interval: time.Second / time.Duration(fps),
+		fps:      fps,
+		requests: make(map[network.RequestID]string),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	b.RecordMutex.Lock()
+	b.recording = rec
+	b.RecordMutex.Unlock()
+
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		b.handleRecordingEvent(rec, ev)
+	})
+
+	if err := chromedp.Run(b.Ctx, start); err != nil {
+		cancel()
+		b.RecordMutex.Lock()
+		b.recording = nil
+		b.RecordMutex.Unlock()
+		return fmt.Errorf("Failed to start screencast: %v", err)
+	}
+
+	b.RecordMutex.Lock()
+	rec.timer = time.AfterFunc(maxDuration, func() {
+		b.haltRecording(rec, fmt.Sprintf("max_duration %s reached", maxDuration))
+	})
+	b.RecordMutex.Unlock()
+
+	return nil
+}
+
+// handleRecordingEvent runs on the chromedp event loop: it must not block,
+// so frame acks are sent from a goroutine.
+func (b *DevBrowser) handleRecordingEvent(rec *screencastRecording, ev interface{}) {
+	switch ev := ev.(type) {
+	case *page.EventScreencastFrame:
+		// Chrome sends the next frame only after the ack, even when the
+		// frame itself is dropped below.
+		go func(sessionID int64) {
+			_ = chromedp.Run(rec.ctx, page.ScreencastFrameAck(sessionID))
+		}(ev.SessionID)
+
+		data, err := base64.StdEncoding.DecodeString(ev.Data)
+		if err != nil {
+			return
+		}
+
+		b.RecordMutex.Lock()
+		defer b.RecordMutex.Unlock()
+		if rec.stopped {
+			return
+		}
+		offset := time.Since(rec.started)
+		if n := len(rec.frames); n > 0 && offset-rec.frames[n-1].Offset < rec.interval {
+			rec.skipped++
+			return
+		}
+		rec.frames = append(rec.frames, screencastFrame{Data: data, Offset: offset})
+		if len(rec.frames) >= recordMaxFrames {
+			go b.haltRecording(rec, fmt.Sprintf("frame limit %d reached", recordMaxFrames))
+		}
+
+	case *runtime.EventConsoleAPICalled:
+		b.addRecordedEvent(rec, "console."+string(ev.Type), formatConsoleArgs(ev.Args))
+
+	case *runtime.EventExceptionThrown:
+		msg := ev.ExceptionDetails.Text
+		if ev.ExceptionDetails.Exception != nil && ev.ExceptionDetails.Exception.Description != "" {
+			msg += ": " + ev.ExceptionDetails.Exception.Description
+		}
+		b.addRecordedEvent(rec, "error", msg)
+
+	case *network.EventRequestWillBeSent:
+		b.RecordMutex.Lock()
+		rec.requests[ev.RequestID] = ev.Request.Method + " " + ev.Request.URL
+		b.RecordMutex.Unlock()
+
+	case *network.EventResponseReceived:
+		b.RecordMutex.Lock()
+		reqLine, ok := rec.requests[ev.RequestID]
+		b.RecordMutex.Unlock()
+		if !ok {
+			reqLine = ev.Response.URL
+		}
+		b.addRecordedEvent(rec, "network", fmt.Sprintf("%d %s [%s]", ev.Response.Status, reqLine, ev.Type))
+
+	case *network.EventLoadingFailed:
+		b.RecordMutex.Lock()
+		reqLine := rec.requests[ev.RequestID]
+		b.RecordMutex.Unlock()
+		b.addRecordedEvent(rec, "network", fmt.Sprintf("Failed %s [%s] %s", reqLine, ev.Type, ev.ErrorText))
+	}
+}
+
+func (b *DevBrowser) addRecordedEvent(rec *screencastRecording, kind, text string) {
+	b.RecordMutex.Lock()
+	defer b.RecordMutex.Unlock()
+	if rec.stopped {
+		return
+	}
+	rec.events = append(rec.events, recordedEvent{
+		Offset: time.Since(rec.started),
+		Frame:  len(rec.frames),
+		Kind:   kind,
+		Text:   text,
+	})
+}
+
+// haltRecording stops the screencast and the listener. It is idempotent, so
+// the max-duration timer and browser_record_stop can race safely.
+func (b *DevBrowser) haltRecording(rec *screencastRecording, reason string) {
+	b.RecordMutex.Lock()
+	if rec.stopped {
+		b.RecordMutex.Unlock()
+		return
+	}
+	rec.stopped = true
+	rec.stopReason = reason
+	rec.duration = time.Since(rec.started)
+	if rec.timer != nil {
+		rec.timer.Stop()
+	}
+	b.RecordMutex.Unlock()
+
+	if err := chromedp.Run(rec.ctx, page.StopScreencast()); err != nil {
+		b.Logger(fmt.Sprintf("Failed to stop screencast: %v", err))
+	}
+	rec.cancel()
+	b.Logger(fmt.Sprintf("Recording stopped: %s", reason))
+}
+
+// writeRecording writes the frames, timeline.txt and the optional animation,
+// and returns a report with the timeline head.
+func (b *DevBrowser) writeRecording(rec *screencastRecording, framesDir, animPath, encode string) (*mcp.Result, error) {
+	if len(rec.frames) == 0 {
+		return nil, fmt.Errorf("recording captured no frames (%s): the page did not repaint while recording", rec.stopReason)
+	}
+
+	if err := os.MkdirAll(framesDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %v", framesDir, err)
+	}
+	// An overwrite must not leave frames of an older, longer recording behind.
+	if old, _ := filepath.Glob(filepath.Join(framesDir, "frame-*.jpg")); len(old) > 0 {
+		for _, f := range old {
+			os.Remove(f)
+		}
+	}
+
+	names := make([]string, len(rec.frames))
+	for i, f := range rec.frames {
+		names[i] = fmt.Sprintf("frame-%04d-%06dms.jpg", i+1, f.Offset.Milliseconds())
+		if err := os.WriteFile(filepath.Join(framesDir, names[i]), f.Data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write frame file: %v", err)
+		}
+	}
+
+	timeline := recordingTimeline(rec, names)
+	timelinePath := filepath.Join(framesDir, "timeline.txt")
+	if err := os.WriteFile(timelinePath, []byte(strings.Join(timeline, "\n")+"\n"), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write timeline file: %v", err)
+	}
+
+	var report strings.Builder
+	report.WriteString(fmt.Sprintf("Recording saved: %d frames over %.1fs (fps limit %d, %d repaints skipped, %s)\n",
+		len(rec.frames), rec.duration.Seconds(), rec.fps, rec.skipped, rec.stopReason))
+	report.WriteString(fmt.Sprintf("Frames: %s\nTimeline: %s\n", framesDir, timelinePath))
+
+	if encode != "" {
+		frames := make([]AnimationFrame, len(rec.frames))
+		for i, f := range rec.frames {
+			next := rec.duration
+			if i+1 < len(rec.frames) {
+				next = rec.frames[i+1].Offset
+			}
+			delay := next - f.Offset
+			if delay < 100*time.Millisecond {
+				delay = 100 * time.Millisecond
+			}
+			frames[i] = AnimationFrame{Data: f.Data, Delay: delay}
+		}
+
+		var data []byte
+		var err error
+		if encode == "gif" {
+			data, err = EncodeGIF(frames)
+		} else {
+			data, err = EncodeAPNG(frames)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(animPath, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write animation file: %v", err)
+		}
+		report.WriteString(fmt.Sprintf("Animation (%s): %s (%d KB)\n", encode, animPath, len(data)/1024))
+	}
+
+	report.WriteString(fmt.Sprintf("Events: %d\n", len(rec.events)))
+	for i, e := range rec.events {
+		if i == recordReportEvents {
+			report.WriteString(fmt.Sprintf("... %d more in timeline.txt\n", len(rec.events)-i))
+			break
+		}
+		report.WriteString(e.line() + "\n")
+	}
+
+	return mcp.Text(report.String()), nil
+}
+
+// recordingTimeline merges frames and events by time offset. Event lines
+// name the frame that was on screen, so "+1.234s frame 0012  [console.log] x"
+// can be matched to frame-0012-*.jpg.
+func recordingTimeline(rec *screencastRecording, frameNames []string) []string {
+	type entry struct {
+		offset time.Duration
+		line   string
+	}
+	var entries []entry
+	for i, f := range rec.frames {
+		entries = append(entries, entry{f.Offset, fmt.Sprintf("+%.3fs frame %04d  %s", f.Offset.Seconds(), i+1, frameNames[i])})
+	}
+	for _, e := range rec.events {
+		entries = append(entries, entry{e.Offset, e.line()})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].offset < entries[j].offset })
+
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = e.line
+	}
+	return lines
+}
@@ -0,0 +1,58 @@
+package devbrowser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tinywasm/context"
+	"github.com/tinywasm/mcp"
+)
+
+func (b *DevBrowser) GetReloadTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_reload_assets",
+			Description: "Reload stylesheets or images in place after editing them, without reloading the page, so its state (scroll, form input, app state) is kept. kind: css (default; <link> stylesheets and @import-ed sheets) or images (<img>). url limits it to the assets matching a full Url or a path suffix (e.g. app.css or /css/app.css). Returns the Urls reloaded.",
+			Args:        new(ReloadAssetsArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args ReloadAssetsArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				kind := args.Kind
+				if kind == "" {
+					kind = "css"
+				}
+				var reloaded []string
+				var err error
+				switch kind {
+				case "css":
+					reloaded, err = b.ReloadStylesheet(args.Url)
+				case "images":
+					reloaded, err = b.reloadImages(args.Url)
+				default:
+					return nil, fmt.Errorf("unsupported asset kind: %s. Use css or images", kind)
+				}
+				if err != nil {
+					return nil, err
+				}
+
+				noun := map[string]string{"css": "stylesheets", "images": "images"}[kind]
+				if len(reloaded) == 0 {
+					if args.Url != "" {
+						return mcp.Text(fmt.Sprintf("No %s matching %s on the page", noun, args.Url)), nil
+					}
+					return mcp.Text(fmt.Sprintf("No %s on the page", noun)), nil
+				}
+				return mcp.Text(fmt.Sprintf("Reloaded in place (%d %s):\n  %s", len(reloaded), noun, strings.Join(reloaded, "\n  "))), nil
+			},
+		},
+	}
+}
@@ -0,0 +1,257 @@
+package devbrowser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tinywasm/context"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+// annotationMaxMatches caps how many matches of one selector get a number,
+// so a loose selector like "a" does not bury the image in labels.
+const annotationMaxMatches = 20
+
+// annotationMark is one numbered outline drawn by AnnotateElementsJS, or a
+// legend entry for a selector that could not be drawn (Error set).
+type annotationMark struct {
+	N        int    `json:"n"`
+	Selector string `json:"selector"`
+	Match    int    `json:"match"`
+	Total    int    `json:"total"`
+	X        int    `json:"x"` // Viewport coordinates, CSS px
+	Y        int    `json:"y"`
+	PageX    int    `json:"pageX"` // Document coordinates, CSS px
+	PageY    int    `json:"pageY"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Error    string `json:"error"`
+}
+
+// AnnotateElementsJS draws a numbered outline over every match of each
+// selector inside a single absolutely positioned container, so removing that
+// container restores the page. Each selector gets its own colour. Refs and
+// selector paths are resolved on the Go side: deepRects maps their index to
+// the viewport rect of their element, or to the error to report.
+const AnnotateElementsJS = `(selectors, maxMatches, deepRects = {}) => {
+	document.getElementById('__devbrowser_annotations')?.remove();
+	const root = document.createElement('div');
+	root.id = '__devbrowser_annotations';
+	root.style.cssText = 'position:absolute;left:0;top:0;width:0;height:0;overflow:visible;z-index:2147483647;pointer-events:none;';
+	document.documentElement.appendChild(root);
+
+	const colors = ['#e6194b', '#3cb44b', '#4363d8', '#f58231', '#911eb4', '#008080', '#f032e6', '#9a6324'];
+	const out = [];
+	let n = 0;
+	selectors.forEach((sel, si) => {
+		let els;
+		if (si in deepRects) {
+			if (typeof deepRects[si] === 'string') {
+				out.push({selector: sel, error: deepRects[si]});
+				return;
+			}
+			els = [deepRects[si]];
+		} else try {
+			els = Array.from(document.querySelectorAll(sel));
+		} catch (e) {
+			out.push({selector: sel, error: 'invalid selector'});
+			return;
+		}
+		if (!els.length) {
+			out.push({selector: sel, error: 'no match'});
+			return;
+		}
+		const color = colors[si % colors.length];
+		els.slice(0, maxMatches).forEach((el, mi) => {
+			const r = el.getBoundingClientRect ? el.getBoundingClientRect() : el;
+			if (r.width === 0 && r.height === 0) {
+				out.push({selector: sel, match: mi + 1, total: els.length, error: 'not rendered'});
+				return;
+			}
+			n++;
+			const px = r.left + window.scrollX, py = r.top + window.scrollY;
+			const box = document.createElement('div');
+			box.style.cssText = 'position:absolute;box-sizing:border-box;' +
+				'left:' + px + 'px;top:' + py + 'px;width:' + r.width + 'px;height:' + r.height + 'px;' +
+				'border:2px solid ' + color + ';background:' + color + '22;';
+			const label = document.createElement('div');
+			label.textContent = n;
+			// Put the label above the box unless that would leave the page.
+			label.style.cssText = 'position:absolute;left:-2px;top:' + (py >= 18 ? -18 : 0) + 'px;' +
+				'min-width:14px;height:18px;padding:0 4px;border-radius:2px;text-align:center;' +
+				'font:bold 12px/18px sans-serif;color:#fff;background:' + color + ';';
+			box.appendChild(label);
+			root.appendChild(box);
+			out.push({
+				n: n, selector: sel, match: mi + 1, total: els.length,
+				x: Math.round(r.left), y: Math.round(r.top),
+				pageX: Math.round(px), pageY: Math.round(py),
+				width: Math.round(r.width), height: Math.round(r.height)
+			});
+		});
+	});
+	return out;
+}`
+
+const removeAnnotationsJS = `document.getElementById('__devbrowser_annotations')?.remove()`
+
+func (b *DevBrowser) GetScreenshotAnnotatedTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_screenshot_annotated",
+			Description: "Capture a screenshot with numbered, coloured outlines drawn over elements, to point at exactly what you are talking about. selectors holds one CSS selector, element ref (ref:e12), locator (text=Save) or path through iframes and shadow roots (iframe#pay >>> form) per line; every match (up to 20 per selector) gets its own number. The outlines are removed right after the capture. Returns the image plus a legend mapping each number to its selector and bounding rect (viewport CSS px, or document px with fullpage). Accepts format, quality and scale like browser_screenshot.",
+			Args:        new(ScreenshotAnnotatedArgs),
+			Resource:    "browser",
+			Action:      'r',
+			Execute: func(Ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				var args ScreenshotAnnotatedArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				selectors := splitSelectorList(args.Selectors)
+				if len(selectors) == 0 {
+					return nil, fmt.Errorf("selectors required: one CSS selector per line")
+				}
+
+				opts := ScreenshotOptions{
+					Fullpage: args.Fullpage,
+					Format:   args.Format,
+					Quality:  int(args.Quality),
+					Scale:    args.Scale,
+				}
+				if err := opts.Validate(); err != nil {
+					return nil, err
+				}
+
+				if !b.IsOpen() || b.Ctx == nil {
+					return nil, ErrBrowserNotOpen
+				}
+
+				marks, res, err := b.captureAnnotated(selectors, opts)
+				if err != nil {
+					return nil, err
+				}
+
+				report := fmt.Sprintf("Annotated screenshot (%d KB, %s)\nUrl: %s | Viewport: %dx%d\n\n%s",
+					len(res.ImageData)/1024, res.MimeType, res.PageURL, res.Width, res.Height,
+					formatAnnotationLegend(marks, opts.Fullpage))
+
+				return mcp.NewResult(mcp.TextBlock(report), mcp.ImageBlock(res.ImageData, res.MimeType)), nil
+			},
+		},
+	}
+}
+
+// captureAnnotated injects the outlines, captures, and always removes them.
+func (b *DevBrowser) captureAnnotated(selectors []string, opts ScreenshotOptions) ([]annotationMark, *ScreenshotResult, error) {
+	sels, err := json.Marshal(selectors)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rects, err := json.Marshal(b.deepSelectorRects(selectors))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var marks []annotationMark
+	draw := chromedp.Evaluate(fmt.Sprintf("(%s)(%s, %d, %s)", AnnotateElementsJS, sels, annotationMaxMatches, rects), &marks)
+	if err := chromedp.Run(b.Ctx, draw); err != nil {
+		chromedp.Run(b.Ctx, chromedp.Evaluate(removeAnnotationsJS, nil))
+		return nil, nil, fmt.Errorf("Failed to draw annotations: %v", err)
+	}
+	defer func() {
+		if err := chromedp.Run(b.Ctx, chromedp.Evaluate(removeAnnotationsJS, nil)); err != nil {
+			b.Logger(fmt.Sprintf("Warning: failed to remove annotations: %v", err))
+		}
+	}()
+
+	drawn := 0
+	for _, m := range marks {
+		if m.Error == "" {
+			drawn++
+		}
+	}
+	if drawn == 0 {
+		return nil, nil, fmt.Errorf("no element to annotate:\n%s", formatAnnotationLegend(marks, opts.Fullpage))
+	}
+
+	res, err := b.CaptureScreenshotWithOptions(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(res.ImageData) == 0 {
+		return nil, nil, fmt.Errorf("screenshot capture returned empty buffer")
+	}
+	return marks, res, nil
+}
+
+// deepSelectorRects resolves the refs and selector paths of the list, which
+// document.querySelectorAll cannot match, to their viewport rect (or error)
+// keyed by list index.
+func (b *DevBrowser) deepSelectorRects(selectors []string) map[string]any {
+	rects := make(map[string]any)
+	for i, sel := range selectors {
+		if !isDeepSelector(sel) {
+			continue
+		}
+		key := strconv.Itoa(i)
+		el, err := b.resolveElement(b.Ctx, sel)
+		if err != nil {
+			rects[key] = err.Error()
+			continue
+		}
+		r, err := b.elementViewportRect(b.Ctx, el)
+		el.release()
+		if err != nil {
+			rects[key] = "not rendered"
+			continue
+		}
+		rects[key] = map[string]float64{"left": r.X, "top": r.Y, "width": r.Width, "height": r.Height}
+	}
+	return rects
+}
+
+// formatAnnotationLegend renders one line per mark, in drawing order.
+func formatAnnotationLegend(marks []annotationMark, fullpage bool) string {
+	var sb strings.Builder
+	sb.WriteString("Legend:\n")
+	for _, m := range marks {
+		if m.Error != "" {
+			if m.Match > 0 {
+				sb.WriteString(fmt.Sprintf("-  %s (match %d/%d): %s\n", m.Selector, m.Match, m.Total, m.Error))
+			} else {
+				sb.WriteString(fmt.Sprintf("-  %s: %s\n", m.Selector, m.Error))
+			}
+			continue
+		}
+		x, y := m.X, m.Y
+		if fullpage {
+			x, y = m.PageX, m.PageY
+		}
+		sb.WriteString(fmt.Sprintf("%d. %s", m.N, m.Selector))
+		if m.Total > 1 {
+			sb.WriteString(fmt.Sprintf(" (match %d/%d)", m.Match, m.Total))
+		}
+		sb.WriteString(fmt.Sprintf(" at x=%d y=%d w=%d h=%d\n", x, y, m.Width, m.Height))
+		if m.Match == annotationMaxMatches && m.Total > annotationMaxMatches {
+			sb.WriteString(fmt.Sprintf("   ... %d more matches of %s not annotated\n", m.Total-annotationMaxMatches, m.Selector))
+		}
+	}
+	return sb.String()
+}
+
+// splitSelectorList splits a one-selector-per-line list, dropping blank lines.
+func splitSelectorList(list string) []string {
+	var out []string
+	for _, line := range strings.Split(list, "\n") {
+		if s := strings.TrimSpace(line); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,289 @@
+package devbrowser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tinywasm/context"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+// matrixContactSheetColumns and matrixContactSheetCellWidth size the optional
+// contact sheet: wide enough to read a phone layout, small enough that a
+// 4-up sheet stays a reasonable MCP image payload.
+const (
+	matrixContactSheetColumns   = 4
+	matrixContactSheetCellWidth = 360
+)
+
+// matrixTarget is one entry of the browser_screenshot_matrix target list,
+// resolved to either an emulation mode or a catalog device, and optionally
+// one of its media variants.
+type matrixTarget struct {
+	Label      string // Name shown in the report ("mobile", "iPhone 15 Pro Max")
+	Mode       string
+	Device     string
+	Media      Media  // Media override of this capture
+	MediaLabel string // Its spec ("dark"), "" to keep the emulated media
+}
+
+// String is the name of the capture in the report: "mobile dark".
+func (t matrixTarget) String() string {
+	if t.MediaLabel == "" {
+		return t.Label
+	}
+	return t.Label + " " + t.MediaLabel
+}
+
+// fileName is the file name suffix of the capture: "mobile-dark".
+func (t matrixTarget) fileName() string {
+	if t.MediaLabel == "" {
+		return normalizeName(t.Label)
+	}
+	return normalizeName(t.Label) + "-" + normalizeName(t.MediaLabel)
+}
+
+// matrixShot is a single capture of the matrix.
+type matrixShot struct {
+	Target    matrixTarget
+	ImageData []byte
+	Width     int
+	Height    int
+}
+
+func (b *DevBrowser) GetScreenshotMatrixTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_screenshot_matrix",
+			Description: "Capture the current page under several devices or modes in one call to check responsive layouts. targets is a comma-separated list of modes (mobile, tablet, desktop, off) and/or device names from the 131-device catalog (e.g. \"mobile, Pixel 5, iPad Pro, desktop\"). media is a comma-separated list of media variants captured for every target (e.g. \"light, dark\" for a light+dark pair; each variant is light, dark, reduced-motion, more-contrast, less-contrast, forced-colors, print, screen or a vision deficiency, joined by +). media vision-deficiencies captures normal-vision, protanopia, deuteranopia, tritanopia, achromatopsia, blurred-vision and reduced-contrast; add contact_sheet to see them side by side. Emulation is switched per capture without growing the window, and the persisted emulation is restored afterwards. Returns the PNG images, or writes <name>-<target>[-<media>].png files when dir is set. contact_sheet adds one PNG grid with every capture.",
+			Args:        new(ScreenshotMatrixArgs),
+			Resource:    "browser_file",
+			Action:      'c',
+			Execute: func(Ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				var args ScreenshotMatrixArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				targets, err := parseMatrixTargets(args.Targets)
+				if err != nil {
+					return nil, err
+				}
+				if args.Media != "" {
+					variants, labels, err := parseMediaList(args.Media)
+					if err != nil {
+						return nil, err
+					}
+					targets = expandMatrixMedia(targets, variants, labels)
+				}
+
+				name := args.Name
+				if name == "" {
+					name = "matrix"
+				}
+
+				// Resolve and check every destination before capturing anything,
+				// so a bad path or an existing file never leaves a partial set.
+				var paths []string
+				var sheetPath string
+				if args.Dir != "" {
+					for _, t := range targets {
+						fullPath, err := cleanAndValidatePath(args.Dir, name+"-"+t.fileName(), ".png")
+						if err != nil {
+							return nil, err
+						}
+						paths = append(paths, fullPath)
+					}
+					if args.ContactSheet {
+						if sheetPath, err = cleanAndValidatePath(args.Dir, name+"-contact-sheet", ".png"); err != nil {
+							return nil, err
+						}
+						paths = append(paths, sheetPath)
+					}
+					if !args.Overwrite {
+						for _, p := range paths {
+							if _, err := os.Stat(p); err == nil {
+								return nil, fmt.Errorf("file already exists: %s", p)
+							}
+						}
+					}
+				}
+
+				if !b.IsOpen() || b.Ctx == nil {
+					return nil, ErrBrowserNotOpen
+				}
+
+				shots, err := b.captureMatrix(targets, args.Fullpage)
+				if err != nil {
+					return nil, err
+				}
+
+				var sheet []byte
+				if args.ContactSheet {
+					images := make([][]byte, len(shots))
+					for i, s := range shots {
+						images[i] = s.ImageData
+					}
+					if sheet, err = ContactSheet(images, matrixContactSheetColumns, matrixContactSheetCellWidth); err != nil {
+						return nil, err
+					}
+				}
+
+				var pageURL string
+				if err := chromedp.Run(b.Ctx, chromedp.Location(&pageURL)); err != nil {
+					b.Logger(fmt.Sprintf("Warning: failed to read page url: %v", err))
+				}
+
+				var report strings.Builder
+				report.WriteString(fmt.Sprintf("Screenshot matrix: %d captures\nUrl: %s\n\n", len(shots), pageURL))
+				for i, s := range shots {
+					report.WriteString(fmt.Sprintf("%d. %s — viewport %dx%d (%d KB)", i+1, s.Target, s.Width, s.Height, len(s.ImageData)/1024))
+					if args.Dir != "" {
+						report.WriteString(" -> " + paths[i])
+					}
+					report.WriteString("\n")
+				}
+
+				if args.Dir != "" {
+					absDir := filepath.Dir(paths[0])
+					if err := os.MkdirAll(absDir, 0755); err != nil {
+						return nil, fmt.Errorf("failed to create directory %s: %v", absDir, err)
+					}
+					for i, s := range shots {
+						if err := os.WriteFile(paths[i], s.ImageData, 0644); err != nil {
+							return nil, fmt.Errorf("failed to write screenshot file: %v", err)
+						}
+					}
+					if sheet != nil {
+						if err := os.WriteFile(sheetPath, sheet, 0644); err != nil {
+							return nil, fmt.Errorf("failed to write contact sheet: %v", err)
+						}
+						report.WriteString("Contact sheet -> " + sheetPath + "\n")
+					}
+					return mcp.Text(report.String()), nil
+				}
+
+				blocks := []mcp.ContentBlock{mcp.TextBlock(report.String())}
+				if sheet != nil {
+					blocks = append(blocks, mcp.ImageBlock(sheet, "image/png"))
+				} else {
+					for _, s := range shots {
+						blocks = append(blocks, mcp.ImageBlock(s.ImageData, "image/png"))
+					}
+				}
+				return mcp.NewResult(blocks...), nil
+			},
+		},
+	}
+}
+
+// parseMatrixTargets splits a comma-separated target list and resolves each
+// entry to an emulation mode or a catalog device. A target listed twice,
+// even under another spelling, is rejected: both captures would share a
+// file name.
+func parseMatrixTargets(list string) ([]matrixTarget, error) {
+	var targets []matrixTarget
+	seen := make(map[string]string)
+	for _, raw := range strings.Split(list, ",") {
+		entry := strings.TrimSpace(raw)
+		if entry == "" {
+			continue
+		}
+
+		var t matrixTarget
+		switch strings.ToLower(entry) {
+		case "mobile", "tablet", "desktop", "off":
+			mode := strings.ToLower(entry)
+			t = matrixTarget{Label: mode, Mode: mode}
+		default:
+			d, avail, err := resolveDevice(entry)
+			if err != nil {
+				return nil, fmt.Errorf("unsupported device: %s. Available devices: %s", entry, strings.Join(avail, ", "))
+			}
+			info := d.Device()
+			t = matrixTarget{Label: info.Name, Device: info.Name}
+		}
+
+		if first, ok := seen[t.Label]; ok {
+			return nil, fmt.Errorf("duplicate target: %s (already listed as %s)", entry, first)
+		}
+		seen[t.Label] = entry
+		targets = append(targets, t)
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("targets required: e.g. \"mobile, tablet, desktop\"")
+	}
+	return targets, nil
+}
+
+// expandMatrixMedia repeats every target once per media variant, keeping
+// the variants of a target next to each other.
+func expandMatrixMedia(targets []matrixTarget, variants []Media, labels []string) []matrixTarget {
+	if len(variants) == 0 {
+		return targets
+	}
+	expanded := make([]matrixTarget, 0, len(targets)*len(variants))
+	for _, t := range targets {
+		for i, m := range variants {
+			t.Media, t.MediaLabel = m, labels[i]
+			expanded = append(expanded, t)
+		}
+	}
+	return expanded
+}
+
+// captureMatrix captures one screenshot per target. Emulation is applied
+// directly through CDP (nothing is persisted and the window is not grown)
+// and the persisted ViewportMode/ViewportDevice and media are re-applied on
+// return.
+func (b *DevBrowser) captureMatrix(targets []matrixTarget, fullpage bool) ([]matrixShot, error) {
+	defer func() {
+		if err := b.applyDeviceEmulation(); err != nil {
+			b.Logger(fmt.Sprintf("Failed to restore emulation after screenshot matrix: %v", err))
+		}
+		if err := b.applyMedia(); err != nil {
+			b.Logger(fmt.Sprintf("Failed to restore media emulation after screenshot matrix: %v", err))
+		}
+	}()
+
+	shots := make([]matrixShot, 0, len(targets))
+	for _, t := range targets {
+		actions, err := deviceEmulationActions(t.Mode, t.Device)
+		if err != nil {
+			return nil, err
+		}
+
+		if t.MediaLabel != "" {
+			actions = append(actions, mediaAction(b.GetMedia().merge(t.Media)))
+		}
+
+		shot := matrixShot{Target: t}
+		actions = append(actions, chromedp.Evaluate(waitForPaintJS, nil, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(true)
+		}))
+		if fullpage {
+			actions = append(actions, chromedp.FullScreenshot(&shot.ImageData, 100))
+		} else {
+			actions = append(actions, chromedp.CaptureScreenshot(&shot.ImageData))
+		}
+		actions = append(actions,
+			chromedp.Evaluate(`window.innerWidth`, &shot.Width),
+			chromedp.Evaluate(`window.innerHeight`, &shot.Height),
+		)
+
+		if err := chromedp.Run(b.Ctx, actions...); err != nil {
+			return nil, fmt.Errorf("failed to capture %s: %v", t, err)
+		}
+		if len(shot.ImageData) == 0 {
+			return nil, fmt.Errorf("screenshot capture returned empty buffer for %s", t)
+		}
+		shots = append(shots, shot)
+	}
+
+	return shots, nil
+}
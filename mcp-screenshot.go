@@ -14,7 +14,7 @@ func (b *DevBrowser) GetScreenshotTools() []mcp.Tool {
 	return []mcp.Tool{
 		{
 			Name:        "browser_screenshot",
-			Description: "Capture screenshot of current browser viewport to verify visual rendering, layout correctness, or UI state. Returns PNG image as MCP resource (binary efficient format).",
+			Description: "Capture screenshot of current browser viewport to verify visual rendering, layout correctness, or UI state. Returns the image (PNG by default) as MCP resource (binary efficient format). format jpeg/webp with quality (1-100) and scale < 1 (e.g. 0.5) shrink the payload a lot. clip_x/clip_y/clip_width/clip_height capture a CSS-pixel rectangle of the viewport (of the document with fullpage). omit_background makes the default white background transparent (png/webp). marks numbers every visible interactive element and returns a table of index -> selector, role, name and element ref instead of the page structure; pass an index as ref to browser_click_element / browser_fill_element. selector (CSS, element ref, locator or a path through iframes and shadow roots like iframe#pay >>> form) captures a single element; it excludes fullpage and the clip. media overrides the emulated media for this capture only: light, dark, reduced-motion, more-contrast, less-contrast, forced-colors, print, screen or a vision deficiency (normal-vision, protanopia, deuteranopia, tritanopia, achromatopsia, blurred-vision, reduced-contrast), joined by + (e.g. dark or print+dark).",
 			Args:        new(ScreenshotArgs),
 			Resource:    "browser",
 			Action:      'r',
@@ -24,7 +24,35 @@ func (b *DevBrowser) GetScreenshotTools() []mcp.Tool {
 					return nil, err
 				}
 
-				res, err := b.CaptureScreenshot(args.Fullpage)
+				if args.Selector != "" && args.Fullpage {
+					return nil, fmt.Errorf("selector and fullpage are mutually exclusive")
+				}
+
+				var marks []pageMark
+				if args.Marks {
+					if !b.IsOpen() || b.Ctx == nil {
+						return nil, ErrBrowserNotOpen
+					}
+					var err error
+					if marks, err = b.drawMarks(args.Fullpage); err != nil {
+						return nil, err
+					}
+					defer b.removeMarks()
+				}
+
+				res, err := b.CaptureScreenshotWithOptions(ScreenshotOptions{
+					Fullpage:       args.Fullpage,
+					Selector:       args.Selector,
+					Format:         args.Format,
+					Quality:        int(args.Quality),
+					ClipX:          args.ClipX,
+					ClipY:          args.ClipY,
+					ClipWidth:      args.ClipWidth,
+					ClipHeight:     args.ClipHeight,
+					Scale:          args.Scale,
+					OmitBackground: args.OmitBackground,
+					Media:          args.Media,
+				})
 				if err != nil {
 					return nil, err
 				}
@@ -33,31 +61,42 @@ func (b *DevBrowser) GetScreenshotTools() []mcp.Tool {
 					return nil, fmt.Errorf("Screenshot capture returned empty buffer")
 				}
 
-				// Write PNG image to clipboard
-				if err := writeToClipboard(res.ImageData); err != nil {
-					b.Logger(fmt.Sprintf("Failed to copy screenshot to clipboard: %v", err))
+				// Write PNG image to clipboard (the clipboard tools are set up for PNG only)
+				if res.MimeType == "image/png" {
+					if err := writeToClipboard(res.ImageData); err != nil {
+						b.Logger(fmt.Sprintf("Failed to copy screenshot to clipboard: %v", err))
+					}
+				}
+
+				// With marks the table replaces the structure: it already lists
+				// what can be acted on, with the selectors refs resolve to.
+				pageContext := res.HTMLStructure
+				if args.Marks {
+					b.rememberMarks(marks, res.PageURL)
+					pageContext = formatMarksTable(marks)
 				}
 
 				// Build visual context report (what AI "sees" without image bytes)
 				contextReport := fmt.Sprintf(
-					"Screenshot captured (%d KB)\n"+
+					"Screenshot captured (%d KB, %s)\n"+
 						"Url: %s | Title: %s | Viewport: %dx%d\n"+
 						"\n"+
 						"%s",
 					len(res.ImageData)/1024,
+					res.MimeType,
 					res.PageURL,
 					res.PageTitle,
 					res.Width, res.Height,
-					res.HTMLStructure,
+					pageContext,
 				)
 
 				// Send text context + image to MCP client (not to TUI)
-				return mcp.NewResult(mcp.TextBlock(contextReport), mcp.ImageBlock(res.ImageData, "image/png")), nil
+				return mcp.NewResult(mcp.TextBlock(contextReport), mcp.ImageBlock(res.ImageData, res.MimeType)), nil
 			},
 		},
 		{
 			Name:        "browser_save_screenshot",
-			Description: "Capture a screenshot and write it as a durable PNG file on disk. Useful for documenting widgets or components.",
+			Description: "Capture a screenshot and write it as a durable image file on disk (PNG by default). Useful for documenting widgets or components. format (png, jpeg, webp) picks the encoding and the file extension; when omitted it follows the extension of name (.jpg, .webp), else png. Accepts the same quality, clip, scale, omit_background and media options as browser_screenshot.",
 			Args:        new(SaveScreenshotArgs),
 			Resource:    "browser_file",
 			Action:      'c',
@@ -71,7 +110,41 @@ func (b *DevBrowser) GetScreenshotTools() []mcp.Tool {
 					return nil, fmt.Errorf("selector and fullpage are mutually exclusive")
 				}
 
-				fullPath, err := cleanAndValidatePath(args.Dir, args.Name)
+				opts := ScreenshotOptions{
+					Fullpage:       args.Fullpage,
+					Selector:       args.Selector,
+					Format:         args.Format,
+					Quality:        int(args.Quality),
+					ClipX:          args.ClipX,
+					ClipY:          args.ClipY,
+					ClipWidth:      args.ClipWidth,
+					ClipHeight:     args.ClipHeight,
+					Scale:          args.Scale,
+					OmitBackground: args.OmitBackground,
+					Media:          args.Media,
+				}
+
+				// An image extension in name picks the format when none is
+				// given, and must agree with it otherwise.
+				ext := filepath.Ext(args.Name)
+				if nameFormat := ScreenshotFormatFromExt(ext); nameFormat != "" {
+					if opts.Format == "" {
+						opts.Format = nameFormat
+					}
+				} else {
+					ext = ""
+				}
+				if err := opts.Validate(); err != nil {
+					return nil, err
+				}
+				if ext != "" && ScreenshotFormatFromExt(ext) != opts.Format {
+					return nil, fmt.Errorf("name extension %s does not match format %s", ext, opts.Format)
+				}
+				if ext == "" {
+					ext = opts.Extension()
+				}
+
+				fullPath, err := cleanAndValidatePath(args.Dir, args.Name, strings.ToLower(ext))
 				if err != nil {
 					return nil, err
 				}
@@ -87,12 +160,7 @@ func (b *DevBrowser) GetScreenshotTools() []mcp.Tool {
 					return nil, ErrBrowserNotOpen
 				}
 
-				var res *ScreenshotResult
-				if args.Selector != "" {
-					res, err = b.CaptureElementScreenshot(args.Selector)
-				} else {
-					res, err = b.CaptureScreenshot(args.Fullpage)
-				}
+				res, err := b.CaptureScreenshotWithOptions(opts)
 				if err != nil {
 					return nil, err
 				}
@@ -107,7 +175,7 @@ func (b *DevBrowser) GetScreenshotTools() []mcp.Tool {
 					return nil, fmt.Errorf("failed to create directory %s: %v", absDir, err)
 				}
 
-				// Write PNG file
+				// Write image file
 				if err := os.WriteFile(fullPath, res.ImageData, 0644); err != nil {
 					return nil, fmt.Errorf("failed to write screenshot file: %v", err)
 				}
@@ -122,9 +190,11 @@ func (b *DevBrowser) GetScreenshotTools() []mcp.Tool {
 				// Return path and dimensions
 				statusReport := fmt.Sprintf(
 					"Screenshot saved to: %s\n"+
+						"Format: %s (%d KB)\n"+
 						"Dimensions: %dx%d\n"+
 						"Emulation Mode: %s\n",
 					fullPath,
+					opts.Format, len(res.ImageData)/1024,
 					res.Width, res.Height,
 					emulationMode,
 				)
@@ -136,7 +206,9 @@ func (b *DevBrowser) GetScreenshotTools() []mcp.Tool {
 }
 
 // cleanAndValidatePath cleans the directory and filename, resolves the absolute path, and performs safety checks.
-func cleanAndValidatePath(dir, name string) (string, error) {
+// ext (e.g. ".png") is appended to name unless already present; an empty ext
+// leaves name untouched, which is how callers validate an output sub-directory.
+func cleanAndValidatePath(dir, name, ext string) (string, error) {
 	// First, reject any separator in name
 	if strings.ContainsAny(name, "/\\") {
 		return "", fmt.Errorf("file name cannot contain path separators: %s", name)
@@ -160,8 +232,8 @@ func cleanAndValidatePath(dir, name string) (string, error) {
 	}
 
 	fullName := name
-	if !strings.HasSuffix(strings.ToLower(name), ".png") {
-		fullName = name + ".png"
+	if ext != "" && !strings.HasSuffix(strings.ToLower(name), ext) {
+		fullName = name + ext
 	}
 	fullPath := filepath.Join(absDir, fullName)
 
@@ -2,17 +2,38 @@ package devbrowser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/tinywasm/context"
 	"github.com/tinywasm/devbrowser/chromedp"
 	"github.com/tinywasm/mcp"
 )
 
+// outerHTMLJS serializes the element it is called on like outerHTML, with
+// its open shadow roots as declarative <template shadowrootmode> blocks.
+// Browsers without getHTML get the plain outerHTML.
+const outerHTMLJS = `function() {
+	if (!this.getHTML) return this.outerHTML;
+	const shadowRoots = [];
+	const walk = (el) => {
+		if (el.shadowRoot) {
+			shadowRoots.push(el.shadowRoot);
+			Array.from(el.shadowRoot.children).forEach(walk);
+		}
+		Array.from(el.children).forEach(walk);
+	};
+	walk(this);
+	const inner = this.getHTML({serializableShadowRoots: true, shadowRoots});
+	const shell = this.cloneNode(false).outerHTML;
+	const end = shell.lastIndexOf('</');
+	return end < 0 ? shell : shell.slice(0, end) + inner + shell.slice(end);
+}`
+
 func (b *DevBrowser) GetSourceTools() []mcp.Tool {
 	return []mcp.Tool{
 		{
 			Name:        "browser_get_source",
-			Description: "Get the raw HTML (outerHTML) of the entire page or a specific element by selector. Useful for faithful reverse engineering of the DOM structure.",
+			Description: "Get the raw HTML (outerHTML) of the entire page or a specific element by CSS selector, element ref (ref:e12), locator (text=Save, role=button[name=\"Save\"], label=Email) or a path through iframes and shadow roots (iframe#pay >>> form). Open shadow roots are included as <template shadowrootmode>; the entire page is followed by the document of each iframe. Useful for faithful reverse engineering of the DOM structure.",
 			Args: new(GetSourceArgs),
 			Resource:    "browser",
 			Action:      'r',
@@ -25,15 +46,16 @@ func (b *DevBrowser) GetSourceTools() []mcp.Tool {
 					return nil, err
 				}
 
-				var js string
+				var result string
+				var err error
 				if args.Selector == "" {
-					js = `document.documentElement.outerHTML`
+					err = chromedp.Run(b.Ctx, chromedp.Evaluate("("+outerHTMLJS+").call(document.documentElement)", &result))
+					if err == nil {
+						result += b.frameSources()
+					}
 				} else {
-					js = fmt.Sprintf(`document.querySelector(%q)?.outerHTML ?? "Element not found"`, args.Selector)
+					err = b.callOnElement(b.Ctx, args.Selector, outerHTMLJS, &result)
 				}
-
-				var result string
-				err := chromedp.Run(b.Ctx, chromedp.Evaluate(js, &result))
 				if err != nil {
 					return nil, err
 				}
@@ -43,3 +65,25 @@ func (b *DevBrowser) GetSourceTools() []mcp.Tool {
 		},
 	}
 }
+
+// frameSources serializes the document of every child frame, which the
+// page's own HTML only references by src.
+func (b *DevBrowser) frameSources() string {
+	frames, err := b.childFrames(b.Ctx)
+	if err != nil {
+		b.Logger(fmt.Sprintf("Warning: failed to list frames: %v", err))
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, f := range frames {
+		var html string
+		if err := f.evaluate("("+outerHTMLJS+").call(document.documentElement)", &html); err != nil {
+			b.Logger(fmt.Sprintf("Warning: failed to read frame %s: %v", f.frame.URL, err))
+			continue
+		}
+		sb.WriteString("\n\n<!-- " + b.frameLabel(b.Ctx, f) + " -->\n")
+		sb.WriteString(html)
+	}
+	return sb.String()
+}
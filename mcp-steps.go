@@ -0,0 +1,131 @@
+package devbrowser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tinywasm/context"
+	"github.com/tinywasm/mcp"
+)
+
+func (b *DevBrowser) GetStepTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_run_steps",
+			Description: "Run a sequence of steps in one call, in order, stopping at the first failure. steps is a JSON array of one-key objects: navigate, click, fill, press, wait, assert, screenshot and evaluate, each holding the arguments of browser_navigate, browser_click_element, browser_fill_element, browser_press_key, browser_wait_for, browser_assert, browser_screenshot or browser_evaluate_js; a failed assert fails its step. A string stands for the main argument: {\"navigate\": \"/login\"}, {\"click\": \"text=Log in\"}, {\"press\": \"Enter\"}, {\"wait\": \"#dashboard\"}, {\"assert\": \"#welcome\"} (visible), {\"evaluate\": \"document.title\"}. timeout (default 5000ms) applies to every step that sets none. Returns a per-step log, JS errors and failed requests seen during the run, the final URL and the screenshots taken.",
+			Args:        new(RunStepsArgs),
+			Resource:    "browser",
+			Action:      'u',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args RunStepsArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				steps, err := parseSteps(args.Steps)
+				if err != nil {
+					return nil, err
+				}
+
+				timeout := args.Timeout
+				if timeout == 0 {
+					timeout = 5000
+				}
+
+				// Resolve every step before running any, so a typo in the last
+				// step does not leave the page half way through the flow.
+				tools := map[string]mcp.Tool{}
+				for _, t := range b.GetMCPTools() {
+					tools[t.Name] = t
+				}
+				reqs := make([]mcp.Request, len(steps))
+				for i, step := range steps {
+					tool := tools[stepKinds[step.kind].tool]
+					if reqs[i], err = stepRequest(tool, step, timeout); err != nil {
+						return nil, fmt.Errorf("step %d: %v", i+1, err)
+					}
+				}
+
+				start := time.Now()
+				var log []string
+				var images []mcp.ContentBlock
+				failed := 0
+				for i, step := range steps {
+					if failed > 0 {
+						log = append(log, fmt.Sprintf("%d. skipped %s", i+1, step.label()))
+						continue
+					}
+
+					stepStart := time.Now()
+					text, shots, err := execStep(tools, step, reqs[i], time.Duration(timeout)*time.Millisecond)
+					images = append(images, shots...)
+					elapsed := time.Since(stepStart).Milliseconds()
+					if err != nil {
+						failed = i + 1
+						log = append(log, fmt.Sprintf("%d. failed  %s (%dms): %v", i+1, step.label(), elapsed, err))
+						continue
+					}
+					line := fmt.Sprintf("%d. ok      %s (%dms)", i+1, step.label(), elapsed)
+					if text != "" {
+						line += ": " + summarizeLine(text, 200)
+					}
+					log = append(log, line)
+				}
+
+				var out strings.Builder
+				if failed > 0 {
+					fmt.Fprintf(&out, "Ran %d of %d steps in %dms: step %d (%s) failed\n", failed, len(steps), time.Since(start).Milliseconds(), failed, steps[failed-1].kind)
+				} else {
+					fmt.Fprintf(&out, "Ran %d steps in %dms\n", len(steps), time.Since(start).Milliseconds())
+				}
+				out.WriteString(strings.Join(log, "\n"))
+				if events := b.runEvents(start); len(events) > 0 {
+					out.WriteString("\n\nDuring the run:\n" + strings.Join(events, "\n"))
+				}
+				if current, err := b.CurrentURL(); err == nil {
+					out.WriteString("\n\nFinal URL: " + current)
+				}
+
+				res := mcp.NewResult(append([]mcp.ContentBlock{mcp.TextBlock(out.String())}, images...)...)
+				res.IsError = failed > 0
+				return res, nil
+			},
+		},
+	}
+}
+
+// execStep runs one step through its tool. A tool that does not return
+// within its timeout, plus a grace period for tools without one, fails the
+// step; the run stops there so nothing races the abandoned call.
+func execStep(tools map[string]mcp.Tool, step runStep, req mcp.Request, timeout time.Duration) (string, []mcp.ContentBlock, error) {
+	type result struct {
+		res *mcp.Result
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		res, err := tools[req.Params.Name].Execute(nil, req)
+		done <- result{res, err}
+	}()
+
+	limit := step.timeout(timeout) + 2*time.Second
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return "", nil, r.err
+		}
+		text, images := stepOutput(r.res)
+		if r.res.IsError {
+			return "", images, errors.New(strings.ReplaceAll(text, "\n", " "))
+		}
+		return text, images, nil
+	case <-time.After(limit):
+		return "", nil, fmt.Errorf("no result after %dms", limit.Milliseconds())
+	}
+}
@@ -1,22 +1,35 @@
 package devbrowser
 
 import (
+	stdctx "context"
 	"fmt"
+	"strings"
 
 	"github.com/tinywasm/context"
 	"github.com/tinywasm/devbrowser/chromedp"
 	"github.com/tinywasm/mcp"
 )
 
-// GetStructureJS is the JavaScript used to extract the page structure for LLM understanding.
-const GetStructureJS = `
-(() => {
+// clickableHeuristicJS defines isClickable(el, style), the "is this
+// interactive" guess shared by GetStructureJS and set-of-marks screenshots.
+const clickableHeuristicJS = `
+	const isClickable = (el, style) => style.cursor === 'pointer' ||
+		['button', 'a', 'input', 'select'].includes(el.tagName.toLowerCase());
+`
+
+// structureJS is the function declaration behind GetStructureJS; it walks
+// the element it is called on, so a snapshot can start below the body.
+const structureJS = `function() {` + clickableHeuristicJS + `
+	// Interactive elements get a ref placeholder; the Go side resolves the
+	// elements left here to refs (see collectRefTargets).
+	const refTargets = window.` + refTargetsGlobal + ` = [];
 	const getStructure = (el, depth = 0) => {
 		if (depth > 12 || !el) return ''; 
 		
 		const tag = el.tagName.toLowerCase();
 		const indent = '  '.repeat(depth);
-		const style = window.getComputedStyle(el);
+		// Elements of frames walked inline belong to another window.
+		const style = el.ownerDocument.defaultView.getComputedStyle(el);
 		
 		// Skip invisible elements
 		if (style.display === 'none' || style.visibility === 'hidden' || style.opacity === '0') return '';
@@ -76,11 +89,11 @@ const GetStructureJS = `
         }
 		
 		// Indicate interactive items
-		const isClickable = window.getComputedStyle(el).cursor === 'pointer' || 
-						   tag === 'button' || tag === 'a' || tag === 'input' || tag === 'select';
-		if (isClickable) styles.push('clickable');
+		const clickable = isClickable(el, style);
+		if (clickable) styles.push('clickable');
 
 		if (styles.length > 0) result += ' [' + styles.join(' ') + ']';
+		if (clickable) result += ' ref:@' + (refTargets.push(el) - 1);
 		result += '>';
 		
 		if (directText) result += ' ' + directText;
@@ -90,18 +103,34 @@ const GetStructureJS = `
 		Array.from(el.children).forEach(child => {
 			result += getStructure(child, depth + 1);
 		});
+
+		// Open shadow roots and same-origin frames are walked in place; the
+		// Go side appends cross-origin frames as their own sections.
+		if (el.shadowRoot) {
+			result += indent + '  #shadow-root\n';
+			Array.from(el.shadowRoot.children).forEach(child => {
+				result += getStructure(child, depth + 2);
+			});
+		}
+		if (tag === 'iframe' || tag === 'frame') {
+			let doc = null;
+			try { doc = el.contentDocument; } catch (e) {}
+			if (doc && doc.body) result += indent + '  #document\n' + getStructure(doc.body, depth + 2);
+		}
 		
 		return result;
 	};
-	return getStructure(document.body);
-})()
-`
+	return getStructure(this);
+}`
+
+// GetStructureJS is the JavaScript used to extract the page structure for LLM understanding.
+const GetStructureJS = `(` + structureJS + `).call(document.body)`
 
 func (b *DevBrowser) GetStructureTools() []mcp.Tool {
 	return []mcp.Tool{
 		{
 			Name:        "browser_get_content",
-			Description: "Get a text-based representation of the page content, optimized for LLM reading. Reduced token count compared to screenshots.",
+			Description: "Get a text-based representation of the page content, optimized for LLM reading. Reduced token count compared to screenshots. format: structure (default; indented elements with attributes and layout hints), markdown (readable article-like text), text (plain text), links (every link with its URL) or forms (every form control with label, name, value and state). selector roots the output at an element (CSS, ref:e12 or an iframe/shadow path with >>>). max_chars caps the output: repeated siblings like list rows are collapsed first (\"… 48 more <li>\"), then the rest is cut with a note. In structure format open shadow roots (#shadow-root) and same-origin iframes (#document) are shown in place, cross-origin iframes as sections at the end. Interactive elements carry a ref (ref:e12) that any selector argument accepts and that stays valid until the element is removed or the page navigates. diff=true (structure only) returns only the nodes added, removed (or hidden) and changed since the previous call, or since the snapshot saved under checkpoint (a call with checkpoint and without diff saves one), with the same selector - ideal after a click or fill.",
 			Args: new(GetContentArgs),
 			Resource:    "browser",
 			Action:      'r',
@@ -115,6 +144,24 @@ func (b *DevBrowser) GetStructureTools() []mcp.Tool {
 					return nil, err
 				}
 
+				format := strings.ToLower(args.Format)
+				if format == "" {
+					format = "structure"
+				}
+				if !contentFormats[format] {
+					return nil, fmt.Errorf("unsupported format: %s. Use structure, markdown, text, links or forms", args.Format)
+				}
+				if format != "structure" && (args.Diff || args.Checkpoint != "") {
+					return nil, fmt.Errorf("diff and checkpoint only work with format structure")
+				}
+				if args.MaxChars < 0 {
+					return nil, fmt.Errorf("max_chars must be positive, got %d", args.MaxChars)
+				}
+				if err := b.checkSelector(args.Selector); err != nil {
+					return nil, err
+				}
+				maxChars := int(args.MaxChars)
+
 				var pageTitle, pageURL, structure string
 				var windowWidth, windowHeight int
 
@@ -123,11 +170,39 @@ func (b *DevBrowser) GetStructureTools() []mcp.Tool {
 					chromedp.Location(&pageURL),
 					chromedp.Evaluate(`window.innerWidth`, &windowWidth),
 					chromedp.Evaluate(`window.innerHeight`, &windowHeight),
-					chromedp.Evaluate(GetStructureJS, &structure),
 				)
+				if err == nil {
+					structure, err = b.pageContent(b.Ctx, format, args.Selector)
+				}
 
 				if err != nil {
-					return nil, fmt.Errorf("Failed to get page structure: %v", err)
+					return nil, fmt.Errorf("Failed to get page content: %v", err)
+				}
+
+				var content string
+				if format == "structure" {
+					content = TruncateStructure(structure, maxChars)
+				} else {
+					content = truncateContent(structure, maxChars)
+				}
+				if args.Diff {
+					baseline, since, err := b.structureBaseline(args.Checkpoint, args.Selector)
+					if err != nil {
+						return nil, err
+					}
+					switch diff := DiffStructure(baseline.structure, structure); {
+					case baseline.root != args.Selector:
+						content = "The previous snapshot was of " + snapshotRoot(baseline.root) + ", not of " + snapshotRoot(args.Selector) + "; full content:\n\n" + content
+					case baseline.structure == "":
+						content = "No previous snapshot to compare with; full content:\n\n" + content
+					case diff == "":
+						content = "No changes since " + since + ".\n"
+					default:
+						content = "Changes since " + since + ": " + truncateContent(diff, maxChars)
+					}
+				}
+				if format == "structure" {
+					b.rememberStructure(structureSnapshot{structure: structure, root: args.Selector}, args.Checkpoint, args.Diff)
 				}
 
 				report := fmt.Sprintf(
@@ -135,7 +210,7 @@ func (b *DevBrowser) GetStructureTools() []mcp.Tool {
 					pageURL,
 					pageTitle,
 					windowWidth, windowHeight,
-					structure,
+					content,
 				)
 
 				return mcp.Text(report), nil
@@ -143,3 +218,69 @@ func (b *DevBrowser) GetStructureTools() []mcp.Tool {
 		},
 	}
 }
+
+// pageContent renders the page, or the element of a selector argument, in
+// one of the contentFormats.
+func (b *DevBrowser) pageContent(ctx stdctx.Context, format, selector string) (string, error) {
+	fn := structureJS
+	if format != "structure" {
+		fn = contentFormatCall(format)
+	}
+	if selector != "" {
+		return b.elementSnapshot(ctx, selector, fn)
+	}
+	return b.pageSnapshot(ctx, "("+fn+").call(document.body)")
+}
+
+// structureSnapshot is a structure snapshot and the selector it was rooted
+// at ("" for the whole page): only snapshots of the same root compare.
+type structureSnapshot struct {
+	structure string
+	root      string
+}
+
+// snapshotRoot names what a snapshot rooted at root covers.
+func snapshotRoot(root string) string {
+	if root == "" {
+		return "the whole page"
+	}
+	return root
+}
+
+// structureBaseline returns the snapshot diff=true compares against and how
+// to name it: the checkpoint, or the previous browser_get_content call.
+// A checkpoint must have been saved for the same root; the previous
+// snapshot is returned whatever its root, for the caller to check.
+func (b *DevBrowser) structureBaseline(checkpoint, root string) (structureSnapshot, string, error) {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+	if checkpoint == "" {
+		if b.lastStructure.structure == "" {
+			return structureSnapshot{root: root}, "previous snapshot", nil
+		}
+		return b.lastStructure, "previous snapshot", nil
+	}
+	snap, ok := b.checkpoints[checkpoint]
+	if !ok {
+		return structureSnapshot{}, "", fmt.Errorf("unknown checkpoint %q: save it first with browser_get_content checkpoint=%q and no diff", checkpoint, checkpoint)
+	}
+	if snap.root != root {
+		return structureSnapshot{}, "", fmt.Errorf("checkpoint %q is a snapshot of %s, not of %s: diff it with the same selector", checkpoint, snapshotRoot(snap.root), snapshotRoot(root))
+	}
+	return snap, "checkpoint " + checkpoint, nil
+}
+
+// rememberStructure keeps snap as the previous snapshot and, outside a
+// diff, under the checkpoint name.
+func (b *DevBrowser) rememberStructure(snap structureSnapshot, checkpoint string, diff bool) {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+	b.lastStructure = snap
+	if checkpoint != "" && !diff {
+		if b.checkpoints == nil {
+			b.checkpoints = make(map[string]structureSnapshot)
+		}
+		b.checkpoints[checkpoint] = snap
+		b.markCheckpoint(checkpoint)
+	}
+}
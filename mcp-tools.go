@@ -14,14 +14,25 @@ var ErrBrowserNotOpen = fmt.Err(
 func (b *DevBrowser) GetMCPTools() []mcp.Tool {
 	tools := []mcp.Tool{}
 	tools = append(tools, b.GetManagementTools()...)
+	tools = append(tools, b.GetEnvironmentTools()...)
+	tools = append(tools, b.GetMediaTools()...)
 	tools = append(tools, b.GetConsoleTools()...)
 	tools = append(tools, b.GetScreenshotTools()...)
+	tools = append(tools, b.GetScreenshotMatrixTools()...)
+	tools = append(tools, b.GetScreenshotAnnotatedTools()...)
 	tools = append(tools, b.GetStructureTools()...)
+	tools = append(tools, b.GetAccessibilityTools()...)
 	tools = append(tools, b.GetEvaluateJsTools()...)
 	tools = append(tools, b.GetNetworkTools()...)
 	tools = append(tools, b.GetErrorTools()...)
 	tools = append(tools, b.GetInteractionTools()...)
+	tools = append(tools, b.GetFormTools()...)
+	tools = append(tools, b.GetWaitTools()...)
+	tools = append(tools, b.GetAssertTools()...)
+	tools = append(tools, b.GetStepTools()...)
+	tools = append(tools, b.GetDialogTools()...)
 	tools = append(tools, b.GetNavigationTools()...)
+	tools = append(tools, b.GetReloadTools()...)
 	tools = append(tools, b.GetInspectTools()...)
 	tools = append(tools, b.GetPerformanceTools()...)
 	tools = append(tools, b.GetAuditTools()...)
@@ -30,5 +41,6 @@ func (b *DevBrowser) GetMCPTools() []mcp.Tool {
 	tools = append(tools, b.GetStorageTools()...)
 	tools = append(tools, b.GetAssetTools()...)
 	tools = append(tools, b.GetInterceptTools()...)
+	tools = append(tools, b.GetRecordTools()...)
 	return tools
 }
@@ -0,0 +1,66 @@
+package devbrowser
+
+import (
+	"time"
+
+	"github.com/tinywasm/context"
+	"github.com/tinywasm/mcp"
+)
+
+func (b *DevBrowser) GetWaitTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "browser_wait_for",
+			Description: "Wait until a condition holds instead of sleeping, and report what was observed: an element (CSS selector, element ref (ref:e12), locator or selector path) in a state (visible by default, hidden, attached or detached); text present in the page or, with selector, in that element; the URL containing url (or matching /regexp/); no network request in flight for network_idle ms; no DOM mutation for dom_stable ms; or a js expression becoming truthy. Set exactly one condition. On timeout (default 5000ms) the error says what was last observed. Instead of a selector, ref takes an index from the last browser_screenshot with marks.",
+			Args:        new(WaitForArgs),
+			Resource:    "browser",
+			Action:      'r',
+			Execute: func(ctx *context.Context, req mcp.Request) (*mcp.Result, error) {
+				if !b.IsOpenFlag {
+					return nil, ErrBrowserNotOpen
+				}
+
+				var args WaitForArgs
+				if err := req.Bind(&args); err != nil {
+					return nil, err
+				}
+
+				cond, err := b.waitCondition(args)
+				if err != nil {
+					return nil, err
+				}
+
+				report, err := b.WaitFor(cond)
+				if err != nil {
+					return nil, err
+				}
+				return mcp.Text(report), nil
+			},
+		},
+	}
+}
+
+// waitCondition builds the WaitCondition of browser_wait_for arguments and
+// validates it, so that bad arguments fail before touching the browser.
+func (b *DevBrowser) waitCondition(args WaitForArgs) (WaitCondition, error) {
+	cond := WaitCondition{
+		State:       args.State,
+		Text:        args.Text,
+		URL:         args.Url,
+		NetworkIdle: time.Duration(args.NetworkIdle) * time.Millisecond,
+		DOMStable:   time.Duration(args.DomStable) * time.Millisecond,
+		JS:          args.Js,
+		Timeout:     time.Duration(args.Timeout) * time.Millisecond,
+	}
+	if args.Selector != "" || args.Ref != 0 {
+		selector, err := b.resolveElementTarget(args.Selector, args.Ref)
+		if err != nil {
+			return cond, err
+		}
+		if err := b.checkSelector(selector); err != nil {
+			return cond, err
+		}
+		cond.Selector = selector
+	}
+	return cond, cond.validate()
+}
@@ -0,0 +1,38 @@
+package devbrowser
+
+import "testing"
+
+// TestFormatAnnotationLegend guards the legend layout the agent relies on to
+// map numbers back to selectors: errors, match counts, the note for matches
+// past the cap, and document coordinates for full-page captures.
+func TestFormatAnnotationLegend(t *testing.T) {
+	marks := []annotationMark{
+		{N: 1, Selector: "#save", Match: 1, Total: 1, X: 10, Y: 20, PageX: 10, PageY: 620, Width: 80, Height: 30},
+		{Selector: "#missing", Error: "no match"},
+		{Selector: ".row", Match: 1, Total: 25, Error: "not rendered"},
+		{N: 2, Selector: ".row", Match: 2, Total: 25, X: 0, Y: 40, PageX: 0, PageY: 640, Width: 300, Height: 20},
+		{N: 21, Selector: ".row", Match: annotationMaxMatches, Total: 25, X: 0, Y: 400, PageX: 0, PageY: 1000, Width: 300, Height: 20},
+	}
+
+	want := "Legend:\n" +
+		"1. #save at x=10 y=20 w=80 h=30\n" +
+		"-  #missing: no match\n" +
+		"-  .row (match 1/25): not rendered\n" +
+		"2. .row (match 2/25) at x=0 y=40 w=300 h=20\n" +
+		"21. .row (match 20/25) at x=0 y=400 w=300 h=20\n" +
+		"   ... 5 more matches of .row not annotated\n"
+	if got := formatAnnotationLegend(marks, false); got != want {
+		t.Errorf("unexpected legend:\n%s\nwant:\n%s", got, want)
+	}
+
+	wantPage := "Legend:\n" +
+		"1. #save at x=10 y=620 w=80 h=30\n" +
+		"-  #missing: no match\n" +
+		"-  .row (match 1/25): not rendered\n" +
+		"2. .row (match 2/25) at x=0 y=640 w=300 h=20\n" +
+		"21. .row (match 20/25) at x=0 y=1000 w=300 h=20\n" +
+		"   ... 5 more matches of .row not annotated\n"
+	if got := formatAnnotationLegend(marks, true); got != wantPage {
+		t.Errorf("unexpected full-page legend:\n%s\nwant:\n%s", got, wantPage)
+	}
+}
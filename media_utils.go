@@ -0,0 +1,236 @@
+package devbrowser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tinywasm/devbrowser/cdproto/emulation"
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// Media is how the page is rendered for the user: the emulated CSS media
+// type and preference features, and a simulated vision deficiency. Empty
+// fields keep the real value.
+type Media struct {
+	ColorScheme   string `json:"color_scheme,omitempty"`   // prefers-color-scheme: light, dark or no-preference
+	ReducedMotion string `json:"reduced_motion,omitempty"` // prefers-reduced-motion: reduce or no-preference
+	Contrast      string `json:"contrast,omitempty"`       // prefers-contrast: more, less, custom or no-preference
+	ForcedColors  string `json:"forced_colors,omitempty"`  // forced-colors: active or none
+	Type          string `json:"type,omitempty"`           // @media type: screen or print
+	Vision        string `json:"vision,omitempty"`         // Vision deficiency: protanopia, deuteranopia, tritanopia, achromatopsia, blurred-vision, reduced-contrast or none
+}
+
+// mediaValues lists the values each Media field accepts, in Media order.
+var mediaValues = []struct {
+	feature string
+	values  []string
+}{
+	{"prefers-color-scheme", []string{"light", "dark", "no-preference"}},
+	{"prefers-reduced-motion", []string{"reduce", "no-preference"}},
+	{"prefers-contrast", []string{"more", "less", "custom", "no-preference"}},
+	{"forced-colors", []string{"active", "none"}},
+	{"media type", []string{"screen", "print"}},
+	{"vision deficiency", []string{"protanopia", "deuteranopia", "tritanopia", "achromatopsia", "blurred-vision", "reduced-contrast", "none"}},
+}
+
+// visionDeficiencies maps the Vision values to their CDP name.
+var visionDeficiencies = map[string]emulation.SetEmulatedVisionDeficiencyType{
+	"":                 emulation.SetEmulatedVisionDeficiencyTypeNone,
+	"none":             emulation.SetEmulatedVisionDeficiencyTypeNone,
+	"protanopia":       emulation.SetEmulatedVisionDeficiencyTypeProtanopia,
+	"deuteranopia":     emulation.SetEmulatedVisionDeficiencyTypeDeuteranopia,
+	"tritanopia":       emulation.SetEmulatedVisionDeficiencyTypeTritanopia,
+	"achromatopsia":    emulation.SetEmulatedVisionDeficiencyTypeAchromatopsia,
+	"blurred-vision":   emulation.SetEmulatedVisionDeficiencyTypeBlurredVision,
+	"reduced-contrast": emulation.SetEmulatedVisionDeficiencyTypeReducedContrast,
+}
+
+// visionDeficienciesList is the media list that captures the page with
+// normal vision and then with each deficiency.
+const visionDeficienciesList = "normal-vision, protanopia, deuteranopia, tritanopia, achromatopsia, blurred-vision, reduced-contrast"
+
+// mediaShorthands are the words of a media spec ("dark+reduced-motion").
+var mediaShorthands = map[string]Media{
+	"light":          {ColorScheme: "light"},
+	"dark":           {ColorScheme: "dark"},
+	"reduced-motion": {ReducedMotion: "reduce"},
+	"more-contrast":  {Contrast: "more"},
+	"less-contrast":  {Contrast: "less"},
+	"forced-colors":  {ForcedColors: "active"},
+	"print":          {Type: "print"},
+	"screen":         {Type: "screen"},
+
+	"normal-vision":    {Vision: "none"},
+	"protanopia":       {Vision: "protanopia"},
+	"deuteranopia":     {Vision: "deuteranopia"},
+	"tritanopia":       {Vision: "tritanopia"},
+	"achromatopsia":    {Vision: "achromatopsia"},
+	"blurred-vision":   {Vision: "blurred-vision"},
+	"reduced-contrast": {Vision: "reduced-contrast"},
+}
+
+func (m *Media) fields() []*string {
+	return []*string{&m.ColorScheme, &m.ReducedMotion, &m.Contrast, &m.ForcedColors, &m.Type, &m.Vision}
+}
+
+func (m Media) validate() error {
+	for i, f := range m.fields() {
+		if *f == "" {
+			continue
+		}
+		ok := false
+		for _, v := range mediaValues[i].values {
+			ok = ok || *f == v
+		}
+		if !ok {
+			return fmt.Errorf("unsupported %s: %s. Use %s", mediaValues[i].feature, *f, joinOr(mediaValues[i].values))
+		}
+	}
+	return nil
+}
+
+// merge returns m with the fields set in o replacing its own.
+func (m Media) merge(o Media) Media {
+	dst := m.fields()
+	for i, f := range o.fields() {
+		if *f != "" {
+			*dst[i] = *f
+		}
+	}
+	return m
+}
+
+// String lists the emulated media, or says there is none.
+func (m Media) String() string {
+	var parts []string
+	for i, f := range m.fields() {
+		if *f != "" {
+			parts = append(parts, mediaValues[i].feature+": "+*f)
+		}
+	}
+	if len(parts) == 0 {
+		return "no media emulation"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseMedia reads a media spec: shorthands joined by "+", such as "dark",
+// "print", "dark+reduced-motion+more-contrast" or "protanopia".
+func ParseMedia(spec string) (Media, error) {
+	var m Media
+	for _, word := range strings.Split(spec, "+") {
+		word = strings.ToLower(strings.TrimSpace(word))
+		s, ok := mediaShorthands[word]
+		if !ok {
+			return Media{}, fmt.Errorf("unsupported media: %s. Use light, dark, reduced-motion, more-contrast, less-contrast, forced-colors, print, screen or a vision deficiency (%s), joined by +", word, visionDeficienciesList)
+		}
+		m = m.merge(s)
+	}
+	return m, nil
+}
+
+// parseMediaList reads a comma-separated list of media specs ("light, dark").
+// vision-deficiencies stands for normal vision and every deficiency. A
+// variant listed twice, even spelled otherwise, is rejected: both captures
+// would share a file name.
+func parseMediaList(list string) ([]Media, []string, error) {
+	var variants []Media
+	var labels []string
+	var specs []string
+	for _, raw := range strings.Split(list, ",") {
+		spec := strings.TrimSpace(raw)
+		switch {
+		case spec == "":
+		case strings.EqualFold(spec, "vision-deficiencies"):
+			specs = append(specs, strings.Split(visionDeficienciesList, ", ")...)
+		case strings.Contains(strings.ToLower(spec), "vision-deficiencies"):
+			return nil, nil, fmt.Errorf("vision-deficiencies cannot be combined with +: %s. List it on its own and emulate the rest with browser_emulate_media", spec)
+		default:
+			specs = append(specs, spec)
+		}
+	}
+
+	seen := make(map[Media]string)
+	for _, spec := range specs {
+		m, err := ParseMedia(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		if first, ok := seen[m]; ok {
+			return nil, nil, fmt.Errorf("duplicate media: %s (already listed as %s)", spec, first)
+		}
+		seen[m] = spec
+		variants = append(variants, m)
+		labels = append(labels, strings.ToLower(strings.ReplaceAll(spec, " ", "")))
+	}
+	return variants, labels, nil
+}
+
+// joinOr joins values as "a, b or c".
+func joinOr(values []string) string {
+	if len(values) < 2 {
+		return strings.Join(values, "")
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
+
+// mediaAction emulates m. Chrome replaces the whole emulated media on
+// every call, so features left out go back to their real value.
+func mediaAction(m Media) chromedp.Action {
+	var features []*emulation.MediaFeature
+	for i, f := range m.fields()[:4] {
+		if *f != "" {
+			features = append(features, &emulation.MediaFeature{Name: mediaValues[i].feature, Value: *f})
+		}
+	}
+	return chromedp.Tasks{
+		emulation.SetEmulatedMedia().WithMedia(m.Type).WithFeatures(features),
+		emulation.SetEmulatedVisionDeficiency(visionDeficiencies[m.Vision]),
+	}
+}
+
+// SetMedia emulates m from now on, replacing the media set before. It is
+// persisted in the Store and applied at once when the browser is open.
+func (b *DevBrowser) SetMedia(m Media) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	b.Mu.Lock()
+	b.media = m
+	b.Mu.Unlock()
+
+	if b.IsOpen() && b.Ctx != nil {
+		if err := b.applyMedia(); err != nil {
+			return fmt.Errorf("Error applying media emulation: %v", err)
+		}
+	}
+	if err := b.SaveConfig(); err != nil {
+		b.Logger(fmt.Sprintf("Error saving media config: %v", err))
+	}
+	return nil
+}
+
+// GetMedia returns the emulated media.
+func (b *DevBrowser) GetMedia() Media {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+	return b.media
+}
+
+// applyMedia applies the persisted media.
+func (b *DevBrowser) applyMedia() error {
+	return chromedp.Run(b.Ctx, mediaAction(b.GetMedia()))
+}
+
+// encodeMedia is the Store value of m, "" when nothing is emulated.
+func encodeMedia(m Media) string {
+	if m == (Media{}) {
+		return ""
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -7,9 +7,14 @@ import "github.com/tinywasm/model"
 // responsabilidad de quien renderice estos valores en HTML.
 var (
 	// permittedSelector: selectores CSS (#btn, .card > a[href^='x'],
-	// div:nth-child(2n+1), [data-x="y"]).
+	// div:nth-child(2n+1), [data-x="y"]) y locators (text=Save!,
+	// role=button[name="Save"], xpath=//form/button[@type='submit']).
 	permittedSelector = model.Permitted{Letters: true, Numbers: true, Spaces: true,
-		Extra: []rune(`#.-_[]()>~+*:,='"^$|`)}
+		Extra: []rune(`#.-_[]()>~+*:,='"^$|/@!?&;%{}`)}
+	// permittedSelectorList: varios selectores CSS, uno por línea (la coma
+	// ya es parte de la sintaxis de selectores).
+	permittedSelectorList = model.Permitted{Letters: true, Numbers: true, Spaces: true, BreakLine: true,
+		Extra: []rune(`#.-_[]()>~+*:,='"^$|/@!?&;%{}`)}
 	// permittedURL: RFC 3986 (unreserved + reserved + %).
 	permittedURL = model.Permitted{Letters: true, Numbers: true,
 		Extra: []rune(`:/?#[]@!$&'()*+,;=-._~%`)}
@@ -21,19 +26,65 @@ var (
 	// permittedPath: paths on the filesystem (alphanumeric, spaces, separators, dots, hyphens, underscores).
 	permittedPath = model.Permitted{Letters: true, Numbers: true, Spaces: true,
 		Extra: []rune(`/._-\`)}
+	// permittedPathList: several filesystem paths, one per line.
+	permittedPathList = model.Permitted{Letters: true, Numbers: true, Spaces: true, BreakLine: true,
+		Extra: []rune(`/._-\`)}
+	// permittedName: short identifiers chosen by the caller (checkpoints).
+	permittedName = model.Permitted{Letters: true, Numbers: true,
+		Extra: []rune(`._-`)}
 )
 
 var ScreenshotArgsModel = model.Definition{
 	Name: "screenshot_args",
 	Fields: model.Fields{
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
 		{Name: "fullpage", Type: model.Bool()},
+		{Name: "marks", Type: model.Bool()},
+		{Name: "format", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "quality", Type: model.Int()},
+		{Name: "clip_x", Type: model.Float()},
+		{Name: "clip_y", Type: model.Float()},
+		{Name: "clip_width", Type: model.Float()},
+		{Name: "clip_height", Type: model.Float()},
+		{Name: "scale", Type: model.Float()},
+		{Name: "omit_background", Type: model.Bool()},
+		{Name: "media", Type: model.Text(), Permitted: permittedSelector},
 	},
 }
 
 var ClickElementArgsModel = model.Definition{
 	Name: "click_element_args",
 	Fields: model.Fields{
-		{Name: "selector", Type: model.Text(), NotNull: true, Permitted: permittedSelector},
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "ref", Type: model.Int()},
+		{Name: "button", Type: model.Text(), Permitted: permittedName},
+		{Name: "click_count", Type: model.Int()},
+		{Name: "hold", Type: model.Int()},
+		{Name: "wait_after", Type: model.Int()},
+		{Name: "timeout", Type: model.Int()},
+	},
+}
+
+var HoverElementArgsModel = model.Definition{
+	Name: "hover_element_args",
+	Fields: model.Fields{
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "ref", Type: model.Int()},
+		{Name: "wait_after", Type: model.Int()},
+		{Name: "timeout", Type: model.Int()},
+	},
+}
+
+var DragElementArgsModel = model.Definition{
+	Name: "drag_element_args",
+	Fields: model.Fields{
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "ref", Type: model.Int()},
+		{Name: "to", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "to_x", Type: model.Int()},
+		{Name: "to_y", Type: model.Int()},
+		{Name: "steps", Type: model.Int()},
+		{Name: "hold", Type: model.Int()},
 		{Name: "wait_after", Type: model.Int()},
 		{Name: "timeout", Type: model.Int()},
 	},
@@ -42,7 +93,11 @@ var ClickElementArgsModel = model.Definition{
 var NavigateArgsModel = model.Definition{
 	Name: "navigate_args",
 	Fields: model.Fields{
-		{Name: "url", Type: model.Text(), NotNull: true, Permitted: permittedURL},
+		{Name: "url", Type: model.Text(), Permitted: permittedURL},
+		{Name: "action", Type: model.Text(), Permitted: permittedName},
+		{Name: "wait_until", Type: model.Text(), Permitted: permittedName},
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "timeout", Type: model.Int()},
 	},
 }
 
@@ -73,8 +128,80 @@ var GetConsoleArgsModel = model.Definition{
 var FillElementArgsModel = model.Definition{
 	Name: "fill_element_args",
 	Fields: model.Fields{
-		{Name: "selector", Type: model.Text(), NotNull: true, Permitted: permittedSelector},
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "ref", Type: model.Int()},
+		{Name: "value", Type: model.Text(), NotNull: true, Permitted: permittedFree},
+		{Name: "append", Type: model.Bool()},
+		{Name: "wait_after", Type: model.Int()},
+		{Name: "timeout", Type: model.Int()},
+	},
+}
+
+var SelectOptionArgsModel = model.Definition{
+	Name: "select_option_args",
+	Fields: model.Fields{
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "ref", Type: model.Int()},
 		{Name: "value", Type: model.Text(), NotNull: true, Permitted: permittedFree},
+		{Name: "by", Type: model.Text(), Permitted: permittedName},
+		{Name: "wait_after", Type: model.Int()},
+		{Name: "timeout", Type: model.Int()},
+	},
+}
+
+var CheckElementArgsModel = model.Definition{
+	Name: "check_element_args",
+	Fields: model.Fields{
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "ref", Type: model.Int()},
+		{Name: "uncheck", Type: model.Bool()},
+		{Name: "wait_after", Type: model.Int()},
+		{Name: "timeout", Type: model.Int()},
+	},
+}
+
+var UploadFilesArgsModel = model.Definition{
+	Name: "upload_files_args",
+	Fields: model.Fields{
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "ref", Type: model.Int()},
+		{Name: "files", Type: model.Text(), NotNull: true, Permitted: permittedPathList},
+		{Name: "wait_after", Type: model.Int()},
+		{Name: "timeout", Type: model.Int()},
+	},
+}
+
+var FillFormArgsModel = model.Definition{
+	Name: "fill_form_args",
+	Fields: model.Fields{
+		{Name: "fields", Type: model.Text(), NotNull: true, Permitted: permittedFree},
+		{Name: "wait_after", Type: model.Int()},
+		{Name: "timeout", Type: model.Int()},
+	},
+}
+
+var PressKeyArgsModel = model.Definition{
+	Name: "press_key_args",
+	Fields: model.Fields{
+		{Name: "keys", Type: model.Text(), NotNull: true, Permitted: permittedFree},
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "ref", Type: model.Int()},
+		{Name: "wait_after", Type: model.Int()},
+		{Name: "timeout", Type: model.Int()},
+	},
+}
+
+var GestureArgsModel = model.Definition{
+	Name: "gesture_args",
+	Fields: model.Fields{
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "ref", Type: model.Int()},
+		{Name: "gesture", Type: model.Text(), NotNull: true, Permitted: permittedName},
+		{Name: "direction", Type: model.Text(), Permitted: permittedName},
+		{Name: "distance", Type: model.Int()},
+		{Name: "angle", Type: model.Float()},
+		{Name: "duration", Type: model.Int()},
+		{Name: "input", Type: model.Text(), Permitted: permittedName},
 		{Name: "wait_after", Type: model.Int()},
 		{Name: "timeout", Type: model.Int()},
 	},
@@ -119,7 +246,13 @@ var GetPerformanceArgsModel = model.Definition{
 
 var GetContentArgsModel = model.Definition{
 	Name: "get_content_args",
-	Fields: model.Fields{},
+	Fields: model.Fields{
+		{Name: "format", Type: model.Text(), Permitted: permittedName},
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "max_chars", Type: model.Int()},
+		{Name: "diff", Type: model.Bool()},
+		{Name: "checkpoint", Type: model.Text(), Permitted: permittedName},
+	},
 }
 
 var GetSourceArgsModel = model.Definition{
@@ -188,6 +321,28 @@ var SaveScreenshotArgsModel = model.Definition{
 		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
 		{Name: "fullpage", Type: model.Bool()},
 		{Name: "overwrite", Type: model.Bool()},
+		{Name: "format", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "quality", Type: model.Int()},
+		{Name: "clip_x", Type: model.Float()},
+		{Name: "clip_y", Type: model.Float()},
+		{Name: "clip_width", Type: model.Float()},
+		{Name: "clip_height", Type: model.Float()},
+		{Name: "scale", Type: model.Float()},
+		{Name: "omit_background", Type: model.Bool()},
+		{Name: "media", Type: model.Text(), Permitted: permittedSelector},
+	},
+}
+
+var ScreenshotMatrixArgsModel = model.Definition{
+	Name: "screenshot_matrix_args",
+	Fields: model.Fields{
+		{Name: "targets", Type: model.Text(), NotNull: true, Permitted: permittedSelector},
+		{Name: "fullpage", Type: model.Bool()},
+		{Name: "dir", Type: model.Text(), Permitted: permittedPath},
+		{Name: "name", Type: model.Text(), Permitted: permittedPath},
+		{Name: "contact_sheet", Type: model.Bool()},
+		{Name: "overwrite", Type: model.Bool()},
+		{Name: "media", Type: model.Text(), Permitted: permittedSelector},
 	},
 }
 
@@ -198,3 +353,127 @@ type InterceptedRequest struct {
 	ResponseBody string
 	Status       int
 }
+
+var RecordStartArgsModel = model.Definition{
+	Name: "record_start_args",
+	Fields: model.Fields{
+		{Name: "fps", Type: model.Int()},
+		{Name: "max_duration", Type: model.Int()},
+		{Name: "scale", Type: model.Float()},
+		{Name: "quality", Type: model.Int()},
+	},
+}
+
+var RecordStopArgsModel = model.Definition{
+	Name: "record_stop_args",
+	Fields: model.Fields{
+		{Name: "dir", Type: model.Text(), NotNull: true, Permitted: permittedPath},
+		{Name: "name", Type: model.Text(), Permitted: permittedPath},
+		{Name: "encode", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "overwrite", Type: model.Bool()},
+	},
+}
+
+var ScreenshotAnnotatedArgsModel = model.Definition{
+	Name: "screenshot_annotated_args",
+	Fields: model.Fields{
+		{Name: "selectors", Type: model.Text(), NotNull: true, Permitted: permittedSelectorList},
+		{Name: "fullpage", Type: model.Bool()},
+		{Name: "format", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "quality", Type: model.Int()},
+		{Name: "scale", Type: model.Float()},
+	},
+}
+
+var GetAccessibilityTreeArgsModel = model.Definition{
+	Name: "get_accessibility_tree_args",
+	Fields: model.Fields{
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "all", Type: model.Bool()},
+		{Name: "max_depth", Type: model.Int()},
+	},
+}
+
+var WaitForArgsModel = model.Definition{
+	Name: "wait_for_args",
+	Fields: model.Fields{
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "ref", Type: model.Int()},
+		{Name: "state", Type: model.Text(), Permitted: permittedName},
+		{Name: "text", Type: model.Text(), Permitted: permittedFree},
+		{Name: "url", Type: model.Text(), Permitted: permittedFree},
+		{Name: "network_idle", Type: model.Int()},
+		{Name: "dom_stable", Type: model.Int()},
+		{Name: "js", Type: model.Text(), Permitted: permittedFree},
+		{Name: "timeout", Type: model.Int()},
+	},
+}
+
+var RunStepsArgsModel = model.Definition{
+	Name: "run_steps_args",
+	Fields: model.Fields{
+		{Name: "steps", Type: model.Text(), NotNull: true, Permitted: permittedFree},
+		{Name: "timeout", Type: model.Int()},
+	},
+}
+
+var AssertArgsModel = model.Definition{
+	Name: "assert_args",
+	Fields: model.Fields{
+		{Name: "type", Type: model.Text(), Permitted: permittedName},
+		{Name: "selector", Type: model.Text(), Permitted: permittedSelector},
+		{Name: "ref", Type: model.Int()},
+		{Name: "name", Type: model.Text(), Permitted: permittedFree},
+		{Name: "op", Type: model.Text(), Permitted: permittedName},
+		{Name: "expected", Type: model.Text(), Permitted: permittedFree},
+		{Name: "not", Type: model.Bool()},
+		{Name: "since", Type: model.Text(), Permitted: permittedName},
+		{Name: "checkpoint", Type: model.Text(), Permitted: permittedName},
+	},
+}
+
+var HandleDialogArgsModel = model.Definition{
+	Name: "handle_dialog_args",
+	Fields: model.Fields{
+		{Name: "action", Type: model.Text(), Permitted: permittedName},
+		{Name: "prompt_text", Type: model.Text(), Permitted: permittedFree},
+		{Name: "policy", Type: model.Text(), Permitted: permittedName},
+	},
+}
+
+var ReloadAssetsArgsModel = model.Definition{
+	Name: "reload_assets_args",
+	Fields: model.Fields{
+		{Name: "kind", Type: model.Text(), Permitted: permittedName},
+		{Name: "url", Type: model.Text(), Permitted: permittedURL},
+	},
+}
+
+var EmulateEnvironmentArgsModel = model.Definition{
+	Name: "emulate_environment_args",
+	Fields: model.Fields{
+		{Name: "geolocation", Type: model.Text(), Permitted: permittedFree},
+		{Name: "timezone", Type: model.Text(), Permitted: permittedFree},
+		{Name: "locale", Type: model.Text(), Permitted: permittedName},
+		{Name: "accept_language", Type: model.Text(), Permitted: permittedFree},
+		{Name: "user_agent", Type: model.Text(), Permitted: permittedFree},
+		{Name: "ua_platform", Type: model.Text(), Permitted: permittedFree},
+		{Name: "ua_platform_version", Type: model.Text(), Permitted: permittedName},
+		{Name: "ua_model", Type: model.Text(), Permitted: permittedFree},
+		{Name: "ua_mobile", Type: model.Bool()},
+		{Name: "reset", Type: model.Bool()},
+	},
+}
+
+var EmulateMediaArgsModel = model.Definition{
+	Name: "emulate_media_args",
+	Fields: model.Fields{
+		{Name: "color_scheme", Type: model.Text(), Permitted: permittedName},
+		{Name: "reduced_motion", Type: model.Text(), Permitted: permittedName},
+		{Name: "contrast", Type: model.Text(), Permitted: permittedName},
+		{Name: "forced_colors", Type: model.Text(), Permitted: permittedName},
+		{Name: "media_type", Type: model.Text(), Permitted: permittedName},
+		{Name: "vision", Type: model.Text(), Permitted: permittedName},
+		{Name: "reset", Type: model.Bool()},
+	},
+}
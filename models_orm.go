@@ -7,23 +7,56 @@ import (
 )
 
 type ScreenshotArgs struct {
+	Selector string
 	Fullpage bool
+	Marks bool
+	Format string
+	Quality int64
+	ClipX float64
+	ClipY float64
+	ClipWidth float64
+	ClipHeight float64
+	Scale float64
+	OmitBackground bool
+	Media string
 }
 
 func (m *ScreenshotArgs) ModelName() string { return "screenshot_args" }
 
 func (m *ScreenshotArgs) Schema() []model.Field { return ScreenshotArgsModel.Fields }
 
-func (m *ScreenshotArgs) Pointers() []any { return []any{&m.Fullpage} }
+func (m *ScreenshotArgs) Pointers() []any { return []any{&m.Selector, &m.Fullpage, &m.Marks, &m.Format, &m.Quality, &m.ClipX, &m.ClipY, &m.ClipWidth, &m.ClipHeight, &m.Scale, &m.OmitBackground, &m.Media} }
 
 func (m *ScreenshotArgs) IsNil() bool { return m == nil }
 
 func (m *ScreenshotArgs) EncodeFields(w model.FieldWriter) {
+	w.String("selector", m.Selector)
 	w.Bool("fullpage", m.Fullpage)
+	w.Bool("marks", m.Marks)
+	w.String("format", m.Format)
+	w.Int("quality", m.Quality)
+	w.Float("clip_x", m.ClipX)
+	w.Float("clip_y", m.ClipY)
+	w.Float("clip_width", m.ClipWidth)
+	w.Float("clip_height", m.ClipHeight)
+	w.Float("scale", m.Scale)
+	w.Bool("omit_background", m.OmitBackground)
+	w.String("media", m.Media)
 }
 
 func (m *ScreenshotArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("selector"); ok { m.Selector = v }
 	if v, ok := r.Bool("fullpage"); ok { m.Fullpage = v }
+	if v, ok := r.Bool("marks"); ok { m.Marks = v }
+	if v, ok := r.String("format"); ok { m.Format = v }
+	if v, ok := r.Int("quality"); ok { m.Quality = v }
+	if v, ok := r.Float("clip_x"); ok { m.ClipX = v }
+	if v, ok := r.Float("clip_y"); ok { m.ClipY = v }
+	if v, ok := r.Float("clip_width"); ok { m.ClipWidth = v }
+	if v, ok := r.Float("clip_height"); ok { m.ClipHeight = v }
+	if v, ok := r.Float("scale"); ok { m.Scale = v }
+	if v, ok := r.Bool("omit_background"); ok { m.OmitBackground = v }
+	if v, ok := r.String("media"); ok { m.Media = v }
 }
 
 type ScreenshotArgsList []*ScreenshotArgs
@@ -43,6 +76,10 @@ func (m *ScreenshotArgs) Validate(action byte) error {
 
 type ClickElementArgs struct {
 	Selector string
+	Ref int64
+	Button string
+	ClickCount int64
+	Hold int64
 	WaitAfter int64
 	Timeout int64
 }
@@ -51,18 +88,26 @@ func (m *ClickElementArgs) ModelName() string { return "click_element_args" }
 
 func (m *ClickElementArgs) Schema() []model.Field { return ClickElementArgsModel.Fields }
 
-func (m *ClickElementArgs) Pointers() []any { return []any{&m.Selector, &m.WaitAfter, &m.Timeout} }
+func (m *ClickElementArgs) Pointers() []any { return []any{&m.Selector, &m.Ref, &m.Button, &m.ClickCount, &m.Hold, &m.WaitAfter, &m.Timeout} }
 
 func (m *ClickElementArgs) IsNil() bool { return m == nil }
 
 func (m *ClickElementArgs) EncodeFields(w model.FieldWriter) {
 	w.String("selector", m.Selector)
+	w.Int("ref", m.Ref)
+	w.String("button", m.Button)
+	w.Int("click_count", m.ClickCount)
+	w.Int("hold", m.Hold)
 	w.Int("wait_after", m.WaitAfter)
 	w.Int("timeout", m.Timeout)
 }
 
 func (m *ClickElementArgs) DecodeFields(r model.FieldReader) {
 	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("ref"); ok { m.Ref = v }
+	if v, ok := r.String("button"); ok { m.Button = v }
+	if v, ok := r.Int("click_count"); ok { m.ClickCount = v }
+	if v, ok := r.Int("hold"); ok { m.Hold = v }
 	if v, ok := r.Int("wait_after"); ok { m.WaitAfter = v }
 	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
 }
@@ -82,24 +127,139 @@ func (m *ClickElementArgs) Validate(action byte) error {
 	return model.ValidateFields(action, m)
 }
 
+type HoverElementArgs struct {
+	Selector string
+	Ref int64
+	WaitAfter int64
+	Timeout int64
+}
+
+func (m *HoverElementArgs) ModelName() string { return "hover_element_args" }
+
+func (m *HoverElementArgs) Schema() []model.Field { return HoverElementArgsModel.Fields }
+
+func (m *HoverElementArgs) Pointers() []any { return []any{&m.Selector, &m.Ref, &m.WaitAfter, &m.Timeout} }
+
+func (m *HoverElementArgs) IsNil() bool { return m == nil }
+
+func (m *HoverElementArgs) EncodeFields(w model.FieldWriter) {
+	w.String("selector", m.Selector)
+	w.Int("ref", m.Ref)
+	w.Int("wait_after", m.WaitAfter)
+	w.Int("timeout", m.Timeout)
+}
+
+func (m *HoverElementArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("ref"); ok { m.Ref = v }
+	if v, ok := r.Int("wait_after"); ok { m.WaitAfter = v }
+	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
+}
+
+type HoverElementArgsList []*HoverElementArgs
+
+func (s *HoverElementArgsList) Schema() []model.Field { return nil }
+func (s *HoverElementArgsList) Pointers() []any     { return nil }
+func (s *HoverElementArgsList) Len() int             { return len(*s) }
+func (s *HoverElementArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *HoverElementArgsList) Append() model.Fielder  { v := &HoverElementArgs{}; *s = append(*s, v); return v }
+func (s *HoverElementArgsList) IsNil() bool          { return s == nil }
+func (s *HoverElementArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *HoverElementArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *HoverElementArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type DragElementArgs struct {
+	Selector string
+	Ref int64
+	To string
+	ToX int64
+	ToY int64
+	Steps int64
+	Hold int64
+	WaitAfter int64
+	Timeout int64
+}
+
+func (m *DragElementArgs) ModelName() string { return "drag_element_args" }
+
+func (m *DragElementArgs) Schema() []model.Field { return DragElementArgsModel.Fields }
+
+func (m *DragElementArgs) Pointers() []any { return []any{&m.Selector, &m.Ref, &m.To, &m.ToX, &m.ToY, &m.Steps, &m.Hold, &m.WaitAfter, &m.Timeout} }
+
+func (m *DragElementArgs) IsNil() bool { return m == nil }
+
+func (m *DragElementArgs) EncodeFields(w model.FieldWriter) {
+	w.String("selector", m.Selector)
+	w.Int("ref", m.Ref)
+	w.String("to", m.To)
+	w.Int("to_x", m.ToX)
+	w.Int("to_y", m.ToY)
+	w.Int("steps", m.Steps)
+	w.Int("hold", m.Hold)
+	w.Int("wait_after", m.WaitAfter)
+	w.Int("timeout", m.Timeout)
+}
+
+func (m *DragElementArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("ref"); ok { m.Ref = v }
+	if v, ok := r.String("to"); ok { m.To = v }
+	if v, ok := r.Int("to_x"); ok { m.ToX = v }
+	if v, ok := r.Int("to_y"); ok { m.ToY = v }
+	if v, ok := r.Int("steps"); ok { m.Steps = v }
+	if v, ok := r.Int("hold"); ok { m.Hold = v }
+	if v, ok := r.Int("wait_after"); ok { m.WaitAfter = v }
+	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
+}
+
+type DragElementArgsList []*DragElementArgs
+
+func (s *DragElementArgsList) Schema() []model.Field { return nil }
+func (s *DragElementArgsList) Pointers() []any     { return nil }
+func (s *DragElementArgsList) Len() int             { return len(*s) }
+func (s *DragElementArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *DragElementArgsList) Append() model.Fielder  { v := &DragElementArgs{}; *s = append(*s, v); return v }
+func (s *DragElementArgsList) IsNil() bool          { return s == nil }
+func (s *DragElementArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *DragElementArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *DragElementArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
 type NavigateArgs struct {
 	Url string
+	Action string
+	WaitUntil string
+	Selector string
+	Timeout int64
 }
 
 func (m *NavigateArgs) ModelName() string { return "navigate_args" }
 
 func (m *NavigateArgs) Schema() []model.Field { return NavigateArgsModel.Fields }
 
-func (m *NavigateArgs) Pointers() []any { return []any{&m.Url} }
+func (m *NavigateArgs) Pointers() []any { return []any{&m.Url, &m.Action, &m.WaitUntil, &m.Selector, &m.Timeout} }
 
 func (m *NavigateArgs) IsNil() bool { return m == nil }
 
 func (m *NavigateArgs) EncodeFields(w model.FieldWriter) {
 	w.String("url", m.Url)
+	w.String("action", m.Action)
+	w.String("wait_until", m.WaitUntil)
+	w.String("selector", m.Selector)
+	w.Int("timeout", m.Timeout)
 }
 
 func (m *NavigateArgs) DecodeFields(r model.FieldReader) {
 	if v, ok := r.String("url"); ok { m.Url = v }
+	if v, ok := r.String("action"); ok { m.Action = v }
+	if v, ok := r.String("wait_until"); ok { m.WaitUntil = v }
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
 }
 
 type NavigateArgsList []*NavigateArgs
@@ -233,7 +393,9 @@ func (m *GetConsoleArgs) Validate(action byte) error {
 
 type FillElementArgs struct {
 	Selector string
+	Ref int64
 	Value string
+	Append bool
 	WaitAfter int64
 	Timeout int64
 }
@@ -242,20 +404,24 @@ func (m *FillElementArgs) ModelName() string { return "fill_element_args" }
 
 func (m *FillElementArgs) Schema() []model.Field { return FillElementArgsModel.Fields }
 
-func (m *FillElementArgs) Pointers() []any { return []any{&m.Selector, &m.Value, &m.WaitAfter, &m.Timeout} }
+func (m *FillElementArgs) Pointers() []any { return []any{&m.Selector, &m.Ref, &m.Value, &m.Append, &m.WaitAfter, &m.Timeout} }
 
 func (m *FillElementArgs) IsNil() bool { return m == nil }
 
 func (m *FillElementArgs) EncodeFields(w model.FieldWriter) {
 	w.String("selector", m.Selector)
+	w.Int("ref", m.Ref)
 	w.String("value", m.Value)
+	w.Bool("append", m.Append)
 	w.Int("wait_after", m.WaitAfter)
 	w.Int("timeout", m.Timeout)
 }
 
 func (m *FillElementArgs) DecodeFields(r model.FieldReader) {
 	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("ref"); ok { m.Ref = v }
 	if v, ok := r.String("value"); ok { m.Value = v }
+	if v, ok := r.Bool("append"); ok { m.Append = v }
 	if v, ok := r.Int("wait_after"); ok { m.WaitAfter = v }
 	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
 }
@@ -275,6 +441,300 @@ func (m *FillElementArgs) Validate(action byte) error {
 	return model.ValidateFields(action, m)
 }
 
+type SelectOptionArgs struct {
+	Selector string
+	Ref int64
+	Value string
+	By string
+	WaitAfter int64
+	Timeout int64
+}
+
+func (m *SelectOptionArgs) ModelName() string { return "select_option_args" }
+
+func (m *SelectOptionArgs) Schema() []model.Field { return SelectOptionArgsModel.Fields }
+
+func (m *SelectOptionArgs) Pointers() []any { return []any{&m.Selector, &m.Ref, &m.Value, &m.By, &m.WaitAfter, &m.Timeout} }
+
+func (m *SelectOptionArgs) IsNil() bool { return m == nil }
+
+func (m *SelectOptionArgs) EncodeFields(w model.FieldWriter) {
+	w.String("selector", m.Selector)
+	w.Int("ref", m.Ref)
+	w.String("value", m.Value)
+	w.String("by", m.By)
+	w.Int("wait_after", m.WaitAfter)
+	w.Int("timeout", m.Timeout)
+}
+
+func (m *SelectOptionArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("ref"); ok { m.Ref = v }
+	if v, ok := r.String("value"); ok { m.Value = v }
+	if v, ok := r.String("by"); ok { m.By = v }
+	if v, ok := r.Int("wait_after"); ok { m.WaitAfter = v }
+	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
+}
+
+type SelectOptionArgsList []*SelectOptionArgs
+
+func (s *SelectOptionArgsList) Schema() []model.Field { return nil }
+func (s *SelectOptionArgsList) Pointers() []any     { return nil }
+func (s *SelectOptionArgsList) Len() int             { return len(*s) }
+func (s *SelectOptionArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *SelectOptionArgsList) Append() model.Fielder  { v := &SelectOptionArgs{}; *s = append(*s, v); return v }
+func (s *SelectOptionArgsList) IsNil() bool          { return s == nil }
+func (s *SelectOptionArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *SelectOptionArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *SelectOptionArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type CheckElementArgs struct {
+	Selector string
+	Ref int64
+	Uncheck bool
+	WaitAfter int64
+	Timeout int64
+}
+
+func (m *CheckElementArgs) ModelName() string { return "check_element_args" }
+
+func (m *CheckElementArgs) Schema() []model.Field { return CheckElementArgsModel.Fields }
+
+func (m *CheckElementArgs) Pointers() []any { return []any{&m.Selector, &m.Ref, &m.Uncheck, &m.WaitAfter, &m.Timeout} }
+
+func (m *CheckElementArgs) IsNil() bool { return m == nil }
+
+func (m *CheckElementArgs) EncodeFields(w model.FieldWriter) {
+	w.String("selector", m.Selector)
+	w.Int("ref", m.Ref)
+	w.Bool("uncheck", m.Uncheck)
+	w.Int("wait_after", m.WaitAfter)
+	w.Int("timeout", m.Timeout)
+}
+
+func (m *CheckElementArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("ref"); ok { m.Ref = v }
+	if v, ok := r.Bool("uncheck"); ok { m.Uncheck = v }
+	if v, ok := r.Int("wait_after"); ok { m.WaitAfter = v }
+	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
+}
+
+type CheckElementArgsList []*CheckElementArgs
+
+func (s *CheckElementArgsList) Schema() []model.Field { return nil }
+func (s *CheckElementArgsList) Pointers() []any     { return nil }
+func (s *CheckElementArgsList) Len() int             { return len(*s) }
+func (s *CheckElementArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *CheckElementArgsList) Append() model.Fielder  { v := &CheckElementArgs{}; *s = append(*s, v); return v }
+func (s *CheckElementArgsList) IsNil() bool          { return s == nil }
+func (s *CheckElementArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *CheckElementArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *CheckElementArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type UploadFilesArgs struct {
+	Selector string
+	Ref int64
+	Files string
+	WaitAfter int64
+	Timeout int64
+}
+
+func (m *UploadFilesArgs) ModelName() string { return "upload_files_args" }
+
+func (m *UploadFilesArgs) Schema() []model.Field { return UploadFilesArgsModel.Fields }
+
+func (m *UploadFilesArgs) Pointers() []any { return []any{&m.Selector, &m.Ref, &m.Files, &m.WaitAfter, &m.Timeout} }
+
+func (m *UploadFilesArgs) IsNil() bool { return m == nil }
+
+func (m *UploadFilesArgs) EncodeFields(w model.FieldWriter) {
+	w.String("selector", m.Selector)
+	w.Int("ref", m.Ref)
+	w.String("files", m.Files)
+	w.Int("wait_after", m.WaitAfter)
+	w.Int("timeout", m.Timeout)
+}
+
+func (m *UploadFilesArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("ref"); ok { m.Ref = v }
+	if v, ok := r.String("files"); ok { m.Files = v }
+	if v, ok := r.Int("wait_after"); ok { m.WaitAfter = v }
+	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
+}
+
+type UploadFilesArgsList []*UploadFilesArgs
+
+func (s *UploadFilesArgsList) Schema() []model.Field { return nil }
+func (s *UploadFilesArgsList) Pointers() []any     { return nil }
+func (s *UploadFilesArgsList) Len() int             { return len(*s) }
+func (s *UploadFilesArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *UploadFilesArgsList) Append() model.Fielder  { v := &UploadFilesArgs{}; *s = append(*s, v); return v }
+func (s *UploadFilesArgsList) IsNil() bool          { return s == nil }
+func (s *UploadFilesArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *UploadFilesArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *UploadFilesArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type FillFormArgs struct {
+	Fields string
+	WaitAfter int64
+	Timeout int64
+}
+
+func (m *FillFormArgs) ModelName() string { return "fill_form_args" }
+
+func (m *FillFormArgs) Schema() []model.Field { return FillFormArgsModel.Fields }
+
+func (m *FillFormArgs) Pointers() []any { return []any{&m.Fields, &m.WaitAfter, &m.Timeout} }
+
+func (m *FillFormArgs) IsNil() bool { return m == nil }
+
+func (m *FillFormArgs) EncodeFields(w model.FieldWriter) {
+	w.String("fields", m.Fields)
+	w.Int("wait_after", m.WaitAfter)
+	w.Int("timeout", m.Timeout)
+}
+
+func (m *FillFormArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("fields"); ok { m.Fields = v }
+	if v, ok := r.Int("wait_after"); ok { m.WaitAfter = v }
+	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
+}
+
+type FillFormArgsList []*FillFormArgs
+
+func (s *FillFormArgsList) Schema() []model.Field { return nil }
+func (s *FillFormArgsList) Pointers() []any     { return nil }
+func (s *FillFormArgsList) Len() int             { return len(*s) }
+func (s *FillFormArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *FillFormArgsList) Append() model.Fielder  { v := &FillFormArgs{}; *s = append(*s, v); return v }
+func (s *FillFormArgsList) IsNil() bool          { return s == nil }
+func (s *FillFormArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *FillFormArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *FillFormArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type PressKeyArgs struct {
+	Keys string
+	Selector string
+	Ref int64
+	WaitAfter int64
+	Timeout int64
+}
+
+func (m *PressKeyArgs) ModelName() string { return "press_key_args" }
+
+func (m *PressKeyArgs) Schema() []model.Field { return PressKeyArgsModel.Fields }
+
+func (m *PressKeyArgs) Pointers() []any { return []any{&m.Keys, &m.Selector, &m.Ref, &m.WaitAfter, &m.Timeout} }
+
+func (m *PressKeyArgs) IsNil() bool { return m == nil }
+
+func (m *PressKeyArgs) EncodeFields(w model.FieldWriter) {
+	w.String("keys", m.Keys)
+	w.String("selector", m.Selector)
+	w.Int("ref", m.Ref)
+	w.Int("wait_after", m.WaitAfter)
+	w.Int("timeout", m.Timeout)
+}
+
+func (m *PressKeyArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("keys"); ok { m.Keys = v }
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("ref"); ok { m.Ref = v }
+	if v, ok := r.Int("wait_after"); ok { m.WaitAfter = v }
+	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
+}
+
+type PressKeyArgsList []*PressKeyArgs
+
+func (s *PressKeyArgsList) Schema() []model.Field { return nil }
+func (s *PressKeyArgsList) Pointers() []any     { return nil }
+func (s *PressKeyArgsList) Len() int             { return len(*s) }
+func (s *PressKeyArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *PressKeyArgsList) Append() model.Fielder  { v := &PressKeyArgs{}; *s = append(*s, v); return v }
+func (s *PressKeyArgsList) IsNil() bool          { return s == nil }
+func (s *PressKeyArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *PressKeyArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *PressKeyArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type GestureArgs struct {
+	Selector string
+	Ref int64
+	Gesture string
+	Direction string
+	Distance int64
+	Angle float64
+	Duration int64
+	Input string
+	WaitAfter int64
+	Timeout int64
+}
+
+func (m *GestureArgs) ModelName() string { return "gesture_args" }
+
+func (m *GestureArgs) Schema() []model.Field { return GestureArgsModel.Fields }
+
+func (m *GestureArgs) Pointers() []any { return []any{&m.Selector, &m.Ref, &m.Gesture, &m.Direction, &m.Distance, &m.Angle, &m.Duration, &m.Input, &m.WaitAfter, &m.Timeout} }
+
+func (m *GestureArgs) IsNil() bool { return m == nil }
+
+func (m *GestureArgs) EncodeFields(w model.FieldWriter) {
+	w.String("selector", m.Selector)
+	w.Int("ref", m.Ref)
+	w.String("gesture", m.Gesture)
+	w.String("direction", m.Direction)
+	w.Int("distance", m.Distance)
+	w.Float("angle", m.Angle)
+	w.Int("duration", m.Duration)
+	w.String("input", m.Input)
+	w.Int("wait_after", m.WaitAfter)
+	w.Int("timeout", m.Timeout)
+}
+
+func (m *GestureArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("ref"); ok { m.Ref = v }
+	if v, ok := r.String("gesture"); ok { m.Gesture = v }
+	if v, ok := r.String("direction"); ok { m.Direction = v }
+	if v, ok := r.Int("distance"); ok { m.Distance = v }
+	if v, ok := r.Float("angle"); ok { m.Angle = v }
+	if v, ok := r.Int("duration"); ok { m.Duration = v }
+	if v, ok := r.String("input"); ok { m.Input = v }
+	if v, ok := r.Int("wait_after"); ok { m.WaitAfter = v }
+	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
+}
+
+type GestureArgsList []*GestureArgs
+
+func (s *GestureArgsList) Schema() []model.Field { return nil }
+func (s *GestureArgsList) Pointers() []any     { return nil }
+func (s *GestureArgsList) Len() int             { return len(*s) }
+func (s *GestureArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *GestureArgsList) Append() model.Fielder  { v := &GestureArgs{}; *s = append(*s, v); return v }
+func (s *GestureArgsList) IsNil() bool          { return s == nil }
+func (s *GestureArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *GestureArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *GestureArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
 type SwipeElementArgs struct {
 	Selector string
 	Direction string
@@ -460,20 +920,35 @@ func (m *GetPerformanceArgs) Validate(action byte) error {
 }
 
 type GetContentArgs struct {
+	Format string
+	Selector string
+	MaxChars int64
+	Diff bool
+	Checkpoint string
 }
 
 func (m *GetContentArgs) ModelName() string { return "get_content_args" }
 
 func (m *GetContentArgs) Schema() []model.Field { return GetContentArgsModel.Fields }
 
-func (m *GetContentArgs) Pointers() []any { return []any{} }
+func (m *GetContentArgs) Pointers() []any { return []any{&m.Format, &m.Selector, &m.MaxChars, &m.Diff, &m.Checkpoint} }
 
 func (m *GetContentArgs) IsNil() bool { return m == nil }
 
 func (m *GetContentArgs) EncodeFields(w model.FieldWriter) {
+	w.String("format", m.Format)
+	w.String("selector", m.Selector)
+	w.Int("max_chars", m.MaxChars)
+	w.Bool("diff", m.Diff)
+	w.String("checkpoint", m.Checkpoint)
 }
 
 func (m *GetContentArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("format"); ok { m.Format = v }
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("max_chars"); ok { m.MaxChars = v }
+	if v, ok := r.Bool("diff"); ok { m.Diff = v }
+	if v, ok := r.String("checkpoint"); ok { m.Checkpoint = v }
 }
 
 type GetContentArgsList []*GetContentArgs
@@ -786,13 +1261,22 @@ type SaveScreenshotArgs struct {
 	Selector string
 	Fullpage bool
 	Overwrite bool
+	Format string
+	Quality int64
+	ClipX float64
+	ClipY float64
+	ClipWidth float64
+	ClipHeight float64
+	Scale float64
+	OmitBackground bool
+	Media string
 }
 
 func (m *SaveScreenshotArgs) ModelName() string { return "save_screenshot_args" }
 
 func (m *SaveScreenshotArgs) Schema() []model.Field { return SaveScreenshotArgsModel.Fields }
 
-func (m *SaveScreenshotArgs) Pointers() []any { return []any{&m.Dir, &m.Name, &m.Selector, &m.Fullpage, &m.Overwrite} }
+func (m *SaveScreenshotArgs) Pointers() []any { return []any{&m.Dir, &m.Name, &m.Selector, &m.Fullpage, &m.Overwrite, &m.Format, &m.Quality, &m.ClipX, &m.ClipY, &m.ClipWidth, &m.ClipHeight, &m.Scale, &m.OmitBackground, &m.Media} }
 
 func (m *SaveScreenshotArgs) IsNil() bool { return m == nil }
 
@@ -802,6 +1286,15 @@ func (m *SaveScreenshotArgs) EncodeFields(w model.FieldWriter) {
 	w.String("selector", m.Selector)
 	w.Bool("fullpage", m.Fullpage)
 	w.Bool("overwrite", m.Overwrite)
+	w.String("format", m.Format)
+	w.Int("quality", m.Quality)
+	w.Float("clip_x", m.ClipX)
+	w.Float("clip_y", m.ClipY)
+	w.Float("clip_width", m.ClipWidth)
+	w.Float("clip_height", m.ClipHeight)
+	w.Float("scale", m.Scale)
+	w.Bool("omit_background", m.OmitBackground)
+	w.String("media", m.Media)
 }
 
 func (m *SaveScreenshotArgs) DecodeFields(r model.FieldReader) {
@@ -810,6 +1303,15 @@ func (m *SaveScreenshotArgs) DecodeFields(r model.FieldReader) {
 	if v, ok := r.String("selector"); ok { m.Selector = v }
 	if v, ok := r.Bool("fullpage"); ok { m.Fullpage = v }
 	if v, ok := r.Bool("overwrite"); ok { m.Overwrite = v }
+	if v, ok := r.String("format"); ok { m.Format = v }
+	if v, ok := r.Int("quality"); ok { m.Quality = v }
+	if v, ok := r.Float("clip_x"); ok { m.ClipX = v }
+	if v, ok := r.Float("clip_y"); ok { m.ClipY = v }
+	if v, ok := r.Float("clip_width"); ok { m.ClipWidth = v }
+	if v, ok := r.Float("clip_height"); ok { m.ClipHeight = v }
+	if v, ok := r.Float("scale"); ok { m.Scale = v }
+	if v, ok := r.Bool("omit_background"); ok { m.OmitBackground = v }
+	if v, ok := r.String("media"); ok { m.Media = v }
 }
 
 type SaveScreenshotArgsList []*SaveScreenshotArgs
@@ -826,3 +1328,582 @@ func (s *SaveScreenshotArgsList) DecodeFields(_ model.FieldReader) {}
 func (m *SaveScreenshotArgs) Validate(action byte) error {
 	return model.ValidateFields(action, m)
 }
+
+type ScreenshotMatrixArgs struct {
+	Targets string
+	Fullpage bool
+	Dir string
+	Name string
+	ContactSheet bool
+	Overwrite bool
+	Media string
+}
+
+func (m *ScreenshotMatrixArgs) ModelName() string { return "screenshot_matrix_args" }
+
+func (m *ScreenshotMatrixArgs) Schema() []model.Field { return ScreenshotMatrixArgsModel.Fields }
+
+func (m *ScreenshotMatrixArgs) Pointers() []any { return []any{&m.Targets, &m.Fullpage, &m.Dir, &m.Name, &m.ContactSheet, &m.Overwrite, &m.Media} }
+
+func (m *ScreenshotMatrixArgs) IsNil() bool { return m == nil }
+
+func (m *ScreenshotMatrixArgs) EncodeFields(w model.FieldWriter) {
+	w.String("targets", m.Targets)
+	w.Bool("fullpage", m.Fullpage)
+	w.String("dir", m.Dir)
+	w.String("name", m.Name)
+	w.Bool("contact_sheet", m.ContactSheet)
+	w.Bool("overwrite", m.Overwrite)
+	w.String("media", m.Media)
+}
+
+func (m *ScreenshotMatrixArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("targets"); ok { m.Targets = v }
+	if v, ok := r.Bool("fullpage"); ok { m.Fullpage = v }
+	if v, ok := r.String("dir"); ok { m.Dir = v }
+	if v, ok := r.String("name"); ok { m.Name = v }
+	if v, ok := r.Bool("contact_sheet"); ok { m.ContactSheet = v }
+	if v, ok := r.Bool("overwrite"); ok { m.Overwrite = v }
+	if v, ok := r.String("media"); ok { m.Media = v }
+}
+
+type ScreenshotMatrixArgsList []*ScreenshotMatrixArgs
+
+func (s *ScreenshotMatrixArgsList) Schema() []model.Field { return nil }
+func (s *ScreenshotMatrixArgsList) Pointers() []any     { return nil }
+func (s *ScreenshotMatrixArgsList) Len() int             { return len(*s) }
+func (s *ScreenshotMatrixArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *ScreenshotMatrixArgsList) Append() model.Fielder  { v := &ScreenshotMatrixArgs{}; *s = append(*s, v); return v }
+func (s *ScreenshotMatrixArgsList) IsNil() bool          { return s == nil }
+func (s *ScreenshotMatrixArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *ScreenshotMatrixArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *ScreenshotMatrixArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type RecordStartArgs struct {
+	Fps int64
+	MaxDuration int64
+	Scale float64
+	Quality int64
+}
+
+func (m *RecordStartArgs) ModelName() string { return "record_start_args" }
+
+func (m *RecordStartArgs) Schema() []model.Field { return RecordStartArgsModel.Fields }
+
+func (m *RecordStartArgs) Pointers() []any { return []any{&m.Fps, &m.MaxDuration, &m.Scale, &m.Quality} }
+
+func (m *RecordStartArgs) IsNil() bool { return m == nil }
+
+func (m *RecordStartArgs) EncodeFields(w model.FieldWriter) {
+	w.Int("fps", m.Fps)
+	w.Int("max_duration", m.MaxDuration)
+	w.Float("scale", m.Scale)
+	w.Int("quality", m.Quality)
+}
+
+func (m *RecordStartArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.Int("fps"); ok { m.Fps = v }
+	if v, ok := r.Int("max_duration"); ok { m.MaxDuration = v }
+	if v, ok := r.Float("scale"); ok { m.Scale = v }
+	if v, ok := r.Int("quality"); ok { m.Quality = v }
+}
+
+type RecordStartArgsList []*RecordStartArgs
+
+func (s *RecordStartArgsList) Schema() []model.Field { return nil }
+func (s *RecordStartArgsList) Pointers() []any     { return nil }
+func (s *RecordStartArgsList) Len() int             { return len(*s) }
+func (s *RecordStartArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *RecordStartArgsList) Append() model.Fielder  { v := &RecordStartArgs{}; *s = append(*s, v); return v }
+func (s *RecordStartArgsList) IsNil() bool          { return s == nil }
+func (s *RecordStartArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *RecordStartArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *RecordStartArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type RecordStopArgs struct {
+	Dir string
+	Name string
+	Encode string
+	Overwrite bool
+}
+
+func (m *RecordStopArgs) ModelName() string { return "record_stop_args" }
+
+func (m *RecordStopArgs) Schema() []model.Field { return RecordStopArgsModel.Fields }
+
+func (m *RecordStopArgs) Pointers() []any { return []any{&m.Dir, &m.Name, &m.Encode, &m.Overwrite} }
+
+func (m *RecordStopArgs) IsNil() bool { return m == nil }
+
+func (m *RecordStopArgs) EncodeFields(w model.FieldWriter) {
+	w.String("dir", m.Dir)
+	w.String("name", m.Name)
+	w.String("encode", m.Encode)
+	w.Bool("overwrite", m.Overwrite)
+}
+
+func (m *RecordStopArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("dir"); ok { m.Dir = v }
+	if v, ok := r.String("name"); ok { m.Name = v }
+	if v, ok := r.String("encode"); ok { m.Encode = v }
+	if v, ok := r.Bool("overwrite"); ok { m.Overwrite = v }
+}
+
+type RecordStopArgsList []*RecordStopArgs
+
+func (s *RecordStopArgsList) Schema() []model.Field { return nil }
+func (s *RecordStopArgsList) Pointers() []any     { return nil }
+func (s *RecordStopArgsList) Len() int             { return len(*s) }
+func (s *RecordStopArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *RecordStopArgsList) Append() model.Fielder  { v := &RecordStopArgs{}; *s = append(*s, v); return v }
+func (s *RecordStopArgsList) IsNil() bool          { return s == nil }
+func (s *RecordStopArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *RecordStopArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *RecordStopArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type ScreenshotAnnotatedArgs struct {
+	Selectors string
+	Fullpage bool
+	Format string
+	Quality int64
+	Scale float64
+}
+
+func (m *ScreenshotAnnotatedArgs) ModelName() string { return "screenshot_annotated_args" }
+
+func (m *ScreenshotAnnotatedArgs) Schema() []model.Field { return ScreenshotAnnotatedArgsModel.Fields }
+
+func (m *ScreenshotAnnotatedArgs) Pointers() []any { return []any{&m.Selectors, &m.Fullpage, &m.Format, &m.Quality, &m.Scale} }
+
+func (m *ScreenshotAnnotatedArgs) IsNil() bool { return m == nil }
+
+func (m *ScreenshotAnnotatedArgs) EncodeFields(w model.FieldWriter) {
+	w.String("selectors", m.Selectors)
+	w.Bool("fullpage", m.Fullpage)
+	w.String("format", m.Format)
+	w.Int("quality", m.Quality)
+	w.Float("scale", m.Scale)
+}
+
+func (m *ScreenshotAnnotatedArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("selectors"); ok { m.Selectors = v }
+	if v, ok := r.Bool("fullpage"); ok { m.Fullpage = v }
+	if v, ok := r.String("format"); ok { m.Format = v }
+	if v, ok := r.Int("quality"); ok { m.Quality = v }
+	if v, ok := r.Float("scale"); ok { m.Scale = v }
+}
+
+type ScreenshotAnnotatedArgsList []*ScreenshotAnnotatedArgs
+
+func (s *ScreenshotAnnotatedArgsList) Schema() []model.Field { return nil }
+func (s *ScreenshotAnnotatedArgsList) Pointers() []any     { return nil }
+func (s *ScreenshotAnnotatedArgsList) Len() int             { return len(*s) }
+func (s *ScreenshotAnnotatedArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *ScreenshotAnnotatedArgsList) Append() model.Fielder  { v := &ScreenshotAnnotatedArgs{}; *s = append(*s, v); return v }
+func (s *ScreenshotAnnotatedArgsList) IsNil() bool          { return s == nil }
+func (s *ScreenshotAnnotatedArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *ScreenshotAnnotatedArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *ScreenshotAnnotatedArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type GetAccessibilityTreeArgs struct {
+	Selector string
+	All bool
+	MaxDepth int64
+}
+
+func (m *GetAccessibilityTreeArgs) ModelName() string { return "get_accessibility_tree_args" }
+
+func (m *GetAccessibilityTreeArgs) Schema() []model.Field { return GetAccessibilityTreeArgsModel.Fields }
+
+func (m *GetAccessibilityTreeArgs) Pointers() []any { return []any{&m.Selector, &m.All, &m.MaxDepth} }
+
+func (m *GetAccessibilityTreeArgs) IsNil() bool { return m == nil }
+
+func (m *GetAccessibilityTreeArgs) EncodeFields(w model.FieldWriter) {
+	w.String("selector", m.Selector)
+	w.Bool("all", m.All)
+	w.Int("max_depth", m.MaxDepth)
+}
+
+func (m *GetAccessibilityTreeArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Bool("all"); ok { m.All = v }
+	if v, ok := r.Int("max_depth"); ok { m.MaxDepth = v }
+}
+
+type GetAccessibilityTreeArgsList []*GetAccessibilityTreeArgs
+
+func (s *GetAccessibilityTreeArgsList) Schema() []model.Field { return nil }
+func (s *GetAccessibilityTreeArgsList) Pointers() []any     { return nil }
+func (s *GetAccessibilityTreeArgsList) Len() int             { return len(*s) }
+func (s *GetAccessibilityTreeArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *GetAccessibilityTreeArgsList) Append() model.Fielder  { v := &GetAccessibilityTreeArgs{}; *s = append(*s, v); return v }
+func (s *GetAccessibilityTreeArgsList) IsNil() bool          { return s == nil }
+func (s *GetAccessibilityTreeArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *GetAccessibilityTreeArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *GetAccessibilityTreeArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type WaitForArgs struct {
+	Selector string
+	Ref int64
+	State string
+	Text string
+	Url string
+	NetworkIdle int64
+	DomStable int64
+	Js string
+	Timeout int64
+}
+
+func (m *WaitForArgs) ModelName() string { return "wait_for_args" }
+
+func (m *WaitForArgs) Schema() []model.Field { return WaitForArgsModel.Fields }
+
+func (m *WaitForArgs) Pointers() []any { return []any{&m.Selector, &m.Ref, &m.State, &m.Text, &m.Url, &m.NetworkIdle, &m.DomStable, &m.Js, &m.Timeout} }
+
+func (m *WaitForArgs) IsNil() bool { return m == nil }
+
+func (m *WaitForArgs) EncodeFields(w model.FieldWriter) {
+	w.String("selector", m.Selector)
+	w.Int("ref", m.Ref)
+	w.String("state", m.State)
+	w.String("text", m.Text)
+	w.String("url", m.Url)
+	w.Int("network_idle", m.NetworkIdle)
+	w.Int("dom_stable", m.DomStable)
+	w.String("js", m.Js)
+	w.Int("timeout", m.Timeout)
+}
+
+func (m *WaitForArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("ref"); ok { m.Ref = v }
+	if v, ok := r.String("state"); ok { m.State = v }
+	if v, ok := r.String("text"); ok { m.Text = v }
+	if v, ok := r.String("url"); ok { m.Url = v }
+	if v, ok := r.Int("network_idle"); ok { m.NetworkIdle = v }
+	if v, ok := r.Int("dom_stable"); ok { m.DomStable = v }
+	if v, ok := r.String("js"); ok { m.Js = v }
+	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
+}
+
+type WaitForArgsList []*WaitForArgs
+
+func (s *WaitForArgsList) Schema() []model.Field { return nil }
+func (s *WaitForArgsList) Pointers() []any     { return nil }
+func (s *WaitForArgsList) Len() int             { return len(*s) }
+func (s *WaitForArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *WaitForArgsList) Append() model.Fielder  { v := &WaitForArgs{}; *s = append(*s, v); return v }
+func (s *WaitForArgsList) IsNil() bool          { return s == nil }
+func (s *WaitForArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *WaitForArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *WaitForArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type RunStepsArgs struct {
+	Steps string
+	Timeout int64
+}
+
+func (m *RunStepsArgs) ModelName() string { return "run_steps_args" }
+
+func (m *RunStepsArgs) Schema() []model.Field { return RunStepsArgsModel.Fields }
+
+func (m *RunStepsArgs) Pointers() []any { return []any{&m.Steps, &m.Timeout} }
+
+func (m *RunStepsArgs) IsNil() bool { return m == nil }
+
+func (m *RunStepsArgs) EncodeFields(w model.FieldWriter) {
+	w.String("steps", m.Steps)
+	w.Int("timeout", m.Timeout)
+}
+
+func (m *RunStepsArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("steps"); ok { m.Steps = v }
+	if v, ok := r.Int("timeout"); ok { m.Timeout = v }
+}
+
+type RunStepsArgsList []*RunStepsArgs
+
+func (s *RunStepsArgsList) Schema() []model.Field { return nil }
+func (s *RunStepsArgsList) Pointers() []any     { return nil }
+func (s *RunStepsArgsList) Len() int             { return len(*s) }
+func (s *RunStepsArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *RunStepsArgsList) Append() model.Fielder  { v := &RunStepsArgs{}; *s = append(*s, v); return v }
+func (s *RunStepsArgsList) IsNil() bool          { return s == nil }
+func (s *RunStepsArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *RunStepsArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *RunStepsArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type AssertArgs struct {
+	Type string
+	Selector string
+	Ref int64
+	Name string
+	Op string
+	Expected string
+	Not bool
+	Since string
+	Checkpoint string
+}
+
+func (m *AssertArgs) ModelName() string { return "assert_args" }
+
+func (m *AssertArgs) Schema() []model.Field { return AssertArgsModel.Fields }
+
+func (m *AssertArgs) Pointers() []any { return []any{&m.Type, &m.Selector, &m.Ref, &m.Name, &m.Op, &m.Expected, &m.Not, &m.Since, &m.Checkpoint} }
+
+func (m *AssertArgs) IsNil() bool { return m == nil }
+
+func (m *AssertArgs) EncodeFields(w model.FieldWriter) {
+	w.String("type", m.Type)
+	w.String("selector", m.Selector)
+	w.Int("ref", m.Ref)
+	w.String("name", m.Name)
+	w.String("op", m.Op)
+	w.String("expected", m.Expected)
+	w.Bool("not", m.Not)
+	w.String("since", m.Since)
+	w.String("checkpoint", m.Checkpoint)
+}
+
+func (m *AssertArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("type"); ok { m.Type = v }
+	if v, ok := r.String("selector"); ok { m.Selector = v }
+	if v, ok := r.Int("ref"); ok { m.Ref = v }
+	if v, ok := r.String("name"); ok { m.Name = v }
+	if v, ok := r.String("op"); ok { m.Op = v }
+	if v, ok := r.String("expected"); ok { m.Expected = v }
+	if v, ok := r.Bool("not"); ok { m.Not = v }
+	if v, ok := r.String("since"); ok { m.Since = v }
+	if v, ok := r.String("checkpoint"); ok { m.Checkpoint = v }
+}
+
+type AssertArgsList []*AssertArgs
+
+func (s *AssertArgsList) Schema() []model.Field { return nil }
+func (s *AssertArgsList) Pointers() []any     { return nil }
+func (s *AssertArgsList) Len() int             { return len(*s) }
+func (s *AssertArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *AssertArgsList) Append() model.Fielder  { v := &AssertArgs{}; *s = append(*s, v); return v }
+func (s *AssertArgsList) IsNil() bool          { return s == nil }
+func (s *AssertArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *AssertArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *AssertArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type HandleDialogArgs struct {
+	Action string
+	PromptText string
+	Policy string
+}
+
+func (m *HandleDialogArgs) ModelName() string { return "handle_dialog_args" }
+
+func (m *HandleDialogArgs) Schema() []model.Field { return HandleDialogArgsModel.Fields }
+
+func (m *HandleDialogArgs) Pointers() []any { return []any{&m.Action, &m.PromptText, &m.Policy} }
+
+func (m *HandleDialogArgs) IsNil() bool { return m == nil }
+
+func (m *HandleDialogArgs) EncodeFields(w model.FieldWriter) {
+	w.String("action", m.Action)
+	w.String("prompt_text", m.PromptText)
+	w.String("policy", m.Policy)
+}
+
+func (m *HandleDialogArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("action"); ok { m.Action = v }
+	if v, ok := r.String("prompt_text"); ok { m.PromptText = v }
+	if v, ok := r.String("policy"); ok { m.Policy = v }
+}
+
+type HandleDialogArgsList []*HandleDialogArgs
+
+func (s *HandleDialogArgsList) Schema() []model.Field { return nil }
+func (s *HandleDialogArgsList) Pointers() []any     { return nil }
+func (s *HandleDialogArgsList) Len() int             { return len(*s) }
+func (s *HandleDialogArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *HandleDialogArgsList) Append() model.Fielder  { v := &HandleDialogArgs{}; *s = append(*s, v); return v }
+func (s *HandleDialogArgsList) IsNil() bool          { return s == nil }
+func (s *HandleDialogArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *HandleDialogArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *HandleDialogArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type ReloadAssetsArgs struct {
+	Kind string
+	Url string
+}
+
+func (m *ReloadAssetsArgs) ModelName() string { return "reload_assets_args" }
+
+func (m *ReloadAssetsArgs) Schema() []model.Field { return ReloadAssetsArgsModel.Fields }
+
+func (m *ReloadAssetsArgs) Pointers() []any { return []any{&m.Kind, &m.Url} }
+
+func (m *ReloadAssetsArgs) IsNil() bool { return m == nil }
+
+func (m *ReloadAssetsArgs) EncodeFields(w model.FieldWriter) {
+	w.String("kind", m.Kind)
+	w.String("url", m.Url)
+}
+
+func (m *ReloadAssetsArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("kind"); ok { m.Kind = v }
+	if v, ok := r.String("url"); ok { m.Url = v }
+}
+
+type ReloadAssetsArgsList []*ReloadAssetsArgs
+
+func (s *ReloadAssetsArgsList) Schema() []model.Field { return nil }
+func (s *ReloadAssetsArgsList) Pointers() []any     { return nil }
+func (s *ReloadAssetsArgsList) Len() int             { return len(*s) }
+func (s *ReloadAssetsArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *ReloadAssetsArgsList) Append() model.Fielder  { v := &ReloadAssetsArgs{}; *s = append(*s, v); return v }
+func (s *ReloadAssetsArgsList) IsNil() bool          { return s == nil }
+func (s *ReloadAssetsArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *ReloadAssetsArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *ReloadAssetsArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type EmulateEnvironmentArgs struct {
+	Geolocation string
+	Timezone string
+	Locale string
+	AcceptLanguage string
+	UserAgent string
+	UaPlatform string
+	UaPlatformVersion string
+	UaModel string
+	UaMobile bool
+	Reset bool
+}
+
+func (m *EmulateEnvironmentArgs) ModelName() string { return "emulate_environment_args" }
+
+func (m *EmulateEnvironmentArgs) Schema() []model.Field { return EmulateEnvironmentArgsModel.Fields }
+
+func (m *EmulateEnvironmentArgs) Pointers() []any { return []any{&m.Geolocation, &m.Timezone, &m.Locale, &m.AcceptLanguage, &m.UserAgent, &m.UaPlatform, &m.UaPlatformVersion, &m.UaModel, &m.UaMobile, &m.Reset} }
+
+func (m *EmulateEnvironmentArgs) IsNil() bool { return m == nil }
+
+func (m *EmulateEnvironmentArgs) EncodeFields(w model.FieldWriter) {
+	w.String("geolocation", m.Geolocation)
+	w.String("timezone", m.Timezone)
+	w.String("locale", m.Locale)
+	w.String("accept_language", m.AcceptLanguage)
+	w.String("user_agent", m.UserAgent)
+	w.String("ua_platform", m.UaPlatform)
+	w.String("ua_platform_version", m.UaPlatformVersion)
+	w.String("ua_model", m.UaModel)
+	w.Bool("ua_mobile", m.UaMobile)
+	w.Bool("reset", m.Reset)
+}
+
+func (m *EmulateEnvironmentArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("geolocation"); ok { m.Geolocation = v }
+	if v, ok := r.String("timezone"); ok { m.Timezone = v }
+	if v, ok := r.String("locale"); ok { m.Locale = v }
+	if v, ok := r.String("accept_language"); ok { m.AcceptLanguage = v }
+	if v, ok := r.String("user_agent"); ok { m.UserAgent = v }
+	if v, ok := r.String("ua_platform"); ok { m.UaPlatform = v }
+	if v, ok := r.String("ua_platform_version"); ok { m.UaPlatformVersion = v }
+	if v, ok := r.String("ua_model"); ok { m.UaModel = v }
+	if v, ok := r.Bool("ua_mobile"); ok { m.UaMobile = v }
+	if v, ok := r.Bool("reset"); ok { m.Reset = v }
+}
+
+type EmulateEnvironmentArgsList []*EmulateEnvironmentArgs
+
+func (s *EmulateEnvironmentArgsList) Schema() []model.Field { return nil }
+func (s *EmulateEnvironmentArgsList) Pointers() []any     { return nil }
+func (s *EmulateEnvironmentArgsList) Len() int             { return len(*s) }
+func (s *EmulateEnvironmentArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *EmulateEnvironmentArgsList) Append() model.Fielder  { v := &EmulateEnvironmentArgs{}; *s = append(*s, v); return v }
+func (s *EmulateEnvironmentArgsList) IsNil() bool          { return s == nil }
+func (s *EmulateEnvironmentArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *EmulateEnvironmentArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *EmulateEnvironmentArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
+
+type EmulateMediaArgs struct {
+	ColorScheme string
+	ReducedMotion string
+	Contrast string
+	ForcedColors string
+	MediaType string
+	Vision string
+	Reset bool
+}
+
+func (m *EmulateMediaArgs) ModelName() string { return "emulate_media_args" }
+
+func (m *EmulateMediaArgs) Schema() []model.Field { return EmulateMediaArgsModel.Fields }
+
+func (m *EmulateMediaArgs) Pointers() []any { return []any{&m.ColorScheme, &m.ReducedMotion, &m.Contrast, &m.ForcedColors, &m.MediaType, &m.Vision, &m.Reset} }
+
+func (m *EmulateMediaArgs) IsNil() bool { return m == nil }
+
+func (m *EmulateMediaArgs) EncodeFields(w model.FieldWriter) {
+	w.String("color_scheme", m.ColorScheme)
+	w.String("reduced_motion", m.ReducedMotion)
+	w.String("contrast", m.Contrast)
+	w.String("forced_colors", m.ForcedColors)
+	w.String("media_type", m.MediaType)
+	w.String("vision", m.Vision)
+	w.Bool("reset", m.Reset)
+}
+
+func (m *EmulateMediaArgs) DecodeFields(r model.FieldReader) {
+	if v, ok := r.String("color_scheme"); ok { m.ColorScheme = v }
+	if v, ok := r.String("reduced_motion"); ok { m.ReducedMotion = v }
+	if v, ok := r.String("contrast"); ok { m.Contrast = v }
+	if v, ok := r.String("forced_colors"); ok { m.ForcedColors = v }
+	if v, ok := r.String("media_type"); ok { m.MediaType = v }
+	if v, ok := r.String("vision"); ok { m.Vision = v }
+	if v, ok := r.Bool("reset"); ok { m.Reset = v }
+}
+
+type EmulateMediaArgsList []*EmulateMediaArgs
+
+func (s *EmulateMediaArgsList) Schema() []model.Field { return nil }
+func (s *EmulateMediaArgsList) Pointers() []any     { return nil }
+func (s *EmulateMediaArgsList) Len() int             { return len(*s) }
+func (s *EmulateMediaArgsList) At(i int) model.Fielder { return (*s)[i] }
+func (s *EmulateMediaArgsList) Append() model.Fielder  { v := &EmulateMediaArgs{}; *s = append(*s, v); return v }
+func (s *EmulateMediaArgsList) IsNil() bool          { return s == nil }
+func (s *EmulateMediaArgsList) EncodeFields(_ model.FieldWriter) {}
+func (s *EmulateMediaArgsList) DecodeFields(_ model.FieldReader) {}
+
+func (m *EmulateMediaArgs) Validate(action byte) error {
+	return model.ValidateFields(action, m)
+}
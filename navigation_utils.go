@@ -0,0 +1,377 @@
+package devbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tinywasm/devbrowser/cdproto/cdp"
+	"github.com/tinywasm/devbrowser/cdproto/network"
+	"github.com/tinywasm/devbrowser/cdproto/page"
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// navDefaultTimeout is the timeout of a Navigation that sets none.
+const navDefaultTimeout = 30 * time.Second
+
+// navIdleTime is how long the network must be quiet for network_idle.
+const navIdleTime = 500 * time.Millisecond
+
+// Navigation is a page navigation: to URL, or an Action on the history, and
+// what to wait for before it counts as done.
+type Navigation struct {
+	URL       string        // Absolute URL to load
+	Action    string        // Instead of URL: back, forward, reload or hard_reload (ignores the cache)
+	WaitUntil string        // load (default), domcontentloaded, network_idle or selector
+	Selector  string        // Element to wait for to be visible; implies WaitUntil selector
+	Timeout   time.Duration // Default 30s
+}
+
+// Redirect is a hop of a redirect chain: the URL that answered with Status.
+type Redirect struct {
+	Status int
+	URL    string
+}
+
+// NavigationResult is where a navigation ended and how it got there.
+type NavigationResult struct {
+	URL        string // Final URL
+	Status     int    // Of the main document response; 0 if the page did not load one
+	StatusText string
+	Redirects  []Redirect // In order, before the final response
+	FromCache  bool       // The document came from the disk cache
+	SameDoc    bool       // The URL changed without loading a document (hash or history state)
+	Waited     string     // What was waited for
+	Duration   time.Duration
+}
+
+// String formats the result for the tool output.
+func (r NavigationResult) String() string {
+	var s string
+	switch {
+	case r.SameDoc:
+		s = fmt.Sprintf("%s (same document", r.URL)
+	case r.Status == 0:
+		s = fmt.Sprintf("%s (no response", r.URL)
+	default:
+		s = fmt.Sprintf("%s (%d", r.URL, r.Status)
+		if r.StatusText != "" {
+			s += " " + r.StatusText
+		}
+		if r.FromCache {
+			s += ", from cache"
+		}
+	}
+	s += fmt.Sprintf(", %dms, waited for %s)", r.Duration.Milliseconds(), r.Waited)
+	if len(r.Redirects) > 0 {
+		hops := make([]string, len(r.Redirects))
+		for i, h := range r.Redirects {
+			hops[i] = fmt.Sprintf("%d %s", h.Status, h.URL)
+		}
+		s += "\nRedirects: " + strings.Join(hops, " -> ") + " -> " + r.URL
+	}
+	return s
+}
+
+// String describes the navigation for errors: "navigating to URL", "going
+// back" or "hard reloading".
+func (n Navigation) String() string {
+	switch n.Action {
+	case "back", "forward":
+		return "going " + n.Action
+	case "reload":
+		return "reloading"
+	case "hard_reload":
+		return "hard reloading"
+	}
+	return "navigating to " + n.URL
+}
+
+func (n Navigation) validate() error {
+	switch n.Action {
+	case "":
+		if n.URL == "" {
+			return errors.New("url or action required")
+		}
+	case "back", "forward", "reload", "hard_reload":
+		if n.URL != "" {
+			return fmt.Errorf("%s takes no url", n.Action)
+		}
+	default:
+		return fmt.Errorf("unsupported action: %s. Use back, forward, reload or hard_reload", n.Action)
+	}
+	switch n.WaitUntil {
+	case "", "load", "domcontentloaded", "network_idle":
+	case "selector":
+		if n.Selector == "" {
+			return errors.New("wait_until selector needs a selector")
+		}
+	default:
+		return fmt.Errorf("unsupported wait_until: %s. Use load, domcontentloaded, network_idle or selector", n.WaitUntil)
+	}
+	if n.Selector != "" && n.WaitUntil != "" && n.WaitUntil != "selector" {
+		return fmt.Errorf("selector waits for the element; it cannot be combined with wait_until %s", n.WaitUntil)
+	}
+	if n.Timeout < 0 {
+		return errors.New("timeout must be positive")
+	}
+	return nil
+}
+
+// waitUntil is what the navigation waits for, with the default applied.
+func (n Navigation) waitUntil() string {
+	switch {
+	case n.WaitUntil != "":
+		return n.WaitUntil
+	case n.Selector != "":
+		return "selector"
+	}
+	return "load"
+}
+
+// navTracker follows the main frame through a navigation: its document
+// request with the redirects, and the load events of the new document.
+type navTracker struct {
+	frame cdp.FrameID
+
+	mu        sync.Mutex
+	started   bool // The navigation committed or sent its document request
+	request   network.RequestID
+	redirects []Redirect
+	response  *network.Response
+	failure   string
+
+	domReady chan struct{} // DOMContentLoaded, or a navigation that loads no document
+	loaded   chan struct{} // load, or a navigation that loads no document
+	failed   chan struct{}
+	sameDoc  bool
+	sameURL  string
+}
+
+func newNavTracker(frame cdp.FrameID) *navTracker {
+	return &navTracker{
+		frame:    frame,
+		domReady: make(chan struct{}),
+		loaded:   make(chan struct{}),
+		failed:   make(chan struct{}),
+	}
+}
+
+// closeOnce closes ch once. The caller holds mu.
+func closeOnce(ch chan struct{}) {
+	select {
+	case <-ch:
+	default:
+		close(ch)
+	}
+}
+
+func (t *navTracker) handle(ev interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch ev := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		if ev.Type != network.ResourceTypeDocument || ev.FrameID != t.frame {
+			return
+		}
+		if ev.RedirectResponse != nil && ev.RequestID == t.request {
+			t.redirects = append(t.redirects, Redirect{Status: int(ev.RedirectResponse.Status), URL: ev.RedirectResponse.URL})
+			return
+		}
+		// A new document request replaces the one before, e.g. after
+		// a client side redirect.
+		t.started = true
+		t.request = ev.RequestID
+		t.redirects = nil
+		t.response = nil
+
+	case *network.EventResponseReceived:
+		if ev.RequestID == t.request {
+			t.response = ev.Response
+		}
+
+	case *network.EventLoadingFailed:
+		if ev.RequestID != t.request {
+			return
+		}
+		// A cancelled document (a download, a 204) leaves the page
+		// as it was, with no load to wait for.
+		if ev.Canceled {
+			closeOnce(t.domReady)
+			closeOnce(t.loaded)
+			return
+		}
+		t.failure = ev.ErrorText
+		closeOnce(t.failed)
+
+	case *page.EventFrameNavigated:
+		if ev.Frame.ID != t.frame {
+			return
+		}
+		t.started = true
+		// Pages restored from the back/forward cache fire no load events.
+		if ev.Type == page.NavigationTypeBackForwardCacheRestore {
+			closeOnce(t.domReady)
+			closeOnce(t.loaded)
+		}
+
+	case *page.EventNavigatedWithinDocument:
+		if ev.FrameID == t.frame {
+			t.sameDoc = true
+			t.sameURL = ev.URL
+			closeOnce(t.domReady)
+			closeOnce(t.loaded)
+		}
+
+	case *page.EventDomContentEventFired:
+		if t.started {
+			closeOnce(t.domReady)
+		}
+
+	case *page.EventLoadEventFired:
+		if t.started {
+			closeOnce(t.domReady)
+			closeOnce(t.loaded)
+		}
+	}
+}
+
+// Navigate loads n.URL, or goes back, forward or reloads, and waits for the
+// page as n.WaitUntil says. The result has the final URL and status and the
+// redirect chain of the main document, taken from the network events.
+func (b *DevBrowser) Navigate(n Navigation) (NavigationResult, error) {
+	if b.Ctx == nil {
+		return NavigationResult{}, errors.New("context not initialized")
+	}
+	if err := n.validate(); err != nil {
+		return NavigationResult{}, err
+	}
+	if err := b.checkSelector(n.Selector); err != nil {
+		return NavigationResult{}, err
+	}
+	timeout := n.Timeout
+	if timeout == 0 {
+		timeout = navDefaultTimeout
+	}
+	waitUntil := n.waitUntil()
+
+	ctx, cancel := context.WithTimeout(b.Ctx, timeout)
+	defer cancel()
+	start := time.Now()
+
+	var tree *page.FrameTree
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		tree, err = page.GetFrameTree().Do(ctx)
+		return err
+	})); err != nil {
+		return NavigationResult{}, err
+	}
+	t := newNavTracker(tree.Frame.ID)
+	lctx, lcancel := context.WithCancel(ctx)
+	defer lcancel()
+	chromedp.ListenTarget(lctx, t.handle)
+
+	if err := chromedp.Run(ctx, n.action(t)); err != nil {
+		return NavigationResult{}, err
+	}
+
+	what := "load"
+	ready := t.loaded
+	if waitUntil == "domcontentloaded" || waitUntil == "selector" {
+		what = "DOMContentLoaded"
+		ready = t.domReady
+	}
+	select {
+	case <-ready:
+	case <-t.failed:
+		t.mu.Lock()
+		failure := t.failure
+		t.mu.Unlock()
+		return NavigationResult{}, fmt.Errorf("page load error %s", failure)
+	case <-ctx.Done():
+		return NavigationResult{}, fmt.Errorf("timed out after %dms waiting for %s", timeout.Milliseconds(), what)
+	}
+
+	waited := what
+	switch waitUntil {
+	case "network_idle":
+		rest := timeout - time.Since(start)
+		if _, err := b.WaitFor(WaitCondition{NetworkIdle: navIdleTime, Timeout: max(rest, time.Millisecond)}); err != nil {
+			return NavigationResult{}, err
+		}
+		waited = fmt.Sprintf("load and network idle for %dms", navIdleTime.Milliseconds())
+	case "selector":
+		rest := timeout - time.Since(start)
+		if _, err := b.WaitFor(WaitCondition{Selector: n.Selector, Timeout: max(rest, time.Millisecond)}); err != nil {
+			return NavigationResult{}, err
+		}
+		waited = n.Selector + " visible"
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	res := NavigationResult{
+		Redirects: t.redirects,
+		SameDoc:   t.sameDoc,
+		Waited:    waited,
+		Duration:  time.Since(start),
+	}
+	if t.response != nil {
+		res.URL = t.response.URL
+		res.Status = int(t.response.Status)
+		res.StatusText = t.response.StatusText
+		res.FromCache = t.response.FromDiskCache
+	}
+	if current, err := b.CurrentURL(); err == nil {
+		res.URL = current
+	} else if t.sameDoc {
+		res.URL = t.sameURL
+	}
+	return res, nil
+}
+
+// action starts the navigation without waiting for it.
+func (n Navigation) action(t *navTracker) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		switch n.Action {
+		case "reload", "hard_reload":
+			return page.Reload().WithIgnoreCache(n.Action == "hard_reload").Do(ctx)
+
+		case "back", "forward":
+			cur, entries, err := page.GetNavigationHistory().Do(ctx)
+			if err != nil {
+				return err
+			}
+			i := cur - 1
+			if n.Action == "forward" {
+				i = cur + 1
+			}
+			if i < 0 || i >= int64(len(entries)) {
+				return fmt.Errorf("no page to go %s to in the history", n.Action)
+			}
+			return page.NavigateToHistoryEntry(entries[i].ID).Do(ctx)
+		}
+
+		_, loaderID, errorText, err := page.Navigate(n.URL).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if errorText != "" {
+			return fmt.Errorf("page load error %s", errorText)
+		}
+		// No loader means the URL only changed its fragment.
+		if loaderID == "" {
+			t.mu.Lock()
+			t.sameDoc = true
+			t.sameURL = n.URL
+			closeOnce(t.domReady)
+			closeOnce(t.loaded)
+			t.mu.Unlock()
+		}
+		return nil
+	})
+}
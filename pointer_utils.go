@@ -0,0 +1,205 @@
+package devbrowser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tinywasm/devbrowser/cdproto/input"
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// pointerSteps is how many mousemove events a pointer path is split into by
+// default, so hover, pointermove and drag handlers see motion, not a jump.
+const pointerSteps = 10
+
+// pointerCleanupTimeout bounds the release of a button, or the end of drag
+// interception, after a pointer action failed halfway.
+const pointerCleanupTimeout = time.Second
+
+// dragInterceptWait is how long a drag waits, after its last move, for
+// Chrome to report an HTML5 drag it started.
+const dragInterceptWait = 100 * time.Millisecond
+
+// mouseButton is a CDP mouse button and its bit in MouseEvent.buttons.
+type mouseButton struct {
+	name input.MouseButton
+	bit  int64
+}
+
+var mouseButtons = map[string]mouseButton{
+	"left":   {input.Left, 1},
+	"right":  {input.Right, 2},
+	"middle": {input.Middle, 4},
+}
+
+// parseMouseButton accepts left (the default), right or middle.
+func parseMouseButton(name string) (mouseButton, error) {
+	if name == "" {
+		name = "left"
+	}
+	btn, ok := mouseButtons[strings.ToLower(name)]
+	if !ok {
+		return mouseButton{}, fmt.Errorf("unsupported button: %s. Use left, right or middle", name)
+	}
+	return btn, nil
+}
+
+// elementPoint waits for the element of a selector argument, scrolls it into
+// view and returns its center in page viewport coordinates.
+func (b *DevBrowser) elementPoint(ctx context.Context, selector string) (float64, float64, error) {
+	if !isDeepSelector(selector) {
+		if err := chromedp.Run(ctx, chromedp.WaitVisible(selector, chromedp.ByQuery)); err != nil {
+			return 0, 0, err
+		}
+	}
+	el, err := b.resolveElement(ctx, selector)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer el.release()
+	return b.elementCenter(ctx, el)
+}
+
+// mouseMove moves the mouse from where the pointer tools last left it to
+// (x, y) in steps mousemove events along a straight line, holding held
+// (zero value: no button).
+func (b *DevBrowser) mouseMove(ctx context.Context, x, y float64, steps int, held mouseButton) error {
+	b.Mu.Lock()
+	fromX, fromY := b.mouseX, b.mouseY
+	b.Mu.Unlock()
+
+	steps = max(steps, 1)
+	for i := 1; i <= steps; i++ {
+		px := fromX + (x-fromX)*float64(i)/float64(steps)
+		py := fromY + (y-fromY)*float64(i)/float64(steps)
+		p := input.DispatchMouseEvent(input.MouseMoved, px, py)
+		if held.bit != 0 {
+			p = p.WithButton(held.name).WithButtons(held.bit)
+		}
+		if err := p.Do(ctx); err != nil {
+			return err
+		}
+		b.Mu.Lock()
+		b.mouseX, b.mouseY = px, py
+		b.Mu.Unlock()
+	}
+	return nil
+}
+
+// cleanupContext runs the cleanup of a pointer action on ctx even when ctx
+// has expired or was cancelled, which is when the action failed.
+func cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), pointerCleanupTimeout)
+}
+
+// releaseMouse releases btn at (x, y) after a pointer action failed with the
+// button down, so it is not held for the next tool call.
+func releaseMouse(ctx context.Context, x, y float64, btn mouseButton) {
+	cctx, cancel := cleanupContext(ctx)
+	defer cancel()
+	input.DispatchMouseEvent(input.MouseReleased, x, y).WithButton(btn.name).WithClickCount(1).Do(cctx)
+}
+
+// mouseClick presses and releases btn at (x, y) count times with growing
+// clickCount, as a real double click does, keeping it down for hold.
+func mouseClick(ctx context.Context, x, y float64, btn mouseButton, count int, hold time.Duration) error {
+	pressed := false
+	defer func() {
+		if pressed {
+			releaseMouse(ctx, x, y, btn)
+		}
+	}()
+	for i := 1; i <= count; i++ {
+		if err := input.DispatchMouseEvent(input.MousePressed, x, y).
+			WithButton(btn.name).WithButtons(btn.bit).WithClickCount(int64(i)).Do(ctx); err != nil {
+			return err
+		}
+		pressed = true
+		if hold > 0 {
+			if err := chromedp.Sleep(hold).Do(ctx); err != nil {
+				return err
+			}
+		}
+		if err := input.DispatchMouseEvent(input.MouseReleased, x, y).
+			WithButton(btn.name).WithClickCount(int64(i)).Do(ctx); err != nil {
+			return err
+		}
+		pressed = false
+	}
+	return nil
+}
+
+// mouseDrag presses the left button where the mouse is, keeps it down for
+// hold, moves to (x, y) in steps and releases it there. A drag that Chrome
+// turns into an HTML5 drag (draggable elements, links, images) would go to
+// the OS, which synthetic input never ends; it is intercepted instead and
+// finished with dragenter, dragover and drop at (x, y). It reports whether
+// the drag was an HTML5 one.
+func (b *DevBrowser) mouseDrag(ctx context.Context, x, y float64, steps int, hold time.Duration) (bool, error) {
+	if err := input.SetInterceptDrags(true).Do(ctx); err != nil {
+		return false, err
+	}
+	defer func() {
+		cctx, cancel := cleanupContext(ctx)
+		defer cancel()
+		input.SetInterceptDrags(false).Do(cctx)
+	}()
+
+	lctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	intercepted := make(chan *input.DragData, 1)
+	chromedp.ListenTarget(lctx, func(ev any) {
+		if e, ok := ev.(*input.EventDragIntercepted); ok {
+			select {
+			case intercepted <- e.Data:
+			default:
+			}
+		}
+	})
+
+	b.Mu.Lock()
+	fromX, fromY := b.mouseX, b.mouseY
+	b.Mu.Unlock()
+	left := mouseButtons["left"]
+	if err := input.DispatchMouseEvent(input.MousePressed, fromX, fromY).
+		WithButton(left.name).WithButtons(left.bit).WithClickCount(1).Do(ctx); err != nil {
+		return false, err
+	}
+	released := false
+	defer func() {
+		if !released {
+			b.Mu.Lock()
+			atX, atY := b.mouseX, b.mouseY
+			b.Mu.Unlock()
+			releaseMouse(ctx, atX, atY, left)
+		}
+	}()
+	if hold > 0 {
+		if err := chromedp.Sleep(hold).Do(ctx); err != nil {
+			return false, err
+		}
+	}
+	if err := b.mouseMove(ctx, x, y, steps, left); err != nil {
+		return false, err
+	}
+
+	var data *input.DragData
+	select {
+	case data = <-intercepted:
+	case <-time.After(dragInterceptWait):
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+	if data != nil {
+		for _, t := range []input.DispatchDragEventType{input.DragEnter, input.DragOver, input.Drop} {
+			if err := input.DispatchDragEvent(t, x, y, data).Do(ctx); err != nil {
+				return true, err
+			}
+		}
+	}
+	err := input.DispatchMouseEvent(input.MouseReleased, x, y).WithButton(left.name).WithClickCount(1).Do(ctx)
+	released = err == nil
+	return data != nil, err
+}
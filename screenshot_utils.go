@@ -1,14 +1,28 @@
 package devbrowser
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"strings"
 
+	"github.com/tinywasm/devbrowser/cdproto/cdp"
+	"github.com/tinywasm/devbrowser/cdproto/dom"
+	"github.com/tinywasm/devbrowser/cdproto/emulation"
+	"github.com/tinywasm/devbrowser/cdproto/page"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
 	"github.com/tinywasm/devbrowser/chromedp"
 )
 
+// waitForPaintJS resolves after the next two animation frames, so a capture
+// taken right after an emulation or DOM change shows the re-laid-out page.
+// The timeout keeps it from hanging when rAF is throttled (hidden window).
+const waitForPaintJS = `new Promise(r => { requestAnimationFrame(() => requestAnimationFrame(r)); setTimeout(r, 500); })`
+
 // ScreenshotResult contains the image data and metadata about the captured page.
 type ScreenshotResult struct {
 	ImageData     []byte
+	MimeType      string // "image/png", "image/jpeg" or "image/webp"
 	PageTitle     string
 	PageURL       string
 	Width         int
@@ -16,42 +30,237 @@ type ScreenshotResult struct {
 	HTMLStructure string
 }
 
-// CaptureScreenshot captures a screenshot of the current page.
+// ScreenshotOptions tunes CaptureScreenshotWithOptions. The zero value
+// captures the viewport as PNG, like CaptureScreenshot(false).
+type ScreenshotOptions struct {
+	Fullpage bool
+	Selector string // Capture only this element; excludes Fullpage and the clip
+
+	Format  string // "png" (default), "jpeg" or "webp"
+	Quality int    // 1-100 for jpeg/webp, default 80; ignored for png
+
+	// Clip rectangle in CSS pixels, relative to the viewport (or to the
+	// document with Fullpage). Unused when ClipWidth and ClipHeight are 0.
+	ClipX, ClipY, ClipWidth, ClipHeight float64
+
+	Scale          float64 // Output scale factor (0.1-4), default 1; 0.5 halves each side
+	OmitBackground bool    // Transparent page background; png and webp only
+
+	// Media for this capture only, on top of the emulated one: a spec like
+	// "dark" or "print+reduced-motion" (see ParseMedia).
+	Media string
+}
+
+// screenshotFormats maps accepted format names to their canonical name.
+var screenshotFormats = map[string]string{
+	"":     "png",
+	"png":  "png",
+	"jpeg": "jpeg",
+	"jpg":  "jpeg",
+	"webp": "webp",
+}
+
+// Validate normalizes Format, Quality and Scale in place and rejects
+// conflicting or out-of-range options.
+func (o *ScreenshotOptions) Validate() error {
+	format, ok := screenshotFormats[strings.ToLower(o.Format)]
+	if !ok {
+		return fmt.Errorf("unsupported format: %s. Use png, jpeg or webp", o.Format)
+	}
+	o.Format = format
+
+	if o.Format == "png" {
+		o.Quality = 0
+	} else if o.Quality == 0 {
+		o.Quality = 80
+	} else if o.Quality < 1 || o.Quality > 100 {
+		return fmt.Errorf("quality must be between 1 and 100, got %d", o.Quality)
+	}
+
+	if o.Scale == 0 {
+		o.Scale = 1
+	} else if o.Scale < 0.1 || o.Scale > 4 {
+		return fmt.Errorf("scale must be between 0.1 and 4, got %g", o.Scale)
+	}
+
+	hasClip := o.ClipWidth != 0 || o.ClipHeight != 0
+	if hasClip && (o.ClipWidth <= 0 || o.ClipHeight <= 0) {
+		return fmt.Errorf("clip_width and clip_height must both be positive")
+	}
+	if o.Selector != "" && (o.Fullpage || hasClip) {
+		return fmt.Errorf("selector cannot be combined with fullpage or clip")
+	}
+	if o.OmitBackground && o.Format == "jpeg" {
+		return fmt.Errorf("omit_background needs png or webp: jpeg has no transparency")
+	}
+	if o.Media != "" {
+		if _, err := ParseMedia(o.Media); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Extension returns the file extension for the format, including the dot.
+func (o ScreenshotOptions) Extension() string {
+	switch o.Format {
+	case "jpeg", "jpg":
+		return ".jpg"
+	case "webp":
+		return ".webp"
+	}
+	return ".png"
+}
+
+// MimeType returns the MIME type for the format.
+func (o ScreenshotOptions) MimeType() string {
+	switch o.Format {
+	case "jpeg", "jpg":
+		return "image/jpeg"
+	case "webp":
+		return "image/webp"
+	}
+	return "image/png"
+}
+
+// ScreenshotFormatFromExt returns the format for an image file extension
+// (".png", ".jpg", ".jpeg", ".webp"), or "" when it is not one of them.
+func ScreenshotFormatFromExt(ext string) string {
+	switch strings.ToLower(ext) {
+	case ".png":
+		return "png"
+	case ".jpg", ".jpeg":
+		return "jpeg"
+	case ".webp":
+		return "webp"
+	}
+	return ""
+}
+
+// CaptureScreenshot captures a screenshot of the current page as PNG.
 func (b *DevBrowser) CaptureScreenshot(fullpage bool) (*ScreenshotResult, error) {
+	return b.CaptureScreenshotWithOptions(ScreenshotOptions{Fullpage: fullpage})
+}
+
+// CaptureElementScreenshot captures a PNG screenshot of a specific element.
+func (b *DevBrowser) CaptureElementScreenshot(selector string) (*ScreenshotResult, error) {
+	return b.CaptureScreenshotWithOptions(ScreenshotOptions{Selector: selector})
+}
+
+// CaptureScreenshotWithOptions captures the viewport, the full page, an
+// element or a clip rectangle in the requested format and scale.
+func (b *DevBrowser) CaptureScreenshotWithOptions(opts ScreenshotOptions) (*ScreenshotResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
 	if !b.IsOpenFlag || b.Ctx == nil {
 		return nil, fmt.Errorf("browser is not open")
 	}
 
 	var buf []byte
-	var err error
+	var actions []chromedp.Action
 
-	if fullpage {
-		err = chromedp.Run(b.Ctx,
-			chromedp.FullScreenshot(&buf, 90),
+	if opts.Media != "" {
+		m, _ := ParseMedia(opts.Media)
+		actions = append(actions,
+			mediaAction(b.GetMedia().merge(m)),
+			chromedp.Evaluate(waitForPaintJS, nil, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+				return p.WithAwaitPromise(true)
+			}),
 		)
-	} else {
-		err = chromedp.Run(b.Ctx,
-			chromedp.CaptureScreenshot(&buf),
+	}
+
+	var elemRect page.Viewport
+	switch {
+	case isDeepSelector(opts.Selector):
+		if err := b.checkSelector(opts.Selector); err != nil {
+			return nil, err
+		}
+		actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			elemRect, err = b.deepElementDocumentRect(ctx, opts.Selector)
+			return err
+		}))
+	case opts.Selector != "":
+		actions = append(actions,
+			chromedp.ScrollIntoView(opts.Selector, chromedp.BySearch),
+			chromedp.QueryAfter(opts.Selector, func(ctx context.Context, _ runtime.ExecutionContextID, nodes ...*cdp.Node) error {
+				if len(nodes) < 1 {
+					return fmt.Errorf("selector %q did not return any nodes", opts.Selector)
+				}
+				obj, err := dom.ResolveNode().WithNodeID(nodes[0].NodeID).Do(ctx)
+				if err != nil {
+					return err
+				}
+				return chromedp.CallFunctionOn(elementDocumentRectJS, &elemRect, func(p *runtime.CallFunctionOnParams) *runtime.CallFunctionOnParams {
+					return p.WithObjectID(obj.ObjectID)
+				}).Do(ctx)
+			}, chromedp.BySearch, chromedp.NodeVisible),
 		)
 	}
 
+	if opts.OmitBackground {
+		actions = append(actions, emulation.SetDefaultBackgroundColorOverride().WithColor(&cdp.RGBA{A: 0}))
+	}
+
+	actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+		clip, err := opts.clip(ctx, elemRect)
+		if err != nil {
+			return err
+		}
+		p := page.CaptureScreenshot().
+			WithFormat(page.CaptureScreenshotFormat(opts.Format)).
+			WithFromSurface(true)
+		if opts.Quality > 0 {
+			p = p.WithQuality(int64(opts.Quality))
+		}
+		if opts.Fullpage || clip != nil {
+			p = p.WithCaptureBeyondViewport(true)
+		}
+		if clip != nil {
+			p = p.WithClip(clip)
+		}
+		buf, err = p.Do(ctx)
+		return err
+	}))
+
+	err := chromedp.Run(b.Ctx, actions...)
+
+	if opts.OmitBackground {
+		// Clear the override even when the capture failed.
+		if rerr := chromedp.Run(b.Ctx, emulation.SetDefaultBackgroundColorOverride()); rerr != nil {
+			b.Logger(fmt.Sprintf("Warning: failed to restore page background: %v", rerr))
+		}
+	}
+	if opts.Media != "" {
+		if rerr := b.applyMedia(); rerr != nil {
+			b.Logger(fmt.Sprintf("Warning: failed to restore media emulation: %v", rerr))
+		}
+	}
+
 	if err != nil {
+		if opts.Selector != "" {
+			return nil, fmt.Errorf("failed to capture element screenshot: %v", err)
+		}
 		return nil, fmt.Errorf("failed to capture screenshot: %v", err)
 	}
 
 	res := &ScreenshotResult{
 		ImageData: buf,
+		MimeType:  opts.MimeType(),
 	}
 
-	// Capture comprehensive page context
-	err = chromedp.Run(b.Ctx,
+	// Capture page context; the structure only describes whole-page shots.
+	meta := []chromedp.Action{
 		chromedp.Title(&res.PageTitle),
 		chromedp.Location(&res.PageURL),
 		chromedp.Evaluate(`window.innerWidth`, &res.Width),
 		chromedp.Evaluate(`window.innerHeight`, &res.Height),
-		chromedp.Evaluate(GetStructureJS, &res.HTMLStructure),
-	)
-
+	}
+	err = chromedp.Run(b.Ctx, meta...)
+	if err == nil && opts.Selector == "" {
+		res.HTMLStructure, err = b.pageStructure(b.Ctx)
+	}
 	if err != nil {
 		// Non-fatal, return what we have
 		b.Logger(fmt.Sprintf("Warning: failed to capture context metadata: %v", err))
@@ -60,32 +269,79 @@ func (b *DevBrowser) CaptureScreenshot(fullpage bool) (*ScreenshotResult, error)
 	return res, nil
 }
 
-// CaptureElementScreenshot captures a screenshot of a specific element.
-func (b *DevBrowser) CaptureElementScreenshot(selector string) (*ScreenshotResult, error) {
-	if !b.IsOpenFlag || b.Ctx == nil {
-		return nil, fmt.Errorf("browser is not open")
-	}
-
-	var buf []byte
-	err := chromedp.Run(b.Ctx,
-		chromedp.Screenshot(selector, &buf),
-	)
+// elementDocumentRectJS returns the element's border box in document
+// coordinates, the space CaptureScreenshot clips are expressed in when
+// captureBeyondViewport is set.
+const elementDocumentRectJS = `function() {
+	const r = this.getBoundingClientRect();
+	return {x: r.left + window.scrollX, y: r.top + window.scrollY, width: r.width, height: r.height};
+}`
 
+// deepElementDocumentRect is elementDocumentRectJS for refs and selector
+// paths, whose element may sit inside frames.
+func (b *DevBrowser) deepElementDocumentRect(ctx context.Context, selector string) (page.Viewport, error) {
+	el, err := b.resolveElement(ctx, selector)
 	if err != nil {
-		return nil, fmt.Errorf("failed to capture element screenshot: %v", err)
+		return page.Viewport{}, err
 	}
+	defer el.release()
+	if err := b.scrollNodeIntoView(ctx, el.target, el.node); err != nil {
+		return page.Viewport{}, err
+	}
+	r, err := b.elementViewportRect(ctx, el)
+	if err != nil {
+		return page.Viewport{}, fmt.Errorf("element %q: %v", selector, err)
+	}
+	var visual *page.VisualViewport
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		_, _, _, _, visual, _, err = page.GetLayoutMetrics().Do(ctx)
+		return err
+	})); err != nil {
+		return page.Viewport{}, fmt.Errorf("failed to read layout metrics: %v", err)
+	}
+	r.X += visual.PageX
+	r.Y += visual.PageY
+	return r, nil
+}
 
-	res := &ScreenshotResult{
-		ImageData: buf,
+// clip builds the CDP clip rectangle, or nil for a plain viewport/full page
+// capture at scale 1. Clips are document-relative in CDP, so viewport clips
+// are shifted by the current scroll position.
+func (o ScreenshotOptions) clip(ctx context.Context, elemRect page.Viewport) (*page.Viewport, error) {
+	hasClip := o.ClipWidth > 0 && o.ClipHeight > 0
+	if o.Selector == "" && !hasClip && o.Scale == 1 {
+		return nil, nil
 	}
 
-	// Capture basic context
-	err = chromedp.Run(b.Ctx,
-		chromedp.Title(&res.PageTitle),
-		chromedp.Location(&res.PageURL),
-		chromedp.Evaluate(`window.innerWidth`, &res.Width),
-		chromedp.Evaluate(`window.innerHeight`, &res.Height),
-	)
+	var clip page.Viewport
+	switch {
+	case o.Selector != "":
+		clip = elemRect
+		// Align fractional boxes the way chromedp.ScreenshotNodes does.
+		x, y := math.Round(clip.X), math.Round(clip.Y)
+		clip.Width, clip.Height = math.Round(clip.Width+clip.X-x), math.Round(clip.Height+clip.Y-y)
+		clip.X, clip.Y = x, y
+		if clip.Width <= 0 || clip.Height <= 0 {
+			return nil, fmt.Errorf("element %q has an empty box", o.Selector)
+		}
 
-	return res, nil
+	default:
+		_, _, _, _, visual, content, err := page.GetLayoutMetrics().Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read layout metrics: %v", err)
+		}
+		switch {
+		case hasClip && o.Fullpage:
+			clip = page.Viewport{X: o.ClipX, Y: o.ClipY, Width: o.ClipWidth, Height: o.ClipHeight}
+		case hasClip:
+			clip = page.Viewport{X: visual.PageX + o.ClipX, Y: visual.PageY + o.ClipY, Width: o.ClipWidth, Height: o.ClipHeight}
+		case o.Fullpage:
+			clip = page.Viewport{Width: content.Width, Height: content.Height}
+		default:
+			clip = page.Viewport{X: visual.PageX, Y: visual.PageY, Width: visual.ClientWidth, Height: visual.ClientHeight}
+		}
+	}
+
+	clip.Scale = o.Scale
+	return &clip, nil
 }
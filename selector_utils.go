@@ -0,0 +1,372 @@
+package devbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tinywasm/devbrowser/cdproto/cdp"
+	"github.com/tinywasm/devbrowser/cdproto/dom"
+	"github.com/tinywasm/devbrowser/cdproto/page"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/cdproto/target"
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// selectorPathSep separates the steps of a selector path: each step after
+// the first is matched inside the iframe document or shadow root of the
+// element before it ("iframe#pay >>> button.submit").
+const selectorPathSep = ">>>"
+
+// resolvedElement is a handle to the element a selector argument points
+// at, in the target that renders it. The caller releases it.
+type resolvedElement struct {
+	ctx    context.Context // Runs commands against target
+	target target.ID       // Out-of-process frame, "" = the page
+	obj    *runtime.RemoteObject
+	node   cdp.BackendNodeID
+}
+
+// elementMissing is the error of a selector argument that matches no
+// element, or of a ref whose element is gone, as opposed to failing to read
+// the page (a timeout, a navigation, a JS exception).
+type elementMissing struct{ msg string }
+
+func (e elementMissing) Error() string { return e.msg }
+
+// isElementMissing reports whether err says the element is not in the page.
+func isElementMissing(err error) bool {
+	var missing elementMissing
+	return errors.As(err, &missing)
+}
+
+// do runs fn against the target of the element.
+func (e *resolvedElement) do(fn func(ctx context.Context) error) error {
+	return chromedp.Run(e.ctx, chromedp.ActionFunc(fn))
+}
+
+// call calls the JS function declaration fn with this bound to the element.
+func (e *resolvedElement) call(fn string, res any) error {
+	return e.do(func(ctx context.Context) error {
+		return chromedp.CallFunctionOn(fn, res, func(p *runtime.CallFunctionOnParams) *runtime.CallFunctionOnParams {
+			return p.WithObjectID(e.obj.ObjectID)
+		}).Do(ctx)
+	})
+}
+
+func (e *resolvedElement) release() {
+	if e.obj == nil || e.obj.ObjectID == "" {
+		return
+	}
+	e.do(func(ctx context.Context) error {
+		return runtime.ReleaseObject(e.obj.ObjectID).Do(ctx)
+	})
+}
+
+// isDeepSelector reports whether a selector argument needs resolveElement:
+// a ref, a locator or a selector path. Plain CSS selectors go straight to
+// chromedp.
+func isDeepSelector(selector string) bool {
+	return isElementRef(selector) || isLocator(selector) || strings.Contains(selector, selectorPathSep)
+}
+
+// splitSelectorPath splits a selector path into its trimmed steps. ">>>"
+// inside quotes or attribute brackets is part of the step. Only the first
+// step may be a ref.
+func splitSelectorPath(selector string) ([]string, error) {
+	var steps []string
+	var quote rune
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case depth == 0 && i >= start && strings.HasPrefix(selector[i:], selectorPathSep):
+			steps = append(steps, strings.TrimSpace(selector[start:i]))
+			start = i + len(selectorPathSep)
+		}
+	}
+	steps = append(steps, strings.TrimSpace(selector[start:]))
+
+	for i, step := range steps {
+		if step == "" {
+			return nil, fmt.Errorf("invalid selector path %q: empty step", selector)
+		}
+		if i > 0 && isElementRef(step) {
+			return nil, fmt.Errorf("invalid selector path %q: only the first step can be a ref", selector)
+		}
+	}
+	return steps, nil
+}
+
+// checkSelector validates a ref, locator or selector path without touching
+// the browser, so a typo fails before any waiting.
+func (b *DevBrowser) checkSelector(selector string) error {
+	if !isDeepSelector(selector) {
+		return nil
+	}
+	steps, err := splitSelectorPath(selector)
+	if err != nil {
+		return err
+	}
+	if isElementRef(steps[0]) {
+		if _, _, err := b.lookupRef(steps[0]); err != nil {
+			return err
+		}
+	}
+	for _, step := range steps {
+		if isLocator(step) {
+			if _, err := parseLocator(step); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkCSSSyntax reports a syntax error in a plain CSS selector, which
+// querying would only report as no match. Deep selectors pass.
+func checkCSSSyntax(ctx context.Context, selector string) error {
+	if isDeepSelector(selector) {
+		return nil
+	}
+	arg, err := json.Marshal(selector)
+	if err != nil {
+		return err
+	}
+	var syntax string
+	if err := chromedp.Run(ctx, chromedp.Evaluate(`(() => { try { document.createDocumentFragment().querySelector(`+string(arg)+`); return ''; } catch (e) { return e.message; } })()`, &syntax)); err == nil && syntax != "" {
+		return fmt.Errorf("invalid selector: %s", syntax)
+	}
+	return nil
+}
+
+// resolveElement finds the element of a ref, locator or selector path, descending
+// through iframes (same-origin, cross-origin and out-of-process) and
+// shadow roots (open or closed). ctx runs against the page.
+func (b *DevBrowser) resolveElement(ctx context.Context, selector string) (*resolvedElement, error) {
+	steps, err := splitSelectorPath(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var el *resolvedElement
+	if isElementRef(steps[0]) {
+		el, err = b.resolveRef(ctx, steps[0])
+	} else {
+		el, err = queryElement(ctx, "", nil, steps[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, step := range steps[1:] {
+		next, err := b.descendInto(ctx, el, step)
+		el.release()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.Join(steps[:i+1], " "+selectorPathSep+" "), err)
+		}
+		el = next
+	}
+	return el, nil
+}
+
+// queryElement runs querySelector on root, or on the document of the target
+// tctx runs against when root is nil. Locator steps go to queryLocator.
+func queryElement(tctx context.Context, tgt target.ID, root *runtime.RemoteObject, selector string) (*resolvedElement, error) {
+	if isLocator(selector) {
+		return queryLocator(tctx, tgt, root, selector)
+	}
+	arg, err := json.Marshal(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	el := &resolvedElement{ctx: tctx, target: tgt}
+	err = el.do(func(ctx context.Context) error {
+		var obj *runtime.RemoteObject
+		var exc *runtime.ExceptionDetails
+		var err error
+		if root == nil {
+			obj, exc, err = runtime.Evaluate("document.querySelector(" + string(arg) + ")").Do(ctx)
+		} else {
+			obj, exc, err = runtime.CallFunctionOn(`function(s) { return this.querySelector(s); }`).
+				WithObjectID(root.ObjectID).
+				WithArguments([]*runtime.CallArgument{{Value: arg}}).
+				Do(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		if exc != nil {
+			return fmt.Errorf("invalid selector %q: %v", selector, exc)
+		}
+		if obj.ObjectID == "" {
+			return elementMissing{"element not found: " + selector}
+		}
+		el.obj = obj
+		n, err := dom.DescribeNode().WithObjectID(obj.ObjectID).Do(ctx)
+		if err != nil {
+			return err
+		}
+		el.node = n.BackendNodeID
+		return nil
+	})
+	if err != nil {
+		el.release()
+		return nil, err
+	}
+	return el, nil
+}
+
+// descendInto matches selector inside the frame or shadow root of el.
+func (b *DevBrowser) descendInto(ctx context.Context, el *resolvedElement, selector string) (*resolvedElement, error) {
+	var n *cdp.Node
+	if err := el.do(func(ctx context.Context) error {
+		var err error
+		n, err = dom.DescribeNode().WithObjectID(el.obj.ObjectID).Do(ctx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	// resolveIn resolves a document or shadow root of el's target and
+	// queries inside it.
+	resolveIn := func(id cdp.BackendNodeID) (*resolvedElement, error) {
+		root := &resolvedElement{ctx: el.ctx, target: el.target, node: id}
+		if err := root.do(func(ctx context.Context) error {
+			var err error
+			root.obj, err = dom.ResolveNode().WithBackendNodeID(id).Do(ctx)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		defer root.release()
+		return queryElement(el.ctx, el.target, root.obj, selector)
+	}
+
+	switch {
+	case n.ContentDocument != nil:
+		// Frame rendered by the same process, whatever its origin.
+		return resolveIn(n.ContentDocument.BackendNodeID)
+
+	case n.FrameID != "" && (n.NodeName == "IFRAME" || n.NodeName == "FRAME"):
+		// Out-of-process frame: its document lives in its own target.
+		if _, err := b.syncFrameTargets(ctx); err != nil {
+			return nil, err
+		}
+		id := target.ID(n.FrameID)
+		fctx, err := b.targetContext(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("frame is not accessible: %v", err)
+		}
+		return queryElement(fctx, id, nil, selector)
+	}
+
+	for _, root := range n.ShadowRoots {
+		if root.ShadowRootType != cdp.ShadowRootTypeUserAgent {
+			return resolveIn(root.BackendNodeID)
+		}
+	}
+	return nil, fmt.Errorf("<%s> is neither an iframe nor a shadow host", strings.ToLower(n.NodeName))
+}
+
+// elementViewportRect is the border box of el in page viewport coordinates.
+func (b *DevBrowser) elementViewportRect(ctx context.Context, el *resolvedElement) (page.Viewport, error) {
+	var quads []dom.Quad
+	if err := el.do(func(ctx context.Context) error {
+		var err error
+		quads, err = dom.GetContentQuads().WithBackendNodeID(el.node).Do(ctx)
+		return err
+	}); err != nil || len(quads) == 0 || len(quads[0]) < 8 {
+		return page.Viewport{}, fmt.Errorf("element has no box (hidden or not rendered)")
+	}
+
+	q := quads[0]
+	minX, minY, maxX, maxY := q[0], q[1], q[0], q[1]
+	for i := 2; i < len(q); i += 2 {
+		minX, maxX = min(minX, q[i]), max(maxX, q[i])
+		minY, maxY = min(minY, q[i+1]), max(maxY, q[i+1])
+	}
+
+	ox, oy, err := b.frameOffset(ctx, el.target)
+	if err != nil {
+		return page.Viewport{}, err
+	}
+	return page.Viewport{X: ox + minX, Y: oy + minY, Width: maxX - minX, Height: maxY - minY}, nil
+}
+
+// elementCenter scrolls el into view and returns its center in page
+// viewport coordinates, where mouse and touch events on the page land.
+func (b *DevBrowser) elementCenter(ctx context.Context, el *resolvedElement) (float64, float64, error) {
+	if err := b.scrollNodeIntoView(ctx, el.target, el.node); err != nil {
+		return 0, 0, err
+	}
+	r, err := b.elementViewportRect(ctx, el)
+	if err != nil {
+		return 0, 0, err
+	}
+	return r.X + r.Width/2, r.Y + r.Height/2, nil
+}
+
+// scrollNodeIntoView scrolls the <iframe> elements of an out-of-process
+// frame into view, outermost first, and then the node itself.
+func (b *DevBrowser) scrollNodeIntoView(ctx context.Context, tgt target.ID, node cdp.BackendNodeID) error {
+	if tgt != "" {
+		b.Mu.Lock()
+		ft := b.frameTargets[tgt]
+		b.Mu.Unlock()
+		if ft == nil {
+			return fmt.Errorf("frame %s is gone", tgt)
+		}
+		if err := b.scrollNodeIntoView(ctx, ft.parent, ft.owner); err != nil {
+			return err
+		}
+	}
+	tctx, err := b.targetContext(ctx, tgt)
+	if err != nil {
+		return err
+	}
+	return chromedp.Run(tctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		return dom.ScrollIntoViewIfNeeded().WithBackendNodeID(node).Do(ctx)
+	}))
+}
+
+// clickRemoteElement clicks the center of the element of a selector that
+// elementQuery reported as remote, through the page.
+func (b *DevBrowser) clickRemoteElement(ctx context.Context, selector string) error {
+	el, err := b.resolveElement(ctx, selector)
+	if err != nil {
+		return err
+	}
+	defer el.release()
+	x, y, err := b.elementCenter(ctx, el)
+	if err != nil {
+		return err
+	}
+	return chromedp.Run(ctx, chromedp.MouseClickXY(x, y))
+}
+
+// focusElement focuses the element of a selector argument so that key
+// events reach it. An out-of-process frame only takes the page's focus by
+// a click.
+func (b *DevBrowser) focusElement(ctx context.Context, selector string) error {
+	sel, qopts, remote, err := b.elementQuery(ctx, selector, chromedp.ByQuery)
+	if err != nil {
+		return err
+	}
+	if remote {
+		return b.clickRemoteElement(ctx, selector)
+	}
+	return chromedp.Run(ctx, chromedp.Focus(sel, qopts...))
+}
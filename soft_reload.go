@@ -0,0 +1,311 @@
+package devbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tinywasm/devbrowser/cdproto/page"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// pageStateJS snapshots what a reload loses and the page can get back: the
+// scroll offsets of the window and of scrolled elements, the focused element
+// with its text selection, the form fields changed from their defaults and
+// the given localStorage and sessionStorage keys. Elements are kept as
+// selectors: a unique id, a form field name, or a nth-of-type path.
+const pageStateJS = `(keys) => {
+	const unique = sel => { try { return document.querySelectorAll(sel).length === 1; } catch (e) { return false; } };
+	const path = el => {
+		if (el.id && unique('#' + CSS.escape(el.id))) return '#' + CSS.escape(el.id);
+		if (el.name && el.form !== undefined) {
+			const sel = el.tagName.toLowerCase() + '[name="' + CSS.escape(el.name) + '"]';
+			if (unique(sel)) return sel;
+			const byValue = sel + '[value="' + CSS.escape(el.value) + '"]';
+			if ((el.type === 'radio' || el.type === 'checkbox') && unique(byValue)) return byValue;
+		}
+		const parts = [];
+		for (let e = el; e && e !== document.documentElement; e = e.parentElement) {
+			if (e.id && unique('#' + CSS.escape(e.id))) {
+				parts.unshift('#' + CSS.escape(e.id));
+				break;
+			}
+			let i = 1;
+			for (let s = e.previousElementSibling; s; s = s.previousElementSibling) if (s.tagName === e.tagName) i++;
+			parts.unshift(e.tagName.toLowerCase() + ':nth-of-type(' + i + ')');
+		}
+		return parts.join(' > ');
+	};
+
+	const fields = [];
+	for (const el of document.querySelectorAll('input, textarea, select')) {
+		const type = (el.type || '').toLowerCase();
+		if (['password', 'file', 'hidden', 'submit', 'button', 'reset', 'image'].includes(type)) continue;
+		if (type === 'checkbox' || type === 'radio') {
+			if (el.checked !== el.defaultChecked) fields.push({sel: path(el), checked: el.checked});
+		} else if (el.tagName === 'SELECT') {
+			const opts = Array.from(el.options);
+			const values = opts.filter(o => o.selected).map(o => o.value);
+			let defaults = opts.filter(o => o.defaultSelected).map(o => o.value);
+			if (!defaults.length && !el.multiple && opts.length) defaults = [opts[0].value];
+			if (JSON.stringify(values) !== JSON.stringify(defaults)) fields.push({sel: path(el), values});
+		} else if (el.value !== el.defaultValue) {
+			fields.push({sel: path(el), value: el.value});
+		}
+	}
+
+	const scrolled = [];
+	for (const el of document.body ? document.body.querySelectorAll('*') : []) {
+		if (el.scrollTop || el.scrollLeft) scrolled.push({sel: path(el), x: el.scrollLeft, y: el.scrollTop});
+		if (scrolled.length >= 20) break;
+	}
+
+	let focus = null;
+	const active = document.activeElement;
+	if (active && active !== document.body && active !== document.documentElement) {
+		focus = {sel: path(active)};
+		try {
+			if (typeof active.selectionStart === 'number') {
+				focus.start = active.selectionStart;
+				focus.end = active.selectionEnd;
+			}
+		} catch (e) {}
+	}
+
+	const local = {}, session = {};
+	for (const k of keys) {
+		try {
+			const l = localStorage.getItem(k);
+			if (l !== null) local[k] = l;
+			const s = sessionStorage.getItem(k);
+			if (s !== null) session[k] = s;
+		} catch (e) {}
+	}
+	return {origin: location.origin, x: scrollX, y: scrollY, scrolled, focus, fields, local, session};
+}`
+
+// restorePageStateJS puts a pageStateJS snapshot back after the reload,
+// retrying for up to 2s while the page renders the elements it refers to
+// (a WASM app builds its DOM after load). Fields fire input and change.
+const restorePageStateJS = `(s) => new Promise(resolve => {
+	const find = sel => { try { return document.querySelector(sel); } catch (e) { return null; } };
+	const deadline = Date.now() + 2000;
+	let fields = s.fields || [], scrolled = s.scrolled || [];
+	let restored = 0, elements = 0, scroll = false, focus = '';
+	const step = () => {
+		fields = fields.filter(f => {
+			const el = find(f.sel);
+			if (!el) return true;
+			if (f.values) {
+				for (const o of el.options) o.selected = f.values.includes(o.value);
+			} else if (f.checked !== undefined) {
+				el.checked = f.checked;
+			} else {
+				el.value = f.value;
+			}
+			el.dispatchEvent(new Event('input', {bubbles: true}));
+			el.dispatchEvent(new Event('change', {bubbles: true}));
+			restored++;
+			return false;
+		});
+		scrolled = scrolled.filter(e => {
+			const el = find(e.sel);
+			if (!el) return true;
+			el.scrollLeft = e.x;
+			el.scrollTop = e.y;
+			if (Math.abs(el.scrollLeft - e.x) > 1 || Math.abs(el.scrollTop - e.y) > 1) return true;
+			elements++;
+			return false;
+		});
+		if (!scroll) {
+			scrollTo(s.x, s.y);
+			scroll = Math.abs(scrollX - s.x) <= 1 && Math.abs(scrollY - s.y) <= 1;
+		}
+		if (s.focus && !focus) {
+			const el = find(s.focus.sel);
+			if (el) {
+				el.focus({preventScroll: true});
+				if (s.focus.start !== undefined) {
+					try { el.setSelectionRange(s.focus.start, s.focus.end); } catch (e) {}
+				}
+				focus = s.focus.sel;
+			}
+		}
+		if ((fields.length || scrolled.length || !scroll || (s.focus && !focus)) && Date.now() < deadline) {
+			setTimeout(step, 100);
+			return;
+		}
+		resolve({fields: restored, missing: fields.map(f => f.sel), elements, scroll, x: Math.round(scrollX), y: Math.round(scrollY), focus});
+	};
+	step();
+})`
+
+// restoreStorageJS writes the storage keys of a snapshot back before the
+// page scripts run, so the app starts from them.
+const restoreStorageJS = `(s => {
+	if (location.origin !== s.origin) return;
+	try {
+		for (const k in s.local) localStorage.setItem(k, s.local[k]);
+		for (const k in s.session) sessionStorage.setItem(k, s.session[k]);
+	} catch (e) {}
+})`
+
+// pageState is a pageStateJS snapshot.
+type pageState struct {
+	Origin   string            `json:"origin"`
+	X        float64           `json:"x"`
+	Y        float64           `json:"y"`
+	Scrolled []json.RawMessage `json:"scrolled"`
+	Focus    json.RawMessage   `json:"focus"`
+	Fields   []json.RawMessage `json:"fields"`
+	Local    map[string]string `json:"local"`
+	Session  map[string]string `json:"session"`
+}
+
+// ReloadReport is what a soft reload put back on the page.
+type ReloadReport struct {
+	Scroll   bool     // The window scroll offset was restored
+	ScrollX  int      // Window scroll offset after the reload
+	ScrollY  int      //
+	Elements int      // Scrolled elements restored
+	Fields   int      // Form fields restored
+	Missing  []string // Fields not found after the reload
+	Focus    string   // Selector of the element focused again
+	Storage  []string // Storage keys written back before the page scripts ran
+}
+
+// String formats the report for the log.
+func (r ReloadReport) String() string {
+	var parts []string
+	if r.Scroll {
+		parts = append(parts, fmt.Sprintf("scroll %d,%d", r.ScrollX, r.ScrollY))
+	}
+	if r.Elements > 0 {
+		parts = append(parts, fmt.Sprintf("%d scrolled elements", r.Elements))
+	}
+	if r.Fields > 0 {
+		parts = append(parts, fmt.Sprintf("%d form fields", r.Fields))
+	}
+	if r.Focus != "" {
+		parts = append(parts, "focus "+r.Focus)
+	}
+	if len(r.Storage) > 0 {
+		parts = append(parts, "storage "+strings.Join(r.Storage, ", "))
+	}
+	s := "nothing to restore"
+	if len(parts) > 0 {
+		s = "restored " + strings.Join(parts, ", ")
+	}
+	if len(r.Missing) > 0 {
+		s += "; not found: " + strings.Join(r.Missing, ", ")
+	}
+	return s
+}
+
+// WithSoftReload makes Reload keep the page state: scroll offsets, the
+// focused element, changed form fields and the given localStorage or
+// sessionStorage keys are put back after the reload, and stylesheet or
+// image changes passed to ReloadForFile reload those assets in place. The
+// in-memory state of the app is still lost.
+func WithSoftReload(storageKeys ...string) Option {
+	return func(b *DevBrowser) {
+		b.softReload = true
+		b.softReloadKeys = storageKeys
+	}
+}
+
+// SetSoftReload turns soft reloads on or off, see WithSoftReload.
+func (b *DevBrowser) SetSoftReload(enabled bool, storageKeys ...string) {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+	b.softReload = enabled
+	b.softReloadKeys = storageKeys
+}
+
+// SoftReload reloads the page keeping its state, see WithSoftReload.
+func (b *DevBrowser) SoftReload() (ReloadReport, error) {
+	if b.Ctx == nil {
+		return ReloadReport{}, errors.New("context not initialized")
+	}
+	b.Mu.Lock()
+	keys := b.softReloadKeys
+	b.Mu.Unlock()
+	if keys == nil {
+		keys = []string{}
+	}
+	encodedKeys, err := json.Marshal(keys)
+	if err != nil {
+		return ReloadReport{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(b.Ctx, navDefaultTimeout)
+	defer cancel()
+
+	var raw json.RawMessage
+	if err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf("(%s)(%s)", pageStateJS, encodedKeys), &raw)); err != nil {
+		return ReloadReport{}, fmt.Errorf("Error reading page state: %v", err)
+	}
+	var st pageState
+	if err := json.Unmarshal(raw, &st); err != nil {
+		return ReloadReport{}, fmt.Errorf("Error reading page state: %v", err)
+	}
+
+	var report ReloadReport
+	var scriptID page.ScriptIdentifier
+	if len(st.Local)+len(st.Session) > 0 {
+		storage, err := json.Marshal(map[string]any{"origin": st.Origin, "local": st.Local, "session": st.Session})
+		if err != nil {
+			return ReloadReport{}, err
+		}
+		if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			scriptID, err = page.AddScriptToEvaluateOnNewDocument(fmt.Sprintf("%s(%s)", restoreStorageJS, storage)).Do(ctx)
+			return err
+		})); err != nil {
+			return ReloadReport{}, fmt.Errorf("Error saving storage: %v", err)
+		}
+		for k := range st.Local {
+			report.Storage = append(report.Storage, "localStorage."+k)
+		}
+		for k := range st.Session {
+			report.Storage = append(report.Storage, "sessionStorage."+k)
+		}
+		sort.Strings(report.Storage)
+	}
+
+	_, navErr := b.Navigate(Navigation{Action: "reload"})
+	if scriptID != "" {
+		if err := chromedp.Run(ctx, page.RemoveScriptToEvaluateOnNewDocument(scriptID)); err != nil {
+			b.Logger(fmt.Sprintf("Failed to remove storage restore script: %v", err))
+		}
+	}
+	if navErr != nil {
+		return ReloadReport{}, navErr
+	}
+
+	var restored struct {
+		Fields   int      `json:"fields"`
+		Missing  []string `json:"missing"`
+		Elements int      `json:"elements"`
+		Scroll   bool     `json:"scroll"`
+		X        int      `json:"x"`
+		Y        int      `json:"y"`
+		Focus    string   `json:"focus"`
+	}
+	if err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf("(%s)(%s)", restorePageStateJS, raw), &restored, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	})); err != nil {
+		return report, fmt.Errorf("Error restoring page state: %v", err)
+	}
+	report.Scroll = restored.Scroll && (st.X != 0 || st.Y != 0)
+	report.ScrollX, report.ScrollY = restored.X, restored.Y
+	report.Elements = restored.Elements
+	report.Fields = restored.Fields
+	report.Missing = restored.Missing
+	report.Focus = restored.Focus
+	return report, nil
+}
@@ -0,0 +1,198 @@
+package devbrowser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tinywasm/mcp"
+)
+
+// stepKind maps a browser_run_steps step to the tool that runs it. main is
+// the argument a string step stands for: {"click": "#save"} is
+// {"click": {"selector": "#save"}}.
+type stepKind struct {
+	tool string
+	main string
+}
+
+var stepKinds = map[string]stepKind{
+	"navigate":   {"browser_navigate", "url"},
+	"click":      {"browser_click_element", "selector"},
+	"fill":       {"browser_fill_element", ""},
+	"press":      {"browser_press_key", "keys"},
+	"wait":       {"browser_wait_for", "selector"},
+	"assert":     {"browser_assert", "selector"},
+	"screenshot": {"browser_screenshot", ""},
+	"evaluate":   {"browser_evaluate_js", "script"},
+}
+
+// runStep is a parsed step: its kind and the JSON arguments of its tool.
+type runStep struct {
+	kind string
+	args map[string]any
+}
+
+// label names the step in the log by its kind and main target.
+func (s runStep) label() string {
+	for _, key := range []string{"selector", "url", "keys", "text", "script"} {
+		if v, ok := s.args[key].(string); ok && v != "" {
+			return s.kind + " " + summarizeLine(v, 60)
+		}
+	}
+	return s.kind
+}
+
+// timeout is the timeout the step sets, or def.
+func (s runStep) timeout(def time.Duration) time.Duration {
+	if n, ok := s.args["timeout"].(json.Number); ok {
+		if ms, err := n.Int64(); err == nil && ms > 0 {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+	return def
+}
+
+// parseSteps reads the steps of browser_run_steps: a JSON array of objects
+// with one key, the step kind, holding the arguments of its tool.
+func parseSteps(steps string) ([]runStep, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(steps)))
+	dec.UseNumber()
+	var raw []map[string]any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid steps: %v. Use a JSON array like [{\"navigate\": \"/login\"}, {\"fill\": {\"selector\": \"#email\", \"value\": \"a@b.c\"}}, {\"click\": \"text=Log in\"}]", err)
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("no steps")
+	}
+
+	out := make([]runStep, len(raw))
+	for i, obj := range raw {
+		if len(obj) != 1 {
+			return nil, fmt.Errorf("step %d: use one key, the step kind (%s)", i+1, stepKindList())
+		}
+		for kind, value := range obj {
+			k, ok := stepKinds[kind]
+			if !ok {
+				return nil, fmt.Errorf("step %d: unsupported step %s. Use %s", i+1, kind, stepKindList())
+			}
+			step := runStep{kind: kind}
+			switch v := value.(type) {
+			case map[string]any:
+				step.args = v
+			case string:
+				if k.main == "" {
+					return nil, fmt.Errorf("step %d: %s takes an object of arguments", i+1, kind)
+				}
+				step.args = map[string]any{k.main: v}
+			default:
+				return nil, fmt.Errorf("step %d: %s takes an object of arguments or a string", i+1, kind)
+			}
+			out[i] = step
+		}
+	}
+	return out, nil
+}
+
+func stepKindList() string {
+	kinds := make([]string, 0, len(stepKinds))
+	for k := range stepKinds {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return strings.Join(kinds, ", ")
+}
+
+// stepRequest encodes the arguments of a step as a call of tool, filling in
+// timeout when the tool takes one and the step sets none. Unknown arguments
+// fail here instead of being ignored.
+func stepRequest(tool mcp.Tool, step runStep, timeout int64) (mcp.Request, error) {
+	known := map[string]bool{}
+	for _, f := range tool.Args.Schema() {
+		known[f.Name] = true
+	}
+	for key := range step.args {
+		if !known[key] {
+			return mcp.Request{}, fmt.Errorf("unknown argument %s for %s", key, step.kind)
+		}
+	}
+	args := step.args
+	if _, ok := args["timeout"]; !ok && known["timeout"] {
+		args = map[string]any{"timeout": timeout}
+		for k, v := range step.args {
+			args[k] = v
+		}
+	}
+
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return mcp.Request{}, err
+	}
+	return mcp.Request{
+		Params: mcp.CallToolParams{Name: tool.Name, Arguments: string(encoded)},
+		Action: byte(tool.Action),
+	}, nil
+}
+
+// stepOutput is the text and the images of a tool result.
+func stepOutput(res *mcp.Result) (string, []mcp.ContentBlock) {
+	if res == nil {
+		return "", nil
+	}
+	var blocks []struct {
+		Type     string `json:"type"`
+		Text     string `json:"text"`
+		Data     string `json:"data"`
+		MimeType string `json:"mimeType"`
+	}
+	if err := json.Unmarshal([]byte(res.Content), &blocks); err != nil {
+		return "", nil
+	}
+	var text []string
+	var images []mcp.ContentBlock
+	for _, bl := range blocks {
+		switch bl.Type {
+		case "text":
+			text = append(text, bl.Text)
+		case "image":
+			if data, err := base64.StdEncoding.DecodeString(bl.Data); err == nil {
+				images = append(images, mcp.ImageBlock(data, bl.MimeType))
+			}
+		}
+	}
+	return strings.Join(text, "\n"), images
+}
+
+// summarizeLine shortens text to its first line of at most max runes.
+func summarizeLine(text string, max int) string {
+	line, rest, multi := strings.Cut(strings.TrimSpace(text), "\n")
+	r := []rune(line)
+	if len(r) > max {
+		return string(r[:max]) + "…"
+	}
+	if multi && strings.TrimSpace(rest) != "" {
+		return line + " …"
+	}
+	return line
+}
+
+// runEvents lists the JS errors and failed requests captured since start.
+func (b *DevBrowser) runEvents(start time.Time) []string {
+	var out []string
+	b.ErrorsMutex.Lock()
+	for _, e := range b.JsErrors {
+		if !e.Timestamp.Before(start) {
+			out = append(out, fmt.Sprintf("JS error: %s at %s:%d:%d", e.Message, e.Source, e.LineNumber, e.ColumnNumber))
+		}
+	}
+	b.ErrorsMutex.Unlock()
+
+	for _, r := range b.failedRequestsSince(start) {
+		out = append(out, "Failed request: "+r)
+	}
+	return out
+}
@@ -0,0 +1,258 @@
+package devbrowser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// structureRef matches the element refs of a snapshot line; a node keeps
+// its ref, but whether it has one follows the clickable guess, so refs are
+// not compared.
+var structureRef = regexp.MustCompile(` ref:e\d+`)
+
+// structureNode is one line of a GetStructureJS snapshot with its children.
+type structureNode struct {
+	line     string // As shown, ref included
+	content  string // line without indent and ref, compared across snapshots
+	key      string // Tag and id, used to pair siblings
+	children []*structureNode
+}
+
+// parseStructure rebuilds the tree of a snapshot from its indentation.
+// Lines that are not part of the tree (blank, frame section headers) hang
+// off the root like top-level nodes.
+func parseStructure(structure string) *structureNode {
+	root := &structureNode{}
+	stack := []*structureNode{root}
+	for _, line := range strings.Split(structure, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		depth := (len(line) - len(trimmed)) / 2
+		n := &structureNode{
+			line:    trimmed,
+			content: structureRef.ReplaceAllString(trimmed, ""),
+		}
+		n.key = structureKey(n.content)
+
+		if depth+1 < len(stack) {
+			stack = stack[:depth+1]
+		}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, n)
+		stack = append(stack, n)
+	}
+	return root
+}
+
+var structureID = regexp.MustCompile(` id="([^"]*)"`)
+
+// structureKey is "tag#id", or just the tag: the part of a line that names
+// the element rather than describing its state.
+func structureKey(content string) string {
+	if !strings.HasPrefix(content, "<") {
+		return content
+	}
+	tag := strings.TrimPrefix(strings.FieldsFunc(content, func(r rune) bool { return r == ' ' || r == '>' })[0], "<")
+	if m := structureID.FindStringSubmatch(content); m != nil {
+		return tag + "#" + m[1]
+	}
+	return tag
+}
+
+// label is a short name for a node in change paths: "div#app", "ul.list".
+func (n *structureNode) label() string {
+	if !strings.HasPrefix(n.content, "<") {
+		return n.content
+	}
+	label := n.key
+	if !strings.Contains(label, "#") {
+		if m := structureClass.FindStringSubmatch(n.content); m != nil {
+			label += "." + strings.Fields(m[1])[0]
+		}
+	}
+	return label
+}
+
+var structureClass = regexp.MustCompile(` class="([^"]+)"`)
+
+// structureChange is an added, removed or changed node of a diff.
+type structureChange struct {
+	kind byte // '+', '-' or '~'
+	path string
+	old  *structureNode
+	new  *structureNode
+}
+
+// DiffStructure compares two GetStructureJS snapshots of a page and lists
+// the nodes that were added, removed (or hidden, since hidden elements are
+// not part of a snapshot) and changed (text, attributes or styles), each
+// with the path of its parent:
+//
+//	1 added, 1 removed, 1 changed
+//
+//	+ in body > div#app:
+//	    <div class="toast" [position:fixed]> Saved
+//
+//	- in body > div#app > ul.list:
+//	    <li> Loading...
+//
+//	~ in body > div#app:
+//	    - <button class="btn" [clickable]> Save
+//	    + <button class="btn" disabled [clickable] ref:e4> Saving
+//
+// Siblings are paired on unchanged lines first and then on tag and id, so
+// an inserted row shows as one added node instead of shifting every row
+// after it. Added nodes are shown with their descendants, removed nodes
+// without them. It returns "" when nothing changed.
+func DiffStructure(before, after string) string {
+	var changes []structureChange
+	diffStructureNodes(parseStructure(before), parseStructure(after), nil, &changes)
+	if len(changes) == 0 {
+		return ""
+	}
+
+	counts := map[byte]int{}
+	for _, c := range changes {
+		counts[c.kind]++
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d added, %d removed, %d changed\n", counts['+'], counts['-'], counts['~']))
+	for _, c := range changes {
+		where := c.path
+		if where == "" {
+			where = "page"
+		}
+		sb.WriteString(fmt.Sprintf("\n%c in %s:\n", c.kind, where))
+		switch c.kind {
+		case '+':
+			writeStructureSubtree(&sb, c.new, 1)
+		case '-':
+			sb.WriteString("    " + c.old.line + "\n")
+		case '~':
+			sb.WriteString("    - " + c.old.line + "\n")
+			sb.WriteString("    + " + c.new.line + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// diffStructureNodes pairs the children of two matching nodes and records
+// what differs, recursing into paired children.
+func diffStructureNodes(before, after *structureNode, path []string, changes *[]structureChange) {
+	where := strings.Join(path, " > ")
+	pairs := pairStructureChildren(before.children, after.children)
+
+	for _, p := range pairs {
+		switch {
+		case p[0] < 0:
+			*changes = append(*changes, structureChange{kind: '+', path: where, new: after.children[p[1]]})
+		case p[1] < 0:
+			*changes = append(*changes, structureChange{kind: '-', path: where, old: before.children[p[0]]})
+		default:
+			o, n := before.children[p[0]], after.children[p[1]]
+			if o.content != n.content {
+				*changes = append(*changes, structureChange{kind: '~', path: where, old: o, new: n})
+			}
+			diffStructureNodes(o, n, append(path, n.label()), changes)
+		}
+	}
+}
+
+// structureMaxPairing bounds the LCS tables of pairStructureChildren;
+// longer sibling lists (huge tables, feeds) are paired by position.
+const structureMaxPairing = 1 << 20
+
+// pairStructureChildren aligns two child lists and returns index pairs in
+// document order; -1 marks a child without counterpart. Unchanged lines
+// are paired first, then the nodes between them by tag and id, so a
+// changed row pairs with its old self rather than with a neighbour.
+func pairStructureChildren(before, after []*structureNode) [][2]int {
+	var pairs [][2]int
+	// emit pairs before[i0:i1] with after[j0:j1] along matches, reporting
+	// the nodes in between as removed and added.
+	emit := func(i0, j0 int, matches [][2]int, i1, j1 int) {
+		for _, mt := range append(matches, [2]int{i1, j1}) {
+			for ; i0 < mt[0]; i0++ {
+				pairs = append(pairs, [2]int{i0, -1})
+			}
+			for ; j0 < mt[1]; j0++ {
+				pairs = append(pairs, [2]int{-1, j0})
+			}
+			if mt[0] < i1 {
+				pairs = append(pairs, mt)
+				i0, j0 = mt[0]+1, mt[1]+1
+			}
+		}
+	}
+
+	anchors := lcsMatches(len(before), len(after), func(i, j int) bool {
+		return before[i].content == after[j].content
+	})
+	i0, j0 := 0, 0
+	for _, a := range append(anchors, [2]int{len(before), len(after)}) {
+		gb, ga := before[i0:a[0]], after[j0:a[1]]
+		byKey := lcsMatches(len(gb), len(ga), func(i, j int) bool { return gb[i].key == ga[j].key })
+		for k := range byKey {
+			byKey[k][0] += i0
+			byKey[k][1] += j0
+		}
+		emit(i0, j0, byKey, a[0], a[1])
+		if a[0] < len(before) {
+			pairs = append(pairs, a)
+		}
+		i0, j0 = a[0]+1, a[1]+1
+	}
+	return pairs
+}
+
+// lcsMatches returns the index pairs of a longest common subsequence of two
+// sequences of lengths n and m under eq.
+func lcsMatches(n, m int, eq func(i, j int) bool) [][2]int {
+	var matches [][2]int
+	if n*m > structureMaxPairing {
+		for i := 0; i < min(n, m); i++ {
+			if eq(i, i) {
+				matches = append(matches, [2]int{i, i})
+			}
+		}
+		return matches
+	}
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if eq(i, j) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case eq(i, j):
+			matches = append(matches, [2]int{i, j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return matches
+}
+
+func writeStructureSubtree(sb *strings.Builder, n *structureNode, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth+1) + n.line + "\n")
+	for _, c := range n.children {
+		writeStructureSubtree(sb, c, depth+1)
+	}
+}
@@ -0,0 +1,195 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/cdproto/accessibility"
+	"github.com/tinywasm/devbrowser/cdproto/cdp"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func axValue(raw string) *accessibility.Value {
+	return &accessibility.Value{Type: accessibility.ValueTypeString, Value: []byte(raw)}
+}
+
+func axNode(id, role, name string, children ...string) *accessibility.Node {
+	n := &accessibility.Node{NodeID: accessibility.NodeID(id), Role: axValue(`"` + role + `"`)}
+	if name != "" {
+		n.Name = axValue(`"` + name + `"`)
+	}
+	for _, c := range children {
+		n.ChildIDs = append(n.ChildIDs, accessibility.NodeID(c))
+	}
+	return n
+}
+
+// sampleAXTree is a login form wrapped in an unnamed div and an ignored node.
+func sampleAXTree() []*accessibility.Node {
+	root := axNode("1", "RootWebArea", "Login", "2", "9")
+	wrapper := axNode("2", "generic", "", "3", "5", "7")
+	heading := axNode("3", "heading", "Sign in", "4")
+	heading.Properties = []*accessibility.Property{{Name: accessibility.PropertyNameLevel, Value: axValue(`1`)}}
+	headingText := axNode("4", "StaticText", "Sign in")
+	email := axNode("5", "textbox", "Email")
+	email.Value = axValue(`"a@b.c"`)
+	email.Properties = []*accessibility.Property{
+		{Name: accessibility.PropertyNameFocusable, Value: axValue(`true`)},
+		{Name: accessibility.PropertyNameRequired, Value: axValue(`true`)},
+		{Name: accessibility.PropertyNameInvalid, Value: axValue(`"false"`)},
+	}
+	remember := axNode("7", "checkbox", "Remember me")
+	remember.Properties = []*accessibility.Property{
+		{Name: accessibility.PropertyNameChecked, Value: axValue(`"false"`)},
+		{Name: accessibility.PropertyNameFocusable, Value: axValue(`true`)},
+	}
+	hidden := axNode("9", "button", "Secret")
+	hidden.Ignored = true
+	return []*accessibility.Node{root, wrapper, heading, headingText, email, remember, hidden}
+}
+
+func TestFormatAXTree_InterestingOnly(t *testing.T) {
+	got := devbrowser.FormatAXTree(sampleAXTree(), "1", devbrowser.AXTreeOptions{InterestingOnly: true})
+	want := `RootWebArea "Login"
+  heading "Sign in" [level=1]
+  textbox "Email" [required, focusable] value="a@b.c"
+  checkbox "Remember me" [checked=false, focusable]
+`
+	if got != want {
+		t.Errorf("unexpected tree:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatAXTree_All(t *testing.T) {
+	got := devbrowser.FormatAXTree(sampleAXTree(), "1", devbrowser.AXTreeOptions{})
+	for _, want := range []string{
+		"\n  generic\n",
+		"\n      StaticText \"Sign in\"\n",
+		"\n  button (ignored) \"Secret\"\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in full tree:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatAXTree_SubtreeAndDepth(t *testing.T) {
+	got := devbrowser.FormatAXTree(sampleAXTree(), "2", devbrowser.AXTreeOptions{InterestingOnly: true, MaxDepth: 1})
+	if strings.Contains(got, "RootWebArea") {
+		t.Errorf("subtree must start at the given root:\n%s", got)
+	}
+	if !strings.HasPrefix(got, "heading \"Sign in\"") || strings.Count(got, "\n") != 3 {
+		t.Errorf("expected the three top-level controls of the wrapper, got:\n%s", got)
+	}
+}
+
+func TestFormatAXTree_Refs(t *testing.T) {
+	nodes := sampleAXTree()
+	for i, n := range nodes {
+		n.BackendDOMNodeID = cdp.BackendNodeID(i + 10)
+	}
+	got := devbrowser.FormatAXTree(nodes, "1", devbrowser.AXTreeOptions{
+		InterestingOnly: true,
+		Ref:             func(id cdp.BackendNodeID) string { return fmt.Sprintf("e%d", id-10) },
+	})
+	want := `RootWebArea "Login"
+  heading "Sign in" [level=1] ref:e2
+  textbox "Email" [required, focusable] value="a@b.c" ref:e4
+  checkbox "Remember me" [checked=false, focusable] ref:e5
+`
+	if got != want {
+		t.Errorf("unexpected tree:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGetAccessibilityTree(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/pay" {
+			fmt.Fprint(w, `<!DOCTYPE html><html><body><main><button>Pay now</button></main></body></html>`)
+			return
+		}
+		fmt.Fprint(w, `<!DOCTYPE html><html><head><title>Store</title></head><body>
+			<h1>Store</h1>
+			<form id="login" aria-label="Login">
+				<input aria-label="Email">
+				<button type="button">Sign in</button>
+			</form>
+			<iframe id="pay" src="/pay"></iframe>
+		</body></html>`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL), chromedp.WaitReady("iframe#pay")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+	chromedp.Run(db.Ctx, chromedp.Poll(`document.getElementById('pay').contentDocument?.querySelector('button') !== null`, nil))
+
+	tool := findTool(db.GetAccessibilityTools(), "browser_get_accessibility_tree")
+	// tree returns the outline without the Url/Root header.
+	tree := func(selector string) string {
+		t.Helper()
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(&devbrowser.GetAccessibilityTreeArgs{Selector: selector})},
+			Action: 'r',
+		})
+		if err != nil {
+			t.Fatalf("accessibility tree of %q failed: %v", selector, err)
+		}
+		got := resultText(res)
+		if selector != "" && !strings.Contains(got, "Root: "+selector+"\n") {
+			t.Errorf("expected the root %s in the header, got:\n%s", selector, got)
+		}
+		_, outline, _ := strings.Cut(got, "\n\n")
+		return outline
+	}
+	ref := func(outline, line string) string {
+		t.Helper()
+		m := regexp.MustCompile(regexp.QuoteMeta(line) + `.* (ref:e\d+)`).FindStringSubmatch(outline)
+		if m == nil {
+			t.Fatalf("expected a ref on %s, got:\n%s", line, outline)
+		}
+		return m[1]
+	}
+
+	page := tree("")
+	for _, want := range []string{`RootWebArea "Store"`, `heading "Store"`, `form "Login"`, `textbox "Email"`, `button "Sign in"`} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected %q in the page tree:\n%s", want, page)
+		}
+	}
+
+	form := tree("#login")
+	if !strings.HasPrefix(form, `form "Login"`) || strings.Contains(form, `heading "Store"`) {
+		t.Errorf("expected the tree to start at the form, got:\n%s", form)
+	}
+
+	signIn := ref(page, `button "Sign in"`)
+	if got := tree(signIn); !strings.HasPrefix(got, `button "Sign in"`) {
+		t.Errorf("expected %s to root the tree at the button, got:\n%s", signIn, got)
+	}
+
+	frame := tree("iframe#pay >>> main")
+	if !strings.HasPrefix(frame, "main") || !strings.Contains(frame, `button "Pay now"`) || strings.Contains(frame, "Store") {
+		t.Errorf("expected the tree of the frame's main, got:\n%s", frame)
+	}
+
+	// Refs handed out inside the frame resolve back into it.
+	pay := ref(frame, `button "Pay now"`)
+	if got := tree(pay); !strings.HasPrefix(got, `button "Pay now"`) {
+		t.Errorf("expected %s to root the tree at the frame's button, got:\n%s", pay, got)
+	}
+}
@@ -0,0 +1,210 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func TestAssert_InvalidArgs(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetAssertTools(), "browser_assert")
+	cases := []struct {
+		args    *devbrowser.AssertArgs
+		wantErr string
+	}{
+		{&devbrowser.AssertArgs{}, "type required"},
+		{&devbrowser.AssertArgs{Type: "colour", Selector: "#a"}, "unsupported type"},
+		{&devbrowser.AssertArgs{Type: "count", Selector: "li", Expected: "many"}, "count needs expected as a number"},
+		{&devbrowser.AssertArgs{Type: "count", Selector: "li", Op: "contains", Expected: "2"}, "unsupported op for count"},
+		{&devbrowser.AssertArgs{Type: "text", Op: "matches", Expected: "a("}, "invalid regexp"},
+		{&devbrowser.AssertArgs{Type: "attribute", Selector: "#a"}, "attribute needs a name"},
+		{&devbrowser.AssertArgs{Type: "url", Selector: "#a", Expected: "/"}, "url takes no selector"},
+		{&devbrowser.AssertArgs{Type: "no_console_errors", Not: true}, "cannot be negated"},
+		{&devbrowser.AssertArgs{Type: "visible", Selector: "#a", Since: "start"}, "since applies to"},
+		{&devbrowser.AssertArgs{Type: "exists", Selector: "label="}, "invalid locator"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.wantErr, func(t *testing.T) {
+			_, err := tool.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(tc.args)},
+				Action: 'r',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAssert(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/broken" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<h1 id="title" data-state="ready" style="color: rgb(255, 0, 0)">Order   saved</h1>
+				<ul><li>a</li><li>b</li><li>c</li></ul>
+				<input id="agree" type="checkbox" checked>
+				<p id="gone" style="display:none">hidden</p>
+				<script>localStorage.setItem('theme', 'dark');</script>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+	db.InitializeNetworkCapture()
+	if err := db.InitializeConsoleCapture(); err != nil {
+		t.Fatalf("failed to initialize console capture: %v", err)
+	}
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL+"/orders?id=7"), chromedp.WaitReady("#title")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	cases := []struct {
+		a      devbrowser.Assertion
+		pass   bool
+		actual string
+	}{
+		{devbrowser.Assertion{Type: "exists", Selector: "#title"}, true, "present"},
+		{devbrowser.Assertion{Type: "exists", Selector: "#nope", Not: true}, true, "absent"},
+		{devbrowser.Assertion{Type: "visible", Selector: "#gone"}, false, "present, hidden"},
+		{devbrowser.Assertion{Type: "count", Selector: "li", Expected: "3"}, true, "3"},
+		{devbrowser.Assertion{Type: "count", Selector: "text=b", Op: "at_least", Expected: "1"}, true, "1"},
+		{devbrowser.Assertion{Type: "text", Selector: "#title", Expected: "Order saved"}, true, `"Order saved"`},
+		{devbrowser.Assertion{Type: "text", Op: "matches", Expected: `Order\s+saved`}, true, ""},
+		{devbrowser.Assertion{Type: "text", Selector: "#title", Op: "contains", Expected: "cancelled"}, false, `"Order saved"`},
+		{devbrowser.Assertion{Type: "text", Selector: "#nope", Op: "contains", Expected: "x", Not: true}, false, "element not found: #nope"},
+		{devbrowser.Assertion{Type: "attribute", Selector: "#title", Name: "data-state", Expected: "ready"}, true, `"ready"`},
+		{devbrowser.Assertion{Type: "attribute", Selector: "#title", Name: "data-missing", Expected: "x"}, false, "(missing)"},
+		{devbrowser.Assertion{Type: "property", Selector: "#agree", Name: "checked", Expected: "true"}, true, `"true"`},
+		{devbrowser.Assertion{Type: "style", Selector: "#title", Name: "color", Expected: "rgb(255, 0, 0)"}, true, ""},
+		{devbrowser.Assertion{Type: "url", Expected: "/orders?id=7"}, true, `"/orders?id=7"`},
+		{devbrowser.Assertion{Type: "local_storage", Name: "theme", Expected: "dark"}, true, `"dark"`},
+		{devbrowser.Assertion{Type: "no_console_errors"}, true, "none"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.a.Type+" "+tc.a.Selector+tc.a.Name, func(t *testing.T) {
+			res, err := db.Assert(tc.a)
+			if err != nil {
+				t.Fatalf("Assert failed: %v", err)
+			}
+			if res.Pass != tc.pass || !strings.Contains(res.Actual, tc.actual) {
+				t.Errorf("expected pass=%v with actual containing %q, got:\n%s", tc.pass, tc.actual, res)
+			}
+		})
+	}
+
+	// Only a missing element is absent: a read that fails is an error, not a
+	// pass of the negated check.
+	for _, typ := range []string{"exists", "visible"} {
+		if res, err := db.Assert(devbrowser.Assertion{Type: typ, Selector: "#title >>> p", Not: true}); err == nil || !strings.Contains(err.Error(), "neither an iframe nor a shadow host") {
+			t.Errorf("expected %s to fail to read the element, got %v:\n%s", typ, err, res)
+		}
+	}
+
+	tool := findTool(db.GetAssertTools(), "browser_assert")
+	call := func(args *devbrowser.AssertArgs) *mcp.Result {
+		t.Helper()
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(args)},
+			Action: 'r',
+		})
+		if err != nil {
+			t.Fatalf("browser_assert failed: %v", err)
+		}
+		return res
+	}
+
+	call(&devbrowser.AssertArgs{Checkpoint: "before"})
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`console.error('bad thing'); fetch('/api/broken'); 0`, nil))
+	time.Sleep(300 * time.Millisecond)
+
+	res := call(&devbrowser.AssertArgs{Type: "no_console_errors", Since: "before"})
+	if got := resultText(res); !res.IsError || !strings.Contains(got, "FAIL console errors since checkpoint before") || !strings.Contains(got, "bad thing") {
+		t.Errorf("expected a failed console assertion, got:\n%s", got)
+	}
+	res = call(&devbrowser.AssertArgs{Type: "no_failed_requests", Since: "before"})
+	if got := resultText(res); !res.IsError || !strings.Contains(got, "500 GET "+ts.URL+"/api/broken") {
+		t.Errorf("expected a failed request assertion, got:\n%s", got)
+	}
+	res = call(&devbrowser.AssertArgs{Selector: "#title"})
+	if got := resultText(res); res.IsError || !strings.HasPrefix(got, "PASS #title\n  expected: visible") {
+		t.Errorf("expected a visible assertion by default, got:\n%s", got)
+	}
+}
+
+func TestAssert_FailedRequestsAcrossNavigation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<!DOCTYPE html><html><body><h1>%s</h1></body></html>`, r.URL.Path)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+	db.InitializeNetworkCapture()
+	if err := db.InitializeConsoleCapture(); err != nil {
+		t.Fatalf("failed to initialize console capture: %v", err)
+	}
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL+"/first")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+	tool := findTool(db.GetAssertTools(), "browser_assert")
+	if _, err := tool.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(&devbrowser.AssertArgs{Checkpoint: "before"})},
+		Action: 'r',
+	}); err != nil {
+		t.Fatalf("failed to save the checkpoint: %v", err)
+	}
+	if err := chromedp.Run(db.Ctx, chromedp.Evaluate(`fetch('/api/missing').then(r => r.status)`, nil, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	})); err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+
+	// The navigation clears the network log, not the failed requests.
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL+"/second")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+	res, err := db.Assert(devbrowser.Assertion{Type: "no_failed_requests", Since: "before"})
+	if err != nil {
+		t.Fatalf("Assert failed: %v", err)
+	}
+	if res.Pass || !strings.Contains(res.Actual, "404 GET "+ts.URL+"/api/missing") {
+		t.Errorf("expected the failed request from before the navigation, got:\n%s", res)
+	}
+}
@@ -0,0 +1,187 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/json"
+	"github.com/tinywasm/mcp"
+)
+
+func TestTruncateStructure(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("<body>\n  <h1> Orders\n  <ul class=\"list\">\n")
+	for i := 1; i <= 50; i++ {
+		fmt.Fprintf(&sb, "    <li id=\"row-%d\" class=\"row\"> Order %d\n      <a href=\"/orders/%d\" [clickable] ref:e%d> open\n", i, i, i, i)
+	}
+	sb.WriteString("  <footer> Total: 50\n")
+	structure := sb.String()
+
+	if got := devbrowser.TruncateStructure(structure, 0); got != structure {
+		t.Error("expected no limit with max_chars 0")
+	}
+	if got := devbrowser.TruncateStructure(structure, len(structure)); got != structure {
+		t.Error("expected a snapshot within budget to be unchanged")
+	}
+
+	got := devbrowser.TruncateStructure(structure, 600)
+	if len(got) > 600 {
+		t.Errorf("expected at most 600 characters, got %d:\n%s", len(got), got)
+	}
+	// Rows are collapsed while what follows the list survives.
+	for _, want := range []string{"<h1> Orders", "Order 1\n", "more <li>\n", "<footer> Total: 50"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Order 50") {
+		t.Errorf("expected the last rows to be collapsed:\n%s", got)
+	}
+
+	// A budget too small for collapsing alone cuts at a line boundary.
+	got = devbrowser.TruncateStructure(structure, 150)
+	if len([]rune(got)) > 150 || !strings.Contains(got, "truncated") {
+		t.Errorf("expected a cut snapshot with a note within 150 characters, got:\n%s", got)
+	}
+}
+
+func TestGetContent_Formats(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<article id="post">
+					<h1>Release notes</h1>
+					<p>Version <b>2.0</b> is out, see <a href="/docs">the docs</a>.</p>
+					<ul><li>Faster</li><li>Smaller</li></ul>
+				</article>
+				<form id="login" action="/login" method="post">
+					<label for="user">User</label><input id="user" name="user" value="ana" required>
+					<input type="password" name="pass" placeholder="Password" value="secret">
+					<select name="lang"><option>en</option><option selected>es</option></select>
+					<button type="submit">Sign in</button>
+				</form>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL), chromedp.WaitReady("#login")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	tool := findTool(db.GetStructureTools(), "browser_get_content")
+	run := func(args *devbrowser.GetContentArgs) string {
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(args)},
+			Action: byte(tool.Action),
+		})
+		if err != nil {
+			t.Fatalf("get_content %+v failed: %v", args, err)
+		}
+		var contents mcp.TextContentList
+		if err := json.Decode(string(res.Content), &contents); err != nil {
+			t.Fatal(err)
+		}
+		return contents[0].Text
+	}
+
+	markdown := run(&devbrowser.GetContentArgs{Format: "markdown", Selector: "#post"})
+	for _, want := range []string{"# Release notes", "Version **2.0** is out, see [the docs](" + ts.URL + "/docs).", "- Faster\n- Smaller"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("expected %q in markdown:\n%s", want, markdown)
+		}
+	}
+	if strings.Contains(markdown, "Sign in") {
+		t.Errorf("expected selector to root the markdown at #post:\n%s", markdown)
+	}
+
+	links := run(&devbrowser.GetContentArgs{Format: "links"})
+	if !strings.Contains(links, "- the docs → "+ts.URL+"/docs ref:e") {
+		t.Errorf("expected the docs link with a ref:\n%s", links)
+	}
+
+	forms := run(&devbrowser.GetContentArgs{Format: "forms"})
+	for _, want := range []string{"form#login POST " + ts.URL + "/login", `text "User" name=user value="ana" required`, `password "Password" name=pass value="••••"`, "options=[en, *es]", `button submit "Sign in"`} {
+		if !strings.Contains(forms, want) {
+			t.Errorf("expected %q in forms:\n%s", want, forms)
+		}
+	}
+
+	if _, err := tool.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(&devbrowser.GetContentArgs{Format: "text", Diff: true})},
+		Action: byte(tool.Action),
+	}); err == nil {
+		t.Error("expected diff to be rejected outside the structure format")
+	}
+}
+
+func TestGetContent_DiffRoots(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body><nav><a href="/">Home</a></nav><ul id="cart"><li>Tea</li></ul></body></html>`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL), chromedp.WaitReady("#cart")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	tool := findTool(db.GetStructureTools(), "browser_get_content")
+	run := func(args *devbrowser.GetContentArgs) (string, error) {
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(args)},
+			Action: byte(tool.Action),
+		})
+		if err != nil {
+			return "", err
+		}
+		return resultText(res), nil
+	}
+
+	if _, err := run(&devbrowser.GetContentArgs{Selector: "#cart", Checkpoint: "cart"}); err != nil {
+		t.Fatalf("get_content failed: %v", err)
+	}
+	// The whole page is not compared with a snapshot of #cart.
+	got, err := run(&devbrowser.GetContentArgs{Diff: true})
+	if err != nil {
+		t.Fatalf("get_content diff failed: %v", err)
+	}
+	if !strings.Contains(got, "The previous snapshot was of #cart, not of the whole page; full content:") || strings.Contains(got, "Changes since") {
+		t.Errorf("expected no diff across roots, got:\n%s", got)
+	}
+	if _, err := run(&devbrowser.GetContentArgs{Diff: true, Checkpoint: "cart"}); err == nil || !strings.Contains(err.Error(), `checkpoint "cart" is a snapshot of #cart, not of the whole page`) {
+		t.Errorf("expected the checkpoint of another root to be refused, got: %v", err)
+	}
+
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`document.getElementById('cart').insertAdjacentHTML('beforeend', '<li>Milk</li>'); 0`, nil))
+	got, err = run(&devbrowser.GetContentArgs{Selector: "#cart", Diff: true, Checkpoint: "cart"})
+	if err != nil {
+		t.Fatalf("get_content diff failed: %v", err)
+	}
+	if !strings.Contains(got, "Changes since checkpoint cart") || !strings.Contains(got, "Milk") || strings.Contains(got, "Home") {
+		t.Errorf("expected a diff of #cart only, got:\n%s", got)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tinywasm/devbrowser"
 	"github.com/tinywasm/json"
@@ -154,3 +156,72 @@ func TestMobileAudit_Tool(t *testing.T) {
 		t.Errorf("Expected tap-target violation for small-button, got: %s", report)
 	}
 }
+
+// TestMobileAudit_OutOfProcessFrame audits a sub-tree reached through a
+// cross-site iframe, which Chrome renders in its own process: the flagged
+// elements must still carry refs the other tools resolve.
+func TestMobileAudit_OutOfProcessFrame(t *testing.T) {
+	frame := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body>
+			<button id="tiny" style="width: 20px; height: 20px;" onclick="this.innerText='hit'">X</button>
+		</body></html>`)
+	}))
+	defer frame.Close()
+	// localhost and 127.0.0.1 are different sites.
+	frameURL := strings.Replace(frame.URL, "127.0.0.1", "localhost", 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<!DOCTYPE html><html><body><iframe id="pay" src="%s"></iframe></body></html>`, frameURL)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatal(err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := db.NavigateToURL(ts.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(name string, tools []mcp.Tool, args string) (string, error) {
+		tool := findTool(tools, name)
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: name, Arguments: args},
+			Action: byte(tool.Action),
+		})
+		if err != nil {
+			return "", err
+		}
+		var contents mcp.TextContentList
+		if err := json.Decode(string(res.Content), &contents); err != nil {
+			t.Fatal(err)
+		}
+		return contents[0].Text, nil
+	}
+
+	var report string
+	var err error
+	for i := 0; i < 50; i++ {
+		report, err = run("browser_audit_mobile", db.GetAuditTools(), encodeArgs(&devbrowser.AuditMobileArgs{Selector: "iframe#pay >>> body"}))
+		if err == nil && strings.Contains(report, "#tiny") {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("Failed to audit the frame: %v", err)
+	}
+
+	ref := regexp.MustCompile(`#tiny.* (ref:e\d+)`).FindStringSubmatch(report)
+	if ref == nil {
+		t.Fatalf("Expected a ref on the frame's tap-target violation, got: %s", report)
+	}
+	if _, err := run("browser_click_element", db.GetInteractionTools(), encodeArgs(&devbrowser.ClickElementArgs{Selector: ref[1], WaitAfter: 1})); err != nil {
+		t.Errorf("Failed to click %s from the audit: %v", ref[1], err)
+	}
+}
@@ -0,0 +1,160 @@
+package devbrowser_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func TestHandleDialog_InvalidArgs(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetDialogTools(), "browser_handle_dialog")
+	cases := []struct {
+		args    *devbrowser.HandleDialogArgs
+		wantErr string
+	}{
+		{&devbrowser.HandleDialogArgs{Action: "ok"}, "unsupported action"},
+		{&devbrowser.HandleDialogArgs{Policy: "ignore"}, "unsupported dialog policy"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.wantErr, func(t *testing.T) {
+			_, err := tool.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(tc.args)},
+				Action: 'u',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+	if p := db.GetDialogPolicy(); p.Action != "accept" {
+		t.Errorf("expected the accept policy by default, got %q", p.Action)
+	}
+}
+
+func TestWithDialogPolicy_Invalid(t *testing.T) {
+	db := devbrowser.New(defaultUI{}, &mockStore{data: map[string]string{}}, make(chan bool),
+		devbrowser.WithDialogPolicy(devbrowser.DialogPolicy{Action: "ignore"}))
+	if p := db.GetDialogPolicy(); p.Action != "accept" {
+		t.Errorf("expected an invalid policy to fall back to accept, got %q", p.Action)
+	}
+
+	logger, getLogs := logCapture()
+	db.SetLog(logger)
+	ctx, cancel := chromedp.NewContext(context.Background())
+	defer cancel()
+	db.Ctx = ctx
+	db.InitializeDialogCapture()
+	db.InitializeDialogCapture()
+
+	var reported int
+	for _, l := range getLogs() {
+		if strings.Contains(l, "unsupported dialog policy: ignore") {
+			reported++
+		}
+	}
+	if reported != 1 {
+		t.Errorf("expected the invalid policy to be logged once, got logs: %v", getLogs())
+	}
+}
+
+func TestHandleDialog(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body><p id="out"></p></body></html>`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+	db.InitializeDialogCapture()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	// open runs a dialog from a timer so that Evaluate does not block on it.
+	open := func(js string) {
+		t.Helper()
+		if err := chromedp.Run(db.Ctx, chromedp.Evaluate(`setTimeout(() => { window.r = String(`+js+`) }, 0); 0`, nil)); err != nil {
+			t.Fatalf("failed to open dialog: %v", err)
+		}
+	}
+	result := func() string {
+		t.Helper()
+		var r string
+		if err := chromedp.Run(db.Ctx, chromedp.Evaluate(`window.r || ''`, &r)); err != nil {
+			t.Fatalf("failed to read result: %v", err)
+		}
+		return r
+	}
+
+	// Default policy: accept, prompts get PromptText.
+	if err := db.SetDialogPolicy(devbrowser.DialogPolicy{Action: "accept", PromptText: "Ada"}); err != nil {
+		t.Fatalf("SetDialogPolicy failed: %v", err)
+	}
+	open(`prompt('Name?', 'Bob')`)
+	if _, err := db.WaitFor(devbrowser.WaitCondition{JS: `window.r === 'Ada'`, Timeout: 2 * time.Second}); err != nil {
+		t.Fatalf("prompt was not accepted with the policy text: %v", err)
+	}
+
+	// Manual policy: the dialog stays open until answered.
+	tool := findTool(db.GetDialogTools(), "browser_handle_dialog")
+	call := func(args *devbrowser.HandleDialogArgs) string {
+		t.Helper()
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(args)},
+			Action: 'u',
+		})
+		if err != nil {
+			t.Fatalf("browser_handle_dialog failed: %v", err)
+		}
+		return resultText(res)
+	}
+	if got := call(&devbrowser.HandleDialogArgs{Policy: "manual"}); !strings.Contains(got, "Policy: manual") {
+		t.Errorf("expected the manual policy, got:\n%s", got)
+	}
+	open(`confirm('Delete?')`)
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if d := db.GetDialogs(); len(d) == 2 && d[1].Response == "" {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	got := call(&devbrowser.HandleDialogArgs{})
+	if !strings.Contains(got, `Open dialog: confirm("Delete?")`) {
+		t.Fatalf("expected the confirm to be open, got:\n%s", got)
+	}
+	got = call(&devbrowser.HandleDialogArgs{Action: "dismiss"})
+	if !strings.Contains(got, `Answered confirm("Delete?"): dismissed`) {
+		t.Errorf("expected the confirm to be dismissed, got:\n%s", got)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if r := result(); r != "false" {
+		t.Errorf("expected confirm to return false, got %q", r)
+	}
+
+	dialogs := db.GetDialogs()
+	if len(dialogs) != 2 || dialogs[0].Type != "prompt" || dialogs[0].UserInput != "Ada" || dialogs[1].Response != "dismissed" {
+		t.Errorf("unexpected dialog log: %+v", dialogs)
+	}
+	if _, err := db.HandleDialog(true, ""); err == nil || !strings.Contains(err.Error(), "no dialog is open") {
+		t.Errorf("expected no dialog to be open, got: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/json"
+	"github.com/tinywasm/mcp"
+)
+
+func TestReplaceRefPlaceholders(t *testing.T) {
+	structure := "<button id=\"go\" [clickable] ref:@0>\n<a [clickable] ref:@1>\n<div [clickable] ref:@7>\n"
+	got := devbrowser.ReplaceRefPlaceholders(structure, []string{"e3", ""})
+	want := "<button id=\"go\" [clickable] ref:e3>\n<a [clickable]>\n<div [clickable]>\n"
+	if got != want {
+		t.Errorf("unexpected structure:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestElementRefs_UnknownAndInvalid(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	interaction := db.GetInteractionTools()
+	cases := []struct {
+		tool    *mcp.Tool
+		args    string
+		wantErr string
+	}{
+		{findTool(interaction, "browser_click_element"), encodeArgs(&devbrowser.ClickElementArgs{Selector: "ref:e7"}), "unknown ref e7"},
+		{findTool(interaction, "browser_fill_element"), encodeArgs(&devbrowser.FillElementArgs{Selector: "ref:e1", Value: "x"}), "unknown ref e1"},
+		{findTool(interaction, "browser_swipe_element"), encodeArgs(&devbrowser.SwipeElementArgs{Selector: "ref:e2", Direction: "up"}), "unknown ref e2"},
+		{findTool(db.GetInspectTools(), "browser_inspect_element"), encodeArgs(&devbrowser.InspectElementArgs{Selector: "ref:e3"}), "unknown ref e3"},
+		{findTool(db.GetSourceTools(), "browser_get_source"), encodeArgs(&devbrowser.GetSourceArgs{Selector: "ref:button"}), "invalid ref"},
+		{findTool(db.GetAccessibilityTools(), "browser_get_accessibility_tree"), encodeArgs(&devbrowser.GetAccessibilityTreeArgs{Selector: "ref:e"}), "invalid ref"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.tool.Name, func(t *testing.T) {
+			req := mcp.Request{
+				Params: mcp.CallToolParams{Name: tc.tool.Name, Arguments: tc.args},
+				Action: byte(tc.tool.Action),
+			}
+			_, err := tc.tool.Execute(nil, req)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestElementRefs_StableAndStale(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<div class="row"><button class="btn" onclick="this.innerText='first'">A</button></div>
+				<div class="row"><button class="btn" onclick="this.innerText='second'">B</button></div>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser(func(msg ...any) {
+		t.Log(msg...)
+	})
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	content := findTool(db.GetStructureTools(), "browser_get_content")
+	snapshot := func() string {
+		res, err := content.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: "browser_get_content", Arguments: encodeArgs(&devbrowser.GetContentArgs{})},
+			Action: 'r',
+		})
+		if err != nil {
+			t.Fatalf("get_content failed: %v", err)
+		}
+		var contents mcp.TextContentList
+		if err := json.Decode(string(res.Content), &contents); err != nil {
+			t.Fatal(err)
+		}
+		return contents[0].Text
+	}
+
+	buttonRef := regexp.MustCompile(`<button class="btn" \[clickable\] ref:(e\d+)> B`)
+	m := buttonRef.FindStringSubmatch(snapshot())
+	if m == nil {
+		t.Fatalf("second button has no ref in:\n%s", snapshot())
+	}
+	ref := "ref:" + m[1]
+
+	if again := buttonRef.FindStringSubmatch(snapshot()); again == nil || again[1] != m[1] {
+		t.Errorf("expected the button to keep %s across snapshots, got %v", m[1], again)
+	}
+
+	click := findTool(db.GetInteractionTools(), "browser_click_element")
+	clickRef := func() error {
+		_, err := click.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: "browser_click_element", Arguments: encodeArgs(&devbrowser.ClickElementArgs{Selector: ref, WaitAfter: 1})},
+			Action: 'u',
+		})
+		return err
+	}
+	if err := clickRef(); err != nil {
+		t.Fatalf("click by element ref failed: %v", err)
+	}
+	var texts []string
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`[...document.querySelectorAll('.btn')].map(b => b.innerText)`, &texts))
+	if len(texts) != 2 || texts[0] != "A" || texts[1] != "second" {
+		t.Errorf("expected only the second button clicked, got %v", texts)
+	}
+
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`document.querySelectorAll('.row')[1].remove()`, nil))
+	if err := clickRef(); err == nil || !strings.Contains(err.Error(), "stale") {
+		t.Errorf("expected stale ref error after removal, got: %v", err)
+	}
+
+	snapshot()
+	chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL))
+	m = buttonRef.FindStringSubmatch(snapshot())
+	if m == nil {
+		t.Fatal("button has no ref after reload")
+	}
+	ref = "ref:" + m[1]
+	chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL))
+	if err := clickRef(); err == nil || !strings.Contains(err.Error(), "navigated") {
+		t.Errorf("expected stale ref error after navigation, got: %v", err)
+	}
+}
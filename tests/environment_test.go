@@ -0,0 +1,153 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/cdproto/runtime"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func TestEmulateEnvironment_InvalidArgs(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetEnvironmentTools(), "browser_emulate_environment")
+	cases := []struct {
+		args    *devbrowser.EmulateEnvironmentArgs
+		wantErr string
+	}{
+		{&devbrowser.EmulateEnvironmentArgs{Geolocation: "Madrid"}, "invalid geolocation"},
+		{&devbrowser.EmulateEnvironmentArgs{Geolocation: "95,10"}, "geolocation out of range"},
+		{&devbrowser.EmulateEnvironmentArgs{Locale: "spanish-of-spain-please"}, "invalid locale"},
+		{&devbrowser.EmulateEnvironmentArgs{Timezone: "Europe Madrid"}, "invalid timezone"},
+		{&devbrowser.EmulateEnvironmentArgs{UaModel: "Pixel 7"}, "client hints need ua_platform"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.wantErr, func(t *testing.T) {
+			_, err := tool.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(tc.args)},
+				Action: 'u',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+	if env := db.GetEnvironment(); env != (devbrowser.Environment{}) {
+		t.Errorf("expected an invalid environment not to be kept, got %+v", env)
+	}
+}
+
+func TestEmulateEnvironment_Persisted(t *testing.T) {
+	store := &mockStore{data: map[string]string{}}
+	db := devbrowser.New(defaultUI{}, store, make(chan bool))
+	tool := findTool(db.GetEnvironmentTools(), "browser_emulate_environment")
+	call := func(args *devbrowser.EmulateEnvironmentArgs) string {
+		t.Helper()
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(args)},
+			Action: 'u',
+		})
+		if err != nil {
+			t.Fatalf("browser_emulate_environment failed: %v", err)
+		}
+		return resultText(res)
+	}
+
+	got := call(&devbrowser.EmulateEnvironmentArgs{Timezone: "Europe/Madrid", Locale: "es-ES", Geolocation: "40.4168,-3.7038"})
+	if got != "Environment: geolocation 40.4168,-3.7038, timezone Europe/Madrid, locale es-ES, Accept-Language es-ES,es;q=0.9 (applied when the browser opens)" {
+		t.Errorf("unexpected summary: %s", got)
+	}
+	call(&devbrowser.EmulateEnvironmentArgs{Geolocation: "default", UaPlatform: "Android", UaModel: "Pixel 7", UaMobile: true})
+
+	reopened := devbrowser.New(defaultUI{}, store, make(chan bool))
+	want := devbrowser.Environment{Timezone: "Europe/Madrid", Locale: "es-ES", UAPlatform: "Android", UAModel: "Pixel 7", UAMobile: true}
+	if env := reopened.GetEnvironment(); env != want {
+		t.Errorf("expected the environment to be loaded from the store, got %+v", env)
+	}
+
+	if got := call(&devbrowser.EmulateEnvironmentArgs{Reset: true}); got != "Environment: no environment emulation (applied when the browser opens)" {
+		t.Errorf("unexpected summary after reset: %s", got)
+	}
+	if store.data[devbrowser.StoreKeyEnvironment] != "" {
+		t.Errorf("expected the stored environment to be cleared, got %q", store.data[devbrowser.StoreKeyEnvironment])
+	}
+}
+
+func TestEmulateEnvironment(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<!DOCTYPE html><html><body><p id="lang">%s</p></body></html>`, r.Header.Get("Accept-Language"))
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	err := db.SetEnvironment(devbrowser.Environment{
+		Geolocation: "40.4168,-3.7038",
+		Timezone:    "Asia/Tokyo",
+		Locale:      "es-ES",
+		UserAgent:   "Mozilla/5.0 (X11; Linux x86_64) Chrome/120.0.0.0 DevBrowserTest",
+		UAPlatform:  "Linux",
+	})
+	if err != nil {
+		t.Fatalf("SetEnvironment failed: %v", err)
+	}
+	if err := chromedp.Run(db.Ctx, chromedp.Reload()); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+
+	var seen struct {
+		Lang     string  `json:"lang"`
+		Timezone string  `json:"timezone"`
+		Locale   string  `json:"locale"`
+		UA       string  `json:"ua"`
+		Lat      float64 `json:"lat"`
+		Lon      float64 `json:"lon"`
+	}
+	if err := chromedp.Run(db.Ctx, chromedp.Evaluate(`new Promise((resolve, reject) => navigator.geolocation.getCurrentPosition(p => resolve({
+		lang: document.getElementById('lang').textContent,
+		timezone: Intl.DateTimeFormat().resolvedOptions().timeZone,
+		locale: Intl.DateTimeFormat().resolvedOptions().locale,
+		ua: navigator.userAgent,
+		lat: p.coords.latitude,
+		lon: p.coords.longitude,
+	}), e => reject(new Error(e.message))))`, &seen, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	})); err != nil {
+		t.Fatalf("failed to read the environment: %v", err)
+	}
+	if seen.Lang != "es-ES,es;q=0.9" || seen.Timezone != "Asia/Tokyo" || seen.Locale != "es-ES" ||
+		!strings.HasSuffix(seen.UA, "DevBrowserTest") || seen.Lat != 40.4168 || seen.Lon != -3.7038 {
+		t.Errorf("environment not emulated: %+v", seen)
+	}
+
+	// Device emulation keeps the custom user agent.
+	tool := findTool(db.GetManagementTools(), "browser_emulate_device")
+	if _, err := tool.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(&devbrowser.EmulateDeviceArgs{Mode: "mobile"})},
+		Action: 'u',
+	}); err != nil {
+		t.Fatalf("browser_emulate_device failed: %v", err)
+	}
+	var ua string
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`navigator.userAgent`, &ua))
+	if !strings.HasSuffix(ua, "DevBrowserTest") {
+		t.Errorf("expected the custom user agent after device emulation, got %s", ua)
+	}
+}
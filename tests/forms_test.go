@@ -0,0 +1,162 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+	"github.com/tinywasm/model"
+)
+
+func TestFormTools_InvalidArgs(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	tools := db.GetFormTools()
+	cases := []struct {
+		tool    string
+		args    model.Encodable
+		wantErr string
+	}{
+		{"browser_select_option", &devbrowser.SelectOptionArgs{Selector: "#c", Value: "es", By: "index"}, "unsupported by"},
+		{"browser_upload_files", &devbrowser.UploadFilesArgs{Selector: "#f", Files: "../secret.txt"}, "path traversal"},
+		{"browser_upload_files", &devbrowser.UploadFilesArgs{Selector: "#f", Files: "no-such-file.png"}, "cannot upload"},
+		{"browser_upload_files", &devbrowser.UploadFilesArgs{Selector: "#f", Files: "\n \n"}, "files required"},
+		{"browser_fill_form", &devbrowser.FillFormArgs{Fields: `["email"]`}, "not an object"},
+		{"browser_fill_form", &devbrowser.FillFormArgs{Fields: `{"email": {"a": 1}}`}, "email: use a string"},
+		{"browser_fill_form", &devbrowser.FillFormArgs{Fields: `{"tags": ["a", true]}`}, "arrays hold strings"},
+		{"browser_fill_form", &devbrowser.FillFormArgs{Fields: `{}`}, "no fields"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.wantErr, func(t *testing.T) {
+			tool := findTool(tools, tc.tool)
+			_, err := tool.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(tc.args)},
+				Action: 'u',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFormTools(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<form id="signup">
+					<input name="email" type="email" value="old@example.com" required>
+					<select name="country">
+						<option value="">Choose</option>
+						<option value="es">Spain</option>
+						<option value="fr">France</option>
+					</select>
+					<label><input type="radio" name="plan" value="free" checked> Free</label>
+					<label><input type="radio" name="plan" value="pro"> Pro</label>
+					<label><input type="checkbox" id="terms" required> I agree</label>
+					<input type="file" id="avatar">
+					<input name="age" type="number" min="18">
+				</form>
+				<script>
+					window.changes = [];
+					document.addEventListener('change', e => window.changes.push(e.target.name || e.target.id));
+				</script>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	avatar := filepath.Join(t.TempDir(), "avatar.png")
+	if err := os.WriteFile(avatar, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL), chromedp.WaitReady("#signup")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	tools := db.GetFormTools()
+	run := func(name string, args model.Encodable) string {
+		t.Helper()
+		tool := findTool(tools, name)
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: name, Arguments: encodeArgs(args)},
+			Action: 'u',
+		})
+		if err != nil {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		return resultText(res)
+	}
+
+	if got := run("browser_select_option", &devbrowser.SelectOptionArgs{Selector: "select[name=country]", Value: "France", WaitAfter: 1}); !strings.Contains(got, "Selected France") {
+		t.Errorf("unexpected select result: %s", got)
+	}
+	if got := run("browser_check_element", &devbrowser.CheckElementArgs{Selector: "#terms", WaitAfter: 1}); !strings.Contains(got, "now checked") {
+		t.Errorf("unexpected check result: %s", got)
+	}
+	if got := run("browser_check_element", &devbrowser.CheckElementArgs{Selector: "#terms", WaitAfter: 1}); !strings.Contains(got, "already checked") {
+		t.Errorf("expected a second check to do nothing, got: %s", got)
+	}
+	if got := run("browser_upload_files", &devbrowser.UploadFilesArgs{Selector: "#avatar", Files: avatar, WaitAfter: 1}); !strings.Contains(got, "Uploaded 1 file") {
+		t.Errorf("unexpected upload result: %s", got)
+	}
+
+	got := run("browser_fill_form", &devbrowser.FillFormArgs{
+		Fields:    `{"email": "not-an-email", "country": "es", "plan": "pro", "#terms": false, "age": 12, "nickname": "x"}`,
+		WaitAfter: 1,
+		Timeout:   1000,
+	})
+	for _, want := range []string{
+		"Filled 5 of 6 fields, 3 invalid",
+		`invalid email = "not-an-email": `,
+		`ok      country = "es"`,
+		`ok      plan = "pro"`,
+		`invalid #terms = "unchecked": `,
+		`invalid age = "12": `,
+		"error   nickname: ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in fill_form report, got:\n%s", want, got)
+		}
+	}
+
+	var email, files string
+	var changes []string
+	chromedp.Run(db.Ctx,
+		chromedp.Value("input[name=email]", &email),
+		chromedp.Evaluate(`document.querySelector('#avatar').files[0].name`, &files),
+		chromedp.Evaluate(`window.changes`, &changes),
+	)
+	if email != "not-an-email" {
+		t.Errorf("expected the old email to be replaced, got %q", email)
+	}
+	if files != "avatar.png" {
+		t.Errorf("expected avatar.png to be uploaded, got %q", files)
+	}
+	for _, name := range []string{"country", "terms", "avatar", "plan"} {
+		if !strings.Contains(strings.Join(changes, " "), name) {
+			t.Errorf("expected a change event from %s, got %v", name, changes)
+		}
+	}
+}
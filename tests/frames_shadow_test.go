@@ -0,0 +1,156 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/json"
+	"github.com/tinywasm/mcp"
+)
+
+func TestSelectorPath_Invalid(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	click := findTool(db.GetInteractionTools(), "browser_click_element")
+	cases := []struct {
+		selector string
+		wantErr  string
+	}{
+		{"iframe#pay >>> ", "empty step"},
+		{">>> button", "empty step"},
+		{"iframe >>> ref:e1", "only the first step can be a ref"},
+		{"ref:e9 >>> button", "unknown ref e9"},
+		{"ref:x >>> button", "invalid ref"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.selector, func(t *testing.T) {
+			_, err := click.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: click.Name, Arguments: encodeArgs(&devbrowser.ClickElementArgs{Selector: tc.selector})},
+				Action: 'u',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestShadowDOMAndFrames(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/pay" {
+			fmt.Fprint(w, `<!DOCTYPE html><html><body>
+				<input name="card"><button class="submit" onclick="this.innerText='paid'">Pay</button>
+			</body></html>`)
+			return
+		}
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<my-widget id="w"></my-widget>
+				<iframe id="pay" src="/pay"></iframe>
+				<script>
+					customElements.define('my-widget', class extends HTMLElement {
+						constructor() {
+							super();
+							this.attachShadow({mode: 'open'}).innerHTML =
+								'<style>.inner { height: 10vh; }</style><button class="inner" onclick="this.innerText=\'hit\'">Shadow</button>';
+						}
+					});
+				</script>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser(func(msg ...any) {
+		t.Log(msg...)
+	})
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL), chromedp.WaitReady("iframe#pay")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+	chromedp.Run(db.Ctx, chromedp.Poll(`document.getElementById('pay').contentDocument?.querySelector('.submit') !== null`, nil))
+
+	run := func(tool *mcp.Tool, args string) (string, error) {
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: tool.Name, Arguments: args},
+			Action: byte(tool.Action),
+		})
+		if err != nil {
+			return "", err
+		}
+		var contents mcp.TextContentList
+		if err := json.Decode(string(res.Content), &contents); err != nil {
+			t.Fatal(err)
+		}
+		return contents[0].Text, nil
+	}
+
+	content, err := run(findTool(db.GetStructureTools(), "browser_get_content"), encodeArgs(&devbrowser.GetContentArgs{}))
+	if err != nil {
+		t.Fatalf("get_content failed: %v", err)
+	}
+	for _, want := range []string{"#shadow-root", `<button class="inner"`, "#document", `<button class="submit"`} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in structure:\n%s", want, content)
+		}
+	}
+
+	interaction := db.GetInteractionTools()
+	click := findTool(interaction, "browser_click_element")
+	if _, err := run(click, encodeArgs(&devbrowser.ClickElementArgs{Selector: "my-widget >>> button.inner", WaitAfter: 1})); err != nil {
+		t.Fatalf("click through shadow root failed: %v", err)
+	}
+	if _, err := run(click, encodeArgs(&devbrowser.ClickElementArgs{Selector: "iframe#pay >>> button.submit", WaitAfter: 1})); err != nil {
+		t.Fatalf("click through iframe failed: %v", err)
+	}
+	if _, err := run(findTool(interaction, "browser_fill_element"), encodeArgs(&devbrowser.FillElementArgs{Selector: "iframe#pay >>> input[name=card]", Value: "4242", WaitAfter: 1})); err != nil {
+		t.Fatalf("fill through iframe failed: %v", err)
+	}
+
+	var state []string
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`[
+		document.getElementById('w').shadowRoot.querySelector('button').innerText,
+		document.getElementById('pay').contentDocument.querySelector('button').innerText,
+		document.getElementById('pay').contentDocument.querySelector('input').value
+	]`, &state))
+	if len(state) != 3 || state[0] != "hit" || state[1] != "paid" || state[2] != "4242" {
+		t.Errorf("expected shadow button hit, iframe button paid and card filled, got %v", state)
+	}
+
+	inspect, err := run(findTool(db.GetInspectTools(), "browser_inspect_element"), encodeArgs(&devbrowser.InspectElementArgs{Selector: "my-widget >>> button"}))
+	if err != nil {
+		t.Fatalf("inspect through shadow root failed: %v", err)
+	}
+	if !strings.Contains(inspect, `"shadowHost": "my-widget#w"`) {
+		t.Errorf("expected shadow host in inspect output:\n%s", inspect)
+	}
+
+	source, err := run(findTool(db.GetSourceTools(), "browser_get_source"), encodeArgs(&devbrowser.GetSourceArgs{}))
+	if err != nil {
+		t.Fatalf("get_source failed: %v", err)
+	}
+	if !strings.Contains(source, `shadowrootmode="open"`) || !strings.Contains(source, `name="card"`) {
+		t.Errorf("expected shadow root and iframe document in source:\n%s", source)
+	}
+
+	_, err = run(click, encodeArgs(&devbrowser.ClickElementArgs{Selector: "body >>> button"}))
+	if err == nil || !strings.Contains(err.Error(), "neither an iframe nor a shadow host") {
+		t.Errorf("expected a path error for a plain element, got: %v", err)
+	}
+}
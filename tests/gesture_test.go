@@ -0,0 +1,116 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/devbrowser/chromedp/device"
+	"github.com/tinywasm/json"
+	"github.com/tinywasm/mcp"
+)
+
+func TestGesture_InvalidArgs(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	gesture := findTool(db.GetInteractionTools(), "browser_gesture")
+	cases := []struct {
+		args    *devbrowser.GestureArgs
+		wantErr string
+	}{
+		{&devbrowser.GestureArgs{Selector: "#a", Gesture: "wave"}, "unsupported gesture"},
+		{&devbrowser.GestureArgs{Selector: "#a", Gesture: "swipe", Direction: "sideways"}, "unsupported direction"},
+		{&devbrowser.GestureArgs{Selector: "#a", Gesture: "tap", Input: "pen"}, "unsupported input"},
+		{&devbrowser.GestureArgs{Selector: "#a", Gesture: "fling", Direction: "up", Duration: -5}, "must be positive"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.wantErr, func(t *testing.T) {
+			_, err := gesture.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: gesture.Name, Arguments: encodeArgs(tc.args)},
+				Action: 'u',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGesture_Touch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<div id="pad" style="width:300px;height:300px;margin:50px;background:#eee;touch-action:none"></div>
+				<script>
+					window.events = [];
+					const pad = document.getElementById('pad');
+					let start;
+					pad.addEventListener('touchstart', e => {
+						start = Array.from(e.touches).map(t => [t.clientX, t.clientY]);
+						window.events.push('start:' + e.touches.length);
+					});
+					pad.addEventListener('touchend', e => {
+						const end = Array.from(e.changedTouches).map(t => [t.clientX, t.clientY]);
+						const dx = Math.round(end[0][0] - start[0][0]);
+						window.events.push('end:' + e.changedTouches.length + (start.length === 1 ? ':dx=' + dx : ''));
+					});
+					pad.addEventListener('mousedown', () => window.events.push('mousedown'));
+				</script>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Emulate(device.IPhone12), chromedp.Navigate(ts.URL), chromedp.WaitReady("#pad")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	gesture := findTool(db.GetInteractionTools(), "browser_gesture")
+	run := func(args *devbrowser.GestureArgs) string {
+		res, err := gesture.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: gesture.Name, Arguments: encodeArgs(args)},
+			Action: 'u',
+		})
+		if err != nil {
+			t.Fatalf("%s failed: %v", args.Gesture, err)
+		}
+		var contents mcp.TextContentList
+		if err := json.Decode(string(res.Content), &contents); err != nil {
+			t.Fatal(err)
+		}
+		return contents[0].Text
+	}
+
+	if out := run(&devbrowser.GestureArgs{Selector: "#pad", Gesture: "swipe", Direction: "right", Distance: 100}); !strings.Contains(out, "touch input") {
+		t.Errorf("expected touch input under touch emulation, got: %s", out)
+	}
+	run(&devbrowser.GestureArgs{Selector: "#pad", Gesture: "pinch_out", Distance: 80})
+
+	var events []string
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`window.events`, &events))
+	// Touch end lists only the released finger, one event per finger.
+	want := "start:1 end:1:dx=100 start:1 start:2"
+	if got := strings.Join(events, " "); !strings.HasPrefix(got, want) {
+		t.Errorf("expected touch events starting with %q, got %q", want, got)
+	}
+	if strings.Contains(strings.Join(events, " "), "mousedown") {
+		t.Errorf("expected no mouse events from a swipe, got %v", events)
+	}
+}
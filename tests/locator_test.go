@@ -0,0 +1,150 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func TestLocator_Invalid(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	fill := findTool(db.GetInteractionTools(), "browser_fill_element")
+	cases := []struct {
+		selector string
+		wantErr  string
+	}{
+		{"text=", "empty value"},
+		{`text=""`, "empty value"},
+		{"role=[name=Save]", "empty role"},
+		{`role=button[name="Save"`, "missing ]"},
+		{"role=button[label=Save]", `use [name="..."]`},
+		{"my-app >>> label=", "empty value"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.selector, func(t *testing.T) {
+			_, err := fill.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: fill.Name, Arguments: encodeArgs(&devbrowser.FillElementArgs{Selector: tc.selector, Value: "x"})},
+				Action: 'u',
+			})
+			if err == nil || !strings.Contains(err.Error(), "invalid locator") || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected invalid locator error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLocators(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<div style="display:none"><button>Save</button></div>
+				<form>
+					<label for="mail">Email address</label>
+					<input id="mail">
+					<input id="q" placeholder="Search products">
+					<button type="button" data-testid="save-btn" onclick="window.clicked = (window.clicked || 0) + 1"><span>Save</span> draft</button>
+					<input type="submit" value="Send it" onclick="event.preventDefault(); window.sent = true">
+				</form>
+				<p>Don't "quote" me</p>
+				<my-card></my-card>
+				<script>
+					customElements.define('my-card', class extends HTMLElement {
+						constructor() {
+							super();
+							this.attachShadow({mode: 'open'}).innerHTML = '<button aria-label="Close card">x</button>';
+						}
+					});
+				</script>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL), chromedp.WaitReady("form")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	source := findTool(db.GetSourceTools(), "browser_get_source")
+	cases := []struct {
+		selector string
+		want     string
+	}{
+		{`role=button[name="Save draft"]`, `data-testid`},
+		{"role=button[name*=save]", `data-testid`},
+		{"text=save", "<span"},
+		{`text="Send it"`, `type="submit"`},
+		{`text=Don't "quote"`, "<p"},
+		{"label=email", `id="mail"`},
+		{"placeholder=search", `id="q"`},
+		{"testid=save-btn", "<button"},
+		{"xpath=//form/input[2]", `id="q"`},
+		{`my-card >>> role=button[name="Close card"]`, "aria-label"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.selector, func(t *testing.T) {
+			res, err := source.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: source.Name, Arguments: encodeArgs(&devbrowser.GetSourceArgs{Selector: tc.selector})},
+				Action: 'r',
+			})
+			if err != nil {
+				t.Fatalf("get_source failed: %v", err)
+			}
+			if got := resultText(res); !strings.Contains(got, tc.want) {
+				t.Errorf("expected %q in:\n%s", tc.want, got)
+			}
+		})
+	}
+
+	interaction := db.GetInteractionTools()
+	fill := findTool(interaction, "browser_fill_element")
+	if _, err := fill.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: fill.Name, Arguments: encodeArgs(&devbrowser.FillElementArgs{Selector: "label=Email address", Value: "a@b.c", WaitAfter: 1})},
+		Action: 'u',
+	}); err != nil {
+		t.Fatalf("fill by label failed: %v", err)
+	}
+	click := findTool(interaction, "browser_click_element")
+	if _, err := click.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: click.Name, Arguments: encodeArgs(&devbrowser.ClickElementArgs{Selector: `role=button[name="Save draft"]`, WaitAfter: 1})},
+		Action: 'u',
+	}); err != nil {
+		t.Fatalf("click by role failed: %v", err)
+	}
+
+	var value string
+	var clicked int
+	chromedp.Run(db.Ctx, chromedp.Value("#mail", &value), chromedp.Evaluate(`window.clicked`, &clicked))
+	if value != "a@b.c" {
+		t.Errorf("expected the labelled field to be filled, got %q", value)
+	}
+	if clicked != 1 {
+		t.Errorf("expected one click on the button, got %d", clicked)
+	}
+
+	if _, err := source.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: source.Name, Arguments: encodeArgs(&devbrowser.GetSourceArgs{Selector: "text=Nothing like this"})},
+		Action: 'r',
+	}); err == nil || !strings.Contains(err.Error(), "element not found: text=Nothing like this") {
+		t.Errorf("expected element not found, got: %v", err)
+	}
+}
@@ -13,12 +13,27 @@ func TestGetMCPToolsMetadata_AllToolsRegistered(t *testing.T) {
 	expectedToolNames := []string{
 		"browser_get_console",
 		"browser_emulate_device",
+		"browser_emulate_environment",
+		"browser_emulate_media",
 		"browser_screenshot",
 		"browser_get_content",
 		"browser_click_element",
+		"browser_hover_element",
+		"browser_drag_element",
 		"browser_fill_element",
 		"browser_navigate",
+		"browser_reload_assets",
 		"browser_swipe_element",
+		"browser_press_key",
+		"browser_gesture",
+		"browser_select_option",
+		"browser_check_element",
+		"browser_upload_files",
+		"browser_fill_form",
+		"browser_wait_for",
+		"browser_assert",
+		"browser_run_steps",
+		"browser_handle_dialog",
 		"browser_inspect_element",
 		"browser_get_performance",
 		"browser_get_network_logs",
@@ -31,6 +46,11 @@ func TestGetMCPToolsMetadata_AllToolsRegistered(t *testing.T) {
 		"browser_intercept_request",
 		"browser_save_screenshot",
 		"browser_audit_mobile",
+		"browser_screenshot_matrix",
+		"browser_record_start",
+		"browser_record_stop",
+		"browser_screenshot_annotated",
+		"browser_get_accessibility_tree",
 	}
 
 	if len(tools) != len(expectedToolNames) {
@@ -0,0 +1,218 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+	"github.com/tinywasm/model"
+)
+
+func TestParseMedia(t *testing.T) {
+	m, err := devbrowser.ParseMedia("dark+reduced-motion+print")
+	if err != nil {
+		t.Fatalf("ParseMedia failed: %v", err)
+	}
+	want := devbrowser.Media{ColorScheme: "dark", ReducedMotion: "reduce", Type: "print"}
+	if m != want {
+		t.Errorf("expected %+v, got %+v", want, m)
+	}
+	if got := m.String(); got != "prefers-color-scheme: dark, prefers-reduced-motion: reduce, media type: print" {
+		t.Errorf("unexpected summary: %s", got)
+	}
+	if m, err := devbrowser.ParseMedia("dark+Protanopia"); err != nil || m != (devbrowser.Media{ColorScheme: "dark", Vision: "protanopia"}) {
+		t.Errorf("expected dark with protanopia, got %+v, %v", m, err)
+	}
+	if _, err := devbrowser.ParseMedia("dark+sepia"); err == nil || !strings.Contains(err.Error(), "unsupported media: sepia") {
+		t.Errorf("expected an unsupported media error, got: %v", err)
+	}
+}
+
+func TestEmulateMedia_InvalidArgs(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	media := findTool(db.GetMediaTools(), "browser_emulate_media")
+	shot := findTool(db.GetScreenshotTools(), "browser_screenshot")
+	matrix := findTool(db.GetScreenshotMatrixTools(), "browser_screenshot_matrix")
+	cases := []struct {
+		tool    *mcp.Tool
+		args    model.Encodable
+		wantErr string
+	}{
+		{media, &devbrowser.EmulateMediaArgs{ColorScheme: "sepia"}, "unsupported prefers-color-scheme: sepia. Use light, dark or no-preference"},
+		{media, &devbrowser.EmulateMediaArgs{MediaType: "tv"}, "unsupported media type: tv"},
+		{media, &devbrowser.EmulateMediaArgs{Vision: "myopia"}, "unsupported vision deficiency: myopia"},
+		{shot, &devbrowser.ScreenshotArgs{Media: "darkest"}, "unsupported media: darkest"},
+		{matrix, &devbrowser.ScreenshotMatrixArgs{Targets: "mobile", Media: "light, night"}, "unsupported media: night"},
+		{matrix, &devbrowser.ScreenshotMatrixArgs{Targets: "mobile", Media: "dark+print, light, print+dark"}, "duplicate media: print+dark (already listed as dark+print)"},
+		{matrix, &devbrowser.ScreenshotMatrixArgs{Targets: "mobile", Media: "dark+vision-deficiencies"}, "vision-deficiencies cannot be combined with +"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.wantErr, func(t *testing.T) {
+			_, err := tc.tool.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: tc.tool.Name, Arguments: encodeArgs(tc.args)},
+				Action: 'u',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+	if m := db.GetMedia(); m != (devbrowser.Media{}) {
+		t.Errorf("expected invalid media not to be kept, got %+v", m)
+	}
+}
+
+func TestEmulateMedia_Persisted(t *testing.T) {
+	store := &mockStore{data: map[string]string{}}
+	db := devbrowser.New(defaultUI{}, store, make(chan bool))
+	tool := findTool(db.GetMediaTools(), "browser_emulate_media")
+	call := func(args *devbrowser.EmulateMediaArgs) string {
+		t.Helper()
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(args)},
+			Action: 'u',
+		})
+		if err != nil {
+			t.Fatalf("browser_emulate_media failed: %v", err)
+		}
+		return resultText(res)
+	}
+
+	call(&devbrowser.EmulateMediaArgs{ColorScheme: "dark", ReducedMotion: "reduce"})
+	got := call(&devbrowser.EmulateMediaArgs{ReducedMotion: "default", ForcedColors: "active"})
+	if got != "Media: prefers-color-scheme: dark, forced-colors: active (applied when the browser opens)" {
+		t.Errorf("unexpected summary: %s", got)
+	}
+
+	reopened := devbrowser.New(defaultUI{}, store, make(chan bool))
+	if m := reopened.GetMedia(); m != (devbrowser.Media{ColorScheme: "dark", ForcedColors: "active"}) {
+		t.Errorf("expected the media to be loaded from the store, got %+v", m)
+	}
+
+	if got := call(&devbrowser.EmulateMediaArgs{Reset: true}); got != "Media: no media emulation (applied when the browser opens)" {
+		t.Errorf("unexpected summary after reset: %s", got)
+	}
+}
+
+func TestEmulateMedia(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><head><style>
+			body { background: rgb(255, 255, 255); }
+			@media (prefers-color-scheme: dark) { body { background: rgb(0, 0, 0); } }
+		</style></head><body><h1>Media</h1></body></html>`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	tool := findTool(db.GetMediaTools(), "browser_emulate_media")
+	res, err := tool.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(&devbrowser.EmulateMediaArgs{ColorScheme: "dark", ReducedMotion: "reduce"})},
+		Action: 'u',
+	})
+	if err != nil {
+		t.Fatalf("browser_emulate_media failed: %v", err)
+	}
+	if got := resultText(res); !strings.Contains(got, "Page matches: prefers-color-scheme: dark, prefers-reduced-motion: reduce") {
+		t.Errorf("expected the page to match the emulated media, got:\n%s", got)
+	}
+
+	background := func() string {
+		t.Helper()
+		var bg string
+		if err := chromedp.Run(db.Ctx, chromedp.Evaluate(`getComputedStyle(document.body).backgroundColor`, &bg)); err != nil {
+			t.Fatalf("failed to read the background: %v", err)
+		}
+		return bg
+	}
+	if bg := background(); bg != "rgb(0, 0, 0)" {
+		t.Errorf("expected the dark background, got %s", bg)
+	}
+
+	// A per-shot override leaves the emulated media in place.
+	if _, err := db.CaptureScreenshotWithOptions(devbrowser.ScreenshotOptions{Media: "light"}); err != nil {
+		t.Fatalf("screenshot with media failed: %v", err)
+	}
+	if bg := background(); bg != "rgb(0, 0, 0)" {
+		t.Errorf("expected the dark background back after the capture, got %s", bg)
+	}
+
+	matrix := findTool(db.GetScreenshotMatrixTools(), "browser_screenshot_matrix")
+	res, err = matrix.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: matrix.Name, Arguments: encodeArgs(&devbrowser.ScreenshotMatrixArgs{Targets: "mobile, desktop", Media: "light, dark"})},
+		Action: 'c',
+	})
+	if err != nil {
+		t.Fatalf("browser_screenshot_matrix failed: %v", err)
+	}
+	got := resultText(res)
+	for _, want := range []string{"4 captures", "1. mobile light", "2. mobile dark", "3. desktop light", "4. desktop dark"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, got)
+		}
+	}
+	if bg := background(); bg != "rgb(0, 0, 0)" {
+		t.Errorf("expected the dark background back after the matrix, got %s", bg)
+	}
+}
+
+func TestScreenshotMatrix_VisionDeficiencies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body><button style="background: red; color: green">Pay</button></body></html>`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+	if err := db.SetMedia(devbrowser.Media{Vision: "tritanopia"}); err != nil {
+		t.Fatalf("SetMedia failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	matrix := findTool(db.GetScreenshotMatrixTools(), "browser_screenshot_matrix")
+	res, err := matrix.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: matrix.Name, Arguments: encodeArgs(&devbrowser.ScreenshotMatrixArgs{
+			Targets: "desktop", Media: "vision-deficiencies", Dir: dir, Name: "pay", ContactSheet: true,
+		})},
+		Action: 'c',
+	})
+	if err != nil {
+		t.Fatalf("browser_screenshot_matrix failed: %v", err)
+	}
+	got := resultText(res)
+	for _, want := range []string{"7 captures", "1. desktop normal-vision", "2. desktop protanopia", "7. desktop reduced-contrast", "pay-desktop-blurredvision.png", "Contact sheet -> "} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, got)
+		}
+	}
+	if m := db.GetMedia(); m.Vision != "tritanopia" {
+		t.Errorf("expected the emulated vision deficiency to be kept, got %+v", m)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/tinywasm/devbrowser/chromedp"
 	"github.com/tinywasm/mcp"
@@ -63,3 +66,119 @@ func TestBrowserNavigation(t *testing.T) {
 		t.Fatalf("Failed to get title: %v", err)
 	}
 }
+
+func TestNavigate_InvalidArgs(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	db.LastPort = "8080"
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetNavigationTools(), "browser_navigate")
+	cases := []struct {
+		args    *devbrowser.NavigateArgs
+		wantErr string
+	}{
+		{&devbrowser.NavigateArgs{}, "url or action required"},
+		{&devbrowser.NavigateArgs{Action: "up"}, "unsupported action"},
+		{&devbrowser.NavigateArgs{Url: "/a", Action: "back"}, "back takes no url"},
+		{&devbrowser.NavigateArgs{Url: "/a", WaitUntil: "idle"}, "unsupported wait_until"},
+		{&devbrowser.NavigateArgs{Url: "/a", WaitUntil: "selector"}, "needs a selector"},
+		{&devbrowser.NavigateArgs{Url: "/a", WaitUntil: "load", Selector: "#a"}, "cannot be combined"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.wantErr, func(t *testing.T) {
+			_, err := tool.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(tc.args)},
+				Action: 'u',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNavigate_HistoryAndRedirects(t *testing.T) {
+	var loads atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/moved", http.StatusMovedPermanently)
+			return
+		case "/moved":
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		case "/slow":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<p>loading</p><script>setTimeout(() => { document.body.insertAdjacentHTML('beforeend', '<p id="late">late</p>') }, 300)</script>`)
+			return
+		}
+		loads.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Cache-Control", "max-age=3600")
+		fmt.Fprintf(w, "<h1>%s</h1>", r.URL.Path)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+	db.InitializeNetworkCapture()
+
+	nav := func(n devbrowser.Navigation) devbrowser.NavigationResult {
+		t.Helper()
+		res, err := db.Navigate(n)
+		if err != nil {
+			t.Fatalf("Navigate %+v failed: %v", n, err)
+		}
+		return res
+	}
+
+	res := nav(devbrowser.Navigation{URL: ts.URL + "/old"})
+	if res.URL != ts.URL+"/new" || res.Status != 200 || len(res.Redirects) != 2 ||
+		res.Redirects[0] != (devbrowser.Redirect{Status: 301, URL: ts.URL + "/old"}) ||
+		res.Redirects[1] != (devbrowser.Redirect{Status: 302, URL: ts.URL + "/moved"}) {
+		t.Errorf("unexpected redirect result:\n%s", res)
+	}
+	if got := res.String(); !strings.Contains(got, "Redirects: 301 "+ts.URL+"/old -> 302 "+ts.URL+"/moved -> "+ts.URL+"/new") {
+		t.Errorf("unexpected redirect chain:\n%s", got)
+	}
+
+	if res := nav(devbrowser.Navigation{URL: ts.URL + "/missing"}); res.Status != 404 {
+		t.Errorf("expected a 404 status, got:\n%s", res)
+	}
+
+	if res := nav(devbrowser.Navigation{Action: "back"}); res.URL != ts.URL+"/new" {
+		t.Errorf("expected to go back to /new, got:\n%s", res)
+	}
+	if res := nav(devbrowser.Navigation{Action: "forward"}); res.URL != ts.URL+"/missing" || res.Status != 404 {
+		t.Errorf("expected to go forward to /missing, got:\n%s", res)
+	}
+	if _, err := db.Navigate(devbrowser.Navigation{Action: "forward"}); err == nil || !strings.Contains(err.Error(), "no page to go forward to") {
+		t.Errorf("expected no forward history, got: %v", err)
+	}
+
+	nav(devbrowser.Navigation{URL: ts.URL + "/cached"})
+	before := loads.Load()
+	nav(devbrowser.Navigation{Action: "hard_reload"})
+	if loads.Load() != before+1 {
+		t.Errorf("expected a hard reload to reach the server")
+	}
+
+	res = nav(devbrowser.Navigation{URL: ts.URL + "/slow", Selector: "#late", Timeout: 5 * time.Second})
+	if res.Waited != "#late visible" {
+		t.Errorf("expected to wait for #late, got:\n%s", res)
+	}
+	if _, err := db.Navigate(devbrowser.Navigation{URL: ts.URL + "/slow", Selector: "#never", Timeout: time.Second}); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout waiting for #never, got: %v", err)
+	}
+	if res := nav(devbrowser.Navigation{URL: ts.URL + "/slow", WaitUntil: "network_idle"}); !strings.Contains(res.Waited, "network idle") {
+		t.Errorf("expected to wait for network idle, got:\n%s", res)
+	}
+}
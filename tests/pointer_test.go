@@ -0,0 +1,116 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func TestPointer_InvalidArgs(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	tools := db.GetInteractionTools()
+	cases := []struct {
+		tool    string
+		args    string
+		wantErr string
+	}{
+		{"browser_click_element", encodeArgs(&devbrowser.ClickElementArgs{Selector: "#a", Button: "back"}), "unsupported button"},
+		{"browser_click_element", encodeArgs(&devbrowser.ClickElementArgs{Selector: "#a", ClickCount: 4}), "click_count must be between 1 and 3"},
+		{"browser_drag_element", encodeArgs(&devbrowser.DragElementArgs{Selector: "#a"}), "to or to_x/to_y required"},
+		{"browser_drag_element", encodeArgs(&devbrowser.DragElementArgs{Selector: "#a", To: "#b", ToX: 10}), "not both"},
+		{"browser_drag_element", encodeArgs(&devbrowser.DragElementArgs{Selector: "#a", To: "ref:e9"}), "unknown ref e9"},
+		{"browser_hover_element", encodeArgs(&devbrowser.HoverElementArgs{}), "selector or ref required"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.tool+" "+tc.wantErr, func(t *testing.T) {
+			tool := findTool(tools, tc.tool)
+			_, err := tool.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: tool.Name, Arguments: tc.args},
+				Action: 'u',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPointerActions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<button id="target" style="width:100px;height:40px">Target</button>
+				<div id="card" draggable="true" style="width:80px;height:40px;background:#ccc">Card</div>
+				<div id="bin" style="width:200px;height:100px;margin-top:40px;border:1px solid">Bin</div>
+				<script>
+					window.events = [];
+					const log = e => window.events.push(e.type + (e.detail ? ':' + e.detail : ''));
+					const target = document.getElementById('target');
+					['mouseenter', 'dblclick', 'contextmenu'].forEach(t => target.addEventListener(t, log));
+					let down = 0;
+					target.addEventListener('mousedown', () => down = performance.now());
+					target.addEventListener('mouseup', () => {
+						if (performance.now() - down > 400) window.events.push('longpress');
+					});
+					const bin = document.getElementById('bin');
+					bin.addEventListener('dragover', e => e.preventDefault());
+					bin.addEventListener('drop', e => { e.preventDefault(); window.events.push('drop'); });
+				</script>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL), chromedp.WaitReady("#bin")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	tools := db.GetInteractionTools()
+	run := func(name, args string) string {
+		tool := findTool(tools, name)
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: tool.Name, Arguments: args},
+			Action: 'u',
+		})
+		if err != nil {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		return string(res.Content)
+	}
+
+	run("browser_hover_element", encodeArgs(&devbrowser.HoverElementArgs{Selector: "#target"}))
+	run("browser_click_element", encodeArgs(&devbrowser.ClickElementArgs{Selector: "#target", ClickCount: 2}))
+	run("browser_click_element", encodeArgs(&devbrowser.ClickElementArgs{Selector: "#target", Button: "right"}))
+	// A hold longer than the timeout extends it.
+	run("browser_click_element", encodeArgs(&devbrowser.ClickElementArgs{Selector: "#target", Hold: 500, Timeout: 300}))
+	if out := run("browser_drag_element", encodeArgs(&devbrowser.DragElementArgs{Selector: "#card", To: "#bin", Hold: 1200, Timeout: 1000})); !strings.Contains(out, "HTML5") {
+		t.Errorf("expected an HTML5 drag, got: %s", out)
+	}
+
+	var events []string
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`window.events`, &events))
+	want := "mouseenter dblclick:2 contextmenu longpress drop"
+	if got := strings.Join(events, " "); got != want {
+		t.Errorf("expected events %q, got %q", want, got)
+	}
+}
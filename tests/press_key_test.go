@@ -0,0 +1,98 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func TestPressKey_InvalidKeys(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	press := findTool(db.GetInteractionTools(), "browser_press_key")
+	cases := []struct {
+		keys    string
+		wantErr string
+	}{
+		{"  ", "keys required"},
+		{"Control+Foo", `unknown key "Foo"`},
+		{"K+Control", "K is not a modifier"},
+		{"Shift+", "empty key"},
+		{"Tab Control++ Enterr", `unknown key "Enterr"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.keys, func(t *testing.T) {
+			_, err := press.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: press.Name, Arguments: encodeArgs(&devbrowser.PressKeyArgs{Keys: tc.keys})},
+				Action: 'u',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPressKey(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<input id="q">
+				<script>
+					window.keys = [];
+					document.addEventListener('keydown', e => {
+						const mods = ['ctrlKey', 'shiftKey', 'altKey', 'metaKey'].filter(m => e[m]).map(m => m.replace('Key', ''));
+						window.keys.push(mods.concat(e.key).join('+'));
+					});
+				</script>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL), chromedp.WaitReady("#q")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	press := findTool(db.GetInteractionTools(), "browser_press_key")
+	_, err := press.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: press.Name, Arguments: encodeArgs(&devbrowser.PressKeyArgs{Keys: "h i Control+Shift+p Escape", Selector: "#q"})},
+		Action: 'u',
+	})
+	if err != nil {
+		t.Fatalf("press_key failed: %v", err)
+	}
+
+	var keys []string
+	var value string
+	chromedp.Run(db.Ctx,
+		chromedp.Evaluate(`window.keys`, &keys),
+		chromedp.Value("#q", &value),
+	)
+	want := "h i ctrl+Control ctrl+shift+Shift ctrl+shift+P Escape"
+	if got := strings.Join(keys, " "); got != want {
+		t.Errorf("expected keydown events %q, got %q", want, got)
+	}
+	if value != "hi" {
+		t.Errorf("expected the shortcut to type nothing, got value %q", value)
+	}
+}
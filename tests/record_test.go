@@ -0,0 +1,279 @@
+package devbrowser_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func testAnimationFrames(t *testing.T) []devbrowser.AnimationFrame {
+	t.Helper()
+	return []devbrowser.AnimationFrame{
+		{Data: encodeTestPNG(t, 40, 30, color.RGBA{255, 0, 0, 255}), Delay: 250 * time.Millisecond},
+		{Data: encodeTestPNG(t, 40, 30, color.RGBA{0, 255, 0, 255}), Delay: 100 * time.Millisecond},
+		{Data: encodeTestPNG(t, 40, 30, color.RGBA{0, 0, 255, 255}), Delay: 1 * time.Second},
+	}
+}
+
+func TestEncodeGIF_FramesAndDelays(t *testing.T) {
+	data, err := devbrowser.EncodeGIF(testAnimationFrames(t))
+	if err != nil {
+		t.Fatalf("EncodeGIF failed: %v", err)
+	}
+
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("output is not a GIF: %v", err)
+	}
+	if len(anim.Image) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(anim.Image))
+	}
+	want := []int{25, 10, 100}
+	for i, d := range anim.Delay {
+		if d != want[i] {
+			t.Errorf("frame %d: expected delay %d, got %d", i+1, want[i], d)
+		}
+	}
+	if b := anim.Image[0].Bounds(); b.Dx() != 40 || b.Dy() != 30 {
+		t.Errorf("expected 40x30 frames, got %dx%d", b.Dx(), b.Dy())
+	}
+}
+
+func TestEncodeAPNG_Chunks(t *testing.T) {
+	data, err := devbrowser.EncodeAPNG(testAnimationFrames(t))
+	if err != nil {
+		t.Fatalf("EncodeAPNG failed: %v", err)
+	}
+
+	// Viewers without APNG support must still see the first frame.
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("output is not a valid PNG: %v", err)
+	}
+	if r, g, b, _ := img.At(10, 10).RGBA(); r>>8 != 255 || g != 0 || b != 0 {
+		t.Errorf("expected red default image, got %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+
+	counts := map[string]int{}
+	var numFrames uint32
+	var delays []uint16
+	rest := data[8:]
+	for len(rest) >= 12 {
+		n := int(binary.BigEndian.Uint32(rest))
+		typ := string(rest[4:8])
+		payload := rest[8 : 8+n]
+		counts[typ]++
+		switch typ {
+		case "acTL":
+			numFrames = binary.BigEndian.Uint32(payload)
+		case "fcTL":
+			delays = append(delays, binary.BigEndian.Uint16(payload[20:]))
+		}
+		rest = rest[12+n:]
+	}
+
+	if numFrames != 3 || counts["acTL"] != 1 {
+		t.Errorf("expected one acTL announcing 3 frames, got %d acTL with %d frames", counts["acTL"], numFrames)
+	}
+	if counts["fcTL"] != 3 || counts["fdAT"] != 2 || counts["IDAT"] != 1 {
+		t.Errorf("unexpected chunk counts: %v", counts)
+	}
+	want := []uint16{250, 100, 1000}
+	for i := range want {
+		if i >= len(delays) || delays[i] != want[i] {
+			t.Errorf("expected fcTL delays %v (ms), got %v", want, delays)
+			break
+		}
+	}
+}
+
+func TestEncodeAnimation_RejectsEmptyInput(t *testing.T) {
+	if _, err := devbrowser.EncodeGIF(nil); err == nil {
+		t.Error("expected EncodeGIF error for no frames")
+	}
+	if _, err := devbrowser.EncodeAPNG(nil); err == nil {
+		t.Error("expected EncodeAPNG error for no frames")
+	}
+}
+
+func TestRecordStop_WithoutRecording(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetRecordTools(), "browser_record_stop")
+	if tool == nil {
+		t.Fatal("browser_record_stop tool is not registered")
+	}
+	args := devbrowser.RecordStopArgs{Dir: t.TempDir(), Encode: "gif"}
+	req := mcp.Request{
+		Params: mcp.CallToolParams{
+			Name:      "browser_record_stop",
+			Arguments: encodeArgs(&args),
+		},
+		Action: 'c',
+	}
+
+	_, err := tool.Execute(nil, req)
+	if err == nil || !strings.Contains(err.Error(), "no recording in progress") {
+		t.Errorf("expected 'no recording in progress' error, got: %v", err)
+	}
+}
+
+func TestRecordStop_UnknownEncode(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetRecordTools(), "browser_record_stop")
+	args := devbrowser.RecordStopArgs{Dir: t.TempDir(), Encode: "mp4"}
+	req := mcp.Request{
+		Params: mcp.CallToolParams{
+			Name:      "browser_record_stop",
+			Arguments: encodeArgs(&args),
+		},
+		Action: 'c',
+	}
+
+	_, err := tool.Execute(nil, req)
+	if err == nil || !strings.Contains(err.Error(), "Unknown encode: mp4") {
+		t.Errorf("expected unknown encode error, got: %v", err)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body>
+			<div id="box" style="width:200px;height:200px"></div>
+			<script>
+				// Repaint on every animation frame so the screencast keeps sending.
+				let n = 0;
+				(function paint() {
+					document.getElementById('box').style.background = 'hsl(' + (n++ * 7 % 360) + ', 80%, 50%)';
+					requestAnimationFrame(paint);
+				})();
+			</script>
+		</body></html>`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	start := findTool(db.GetRecordTools(), "browser_record_start")
+	if _, err := start.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: start.Name, Arguments: encodeArgs(&devbrowser.RecordStartArgs{Fps: 10, MaxDuration: 10})},
+		Action: 'u',
+	}); err != nil {
+		t.Fatalf("browser_record_start failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`console.log('halfway'); 0`, nil))
+	time.Sleep(500 * time.Millisecond)
+
+	dir := t.TempDir()
+	stop := findTool(db.GetRecordTools(), "browser_record_stop")
+	res, err := stop.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: stop.Name, Arguments: encodeArgs(&devbrowser.RecordStopArgs{Dir: dir, Name: "anim", Encode: "gif"})},
+		Action: 'c',
+	})
+	if err != nil {
+		t.Fatalf("browser_record_stop failed: %v", err)
+	}
+	report := resultText(res)
+	if !strings.Contains(report, "stopped by browser_record_stop") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+
+	// Chrome sends the next frame only once the previous one is acked: a
+	// second of repaints yields several frames only if the ack loop runs.
+	frames, _ := filepath.Glob(filepath.Join(dir, "anim", "frame-*.jpg"))
+	if len(frames) < 3 {
+		t.Fatalf("expected several frames, got %d:\n%s", len(frames), report)
+	}
+	if data, err := os.ReadFile(frames[0]); err != nil || !bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+		t.Errorf("expected %s to be a JPEG, err %v", frames[0], err)
+	}
+
+	timeline, err := os.ReadFile(filepath.Join(dir, "anim", "timeline.txt"))
+	if err != nil {
+		t.Fatalf("timeline.txt not written: %v", err)
+	}
+	for _, want := range []string{"frame 0001  frame-0001-", "[console.log] halfway"} {
+		if !strings.Contains(string(timeline), want) {
+			t.Errorf("expected %q in timeline.txt:\n%s", want, timeline)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "anim.gif"))
+	if err != nil {
+		t.Fatalf("animation not written: %v", err)
+	}
+	if g, err := gif.DecodeAll(bytes.NewReader(data)); err != nil || len(g.Image) != len(frames) {
+		t.Errorf("expected a GIF with one image per frame (%d), got err %v", len(frames), err)
+	}
+}
+
+func TestRecord_StoppedOnClose(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body><h1>Recording</h1></body></html>`)
+	}))
+	defer ts.Close()
+
+	logger, getLogs := logCapture()
+	db, _ := DefaultTestBrowser(logger)
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+		db.CloseBrowser()
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	start := findTool(db.GetRecordTools(), "browser_record_start")
+	if _, err := start.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: start.Name, Arguments: encodeArgs(&devbrowser.RecordStartArgs{MaxDuration: 60})},
+		Action: 'c',
+	}); err != nil {
+		db.CloseBrowser()
+		t.Fatalf("browser_record_start failed: %v", err)
+	}
+	if err := db.CloseBrowser(); err != nil {
+		t.Fatalf("CloseBrowser failed: %v", err)
+	}
+	if logs := strings.Join(getLogs(), "\n"); !strings.Contains(logs, "Recording stopped: browser closed") {
+		t.Errorf("expected the recording to be stopped on close, got logs:\n%s", logs)
+	}
+
+	stop := findTool(db.GetRecordTools(), "browser_record_stop")
+	_, err := stop.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: stop.Name, Arguments: encodeArgs(&devbrowser.RecordStopArgs{Dir: t.TempDir()})},
+		Action: 'c',
+	})
+	if err == nil || !strings.Contains(err.Error(), "no recording in progress") {
+		t.Errorf("expected the recording to be dropped on close, got: %v", err)
+	}
+}
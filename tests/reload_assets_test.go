@@ -0,0 +1,136 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func TestReloadAssets_InvalidKind(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetReloadTools(), "browser_reload_assets")
+	_, err := tool.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(&devbrowser.ReloadAssetsArgs{Kind: "js"})},
+		Action: 'u',
+	})
+	if err == nil || !strings.Contains(err.Error(), "unsupported asset kind") {
+		t.Errorf("expected an unsupported kind error, got: %v", err)
+	}
+	if _, err := db.ReloadAssets("fonts"); err == nil || !strings.Contains(err.Error(), "unsupported asset kind") {
+		t.Errorf("expected an unsupported kind error, got: %v", err)
+	}
+}
+
+func TestReloadAssets(t *testing.T) {
+	var mu sync.Mutex
+	color := "rgb(255, 0, 0)"
+	hits := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		c := color
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/app.css":
+			w.Header().Set("Content-Type", "text/css")
+			fmt.Fprint(w, `@import "/theme.css"; h1 { font-size: 20px; }`)
+		case "/theme.css":
+			w.Header().Set("Content-Type", "text/css")
+			fmt.Fprintf(w, "p { color: %s; }", c)
+		case "/logo.svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			fmt.Fprint(w, `<svg xmlns="http://www.w3.org/2000/svg" width="10" height="10"/>`)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<!DOCTYPE html><html><head><link rel="stylesheet" href="/app.css"></head>
+				<body><h1>Assets</h1><p id="p">text</p><img src="/logo.svg"><script>window.loads = (window.loads || 0) + 1;</script></body></html>`)
+		}
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	pColor := func() string {
+		t.Helper()
+		var c string
+		if err := chromedp.Run(db.Ctx, chromedp.Evaluate(`getComputedStyle(document.getElementById('p')).color`, &c)); err != nil {
+			t.Fatalf("failed to read the color: %v", err)
+		}
+		return c
+	}
+	if c := pColor(); c != "rgb(255, 0, 0)" {
+		t.Fatalf("expected the imported color, got %s", c)
+	}
+
+	// The imported sheet is reloaded through the CSS domain.
+	mu.Lock()
+	color = "rgb(0, 128, 0)"
+	mu.Unlock()
+	reloaded, err := db.ReloadStylesheet("theme.css")
+	if err != nil {
+		t.Fatalf("ReloadStylesheet failed: %v", err)
+	}
+	if len(reloaded) != 1 || reloaded[0] != ts.URL+"/theme.css" {
+		t.Errorf("expected theme.css to be reloaded, got %v", reloaded)
+	}
+	if c := pColor(); c != "rgb(0, 128, 0)" {
+		t.Errorf("expected the new color after the reload, got %s", c)
+	}
+
+	tool := findTool(db.GetReloadTools(), "browser_reload_assets")
+	call := func(args *devbrowser.ReloadAssetsArgs) string {
+		t.Helper()
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(args)},
+			Action: 'u',
+		})
+		if err != nil {
+			t.Fatalf("browser_reload_assets failed: %v", err)
+		}
+		return resultText(res)
+	}
+
+	if got := call(&devbrowser.ReloadAssetsArgs{Url: "/app.css"}); !strings.Contains(got, "Reloaded in place (1 stylesheets):\n  "+ts.URL+"/app.css") {
+		t.Errorf("expected app.css to be reloaded, got:\n%s", got)
+	}
+	if got := call(&devbrowser.ReloadAssetsArgs{Url: "missing.css"}); got != "No stylesheets matching missing.css on the page" {
+		t.Errorf("expected no match, got:\n%s", got)
+	}
+	mu.Lock()
+	before := hits["/logo.svg"]
+	mu.Unlock()
+	if got := call(&devbrowser.ReloadAssetsArgs{Kind: "images"}); !strings.Contains(got, ts.URL+"/logo.svg") {
+		t.Errorf("expected the logo to be reloaded, got:\n%s", got)
+	}
+	mu.Lock()
+	after := hits["/logo.svg"]
+	mu.Unlock()
+	if after != before+1 {
+		t.Errorf("expected the logo to be fetched again, got %d fetches", after-before)
+	}
+
+	var loads int
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`window.loads`, &loads))
+	if loads != 1 {
+		t.Errorf("expected no page reload, got %d loads", loads)
+	}
+}
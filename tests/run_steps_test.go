@@ -0,0 +1,126 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func TestRunSteps_InvalidArgs(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetStepTools(), "browser_run_steps")
+	cases := []struct {
+		steps   string
+		wantErr string
+	}{
+		{`{"click": "#a"}`, "invalid steps"},
+		{`[]`, "no steps"},
+		{`[{"click": "#a", "fill": {}}]`, "step 1: use one key"},
+		{`[{"click": "#a"}, {"hover": "#b"}]`, "step 2: unsupported step hover"},
+		{`[{"fill": "#a"}]`, "fill takes an object"},
+		{`[{"click": 3}]`, "click takes an object of arguments or a string"},
+		{`[{"navigate": "/"}, {"click": {"selectr": "#a"}}]`, "step 2: unknown argument selectr for click"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.wantErr, func(t *testing.T) {
+			_, err := tool.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(&devbrowser.RunStepsArgs{Steps: tc.steps})},
+				Action: 'u',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunSteps(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<input id="name">
+				<button onclick="document.getElementById('out').textContent = 'Hello ' + document.getElementById('name').value; fetch('/api/missing')">Greet</button>
+				<p id="out"></p>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+	db.InitializeNetworkCapture()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL), chromedp.WaitReady("body")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	tool := findTool(db.GetStepTools(), "browser_run_steps")
+	run := func(steps string) *mcp.Result {
+		t.Helper()
+		res, err := tool.Execute(nil, mcp.Request{
+			Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(&devbrowser.RunStepsArgs{Steps: steps, Timeout: 2000})},
+			Action: 'u',
+		})
+		if err != nil {
+			t.Fatalf("run_steps failed: %v", err)
+		}
+		return res
+	}
+
+	res := run(`[
+		{"fill": {"selector": "#name", "value": "Ada"}},
+		{"click": "text=Greet"},
+		{"wait": {"text": "Hello Ada"}},
+		{"assert": {"selector": "#out"}},
+		{"evaluate": "document.title.length"},
+		{"screenshot": {}}
+	]`)
+	got := resultText(res)
+	if res.IsError {
+		t.Fatalf("expected every step to pass:\n%s", got)
+	}
+	for _, want := range []string{"Ran 6 steps", "1. ok      fill #name", "2. ok      click text=Greet", "4. ok      assert #out", "6. ok      screenshot", "Final URL: " + ts.URL} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if !strings.Contains(res.Content, `"type":"image"`) {
+		t.Errorf("expected the screenshot as an artifact, got: %.200s", res.Content)
+	}
+
+	res = run(`[
+		{"click": "text=Greet"},
+		{"assert": {"type": "text", "op": "contains", "expected": "Goodbye"}},
+		{"click": "#name"}
+	]`)
+	got = resultText(res)
+	if !res.IsError {
+		t.Errorf("expected the run to fail:\n%s", got)
+	}
+	for _, want := range []string{"Ran 2 of 3 steps", "step 2 (assert) failed", `2. failed  assert`, "3. skipped click #name", "Failed request: 404 GET " + ts.URL + "/api/missing"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func TestScreenshotAnnotated_Metadata(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetMCPTools(), "browser_screenshot_annotated")
+	if tool == nil {
+		t.Fatal("browser_screenshot_annotated tool is not registered")
+	}
+	if tool.Resource != "browser" || tool.Action != 'r' {
+		t.Errorf("Expected browser/'r', got %s/'%c'", tool.Resource, tool.Action)
+	}
+}
+
+func TestScreenshotAnnotated_Validation(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetScreenshotAnnotatedTools(), "browser_screenshot_annotated")
+
+	cases := []struct {
+		name    string
+		args    devbrowser.ScreenshotAnnotatedArgs
+		wantErr string
+	}{
+		{"blank lines only", devbrowser.ScreenshotAnnotatedArgs{Selectors: "\n  \n"}, "selectors required"},
+		{"bad format", devbrowser.ScreenshotAnnotatedArgs{Selectors: "#a\n.b", Format: "bmp"}, "unsupported format"},
+		{"browser closed", devbrowser.ScreenshotAnnotatedArgs{Selectors: "#a\n.b"}, "browser is not open"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := mcp.Request{
+				Params: mcp.CallToolParams{
+					Name:      "browser_screenshot_annotated",
+					Arguments: encodeArgs(&tc.args),
+				},
+				Action: 'r',
+			}
+			_, err := tool.Execute(nil, req)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestScreenshotAnnotated(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body style="margin:0">
+			<div id="a" style="position:absolute;left:10px;top:20px;width:100px;height:50px">A</div>
+			<div class="b" style="position:absolute;left:200px;top:30px;width:40px;height:40px">B1</div>
+			<div class="b" style="position:absolute;left:300px;top:40px;width:60px;height:20px">B2</div>
+		</body></html>`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	tool := findTool(db.GetScreenshotAnnotatedTools(), "browser_screenshot_annotated")
+	res, err := tool.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{
+			Name:      tool.Name,
+			Arguments: encodeArgs(&devbrowser.ScreenshotAnnotatedArgs{Selectors: "#a\n.b\n#missing"}),
+		},
+		Action: 'r',
+	})
+	if err != nil {
+		t.Fatalf("browser_screenshot_annotated failed: %v", err)
+	}
+
+	got := resultText(res)
+	for _, want := range []string{
+		"1. #a at x=10 y=20 w=100 h=50\n",
+		"2. .b (match 1/2) at x=200 y=30 w=40 h=40\n",
+		"3. .b (match 2/2) at x=300 y=40 w=60 h=20\n",
+		"-  #missing: no match\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the legend to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	if !strings.Contains(res.Content, `"type":"image"`) {
+		t.Error("expected an image block in the result")
+	}
+
+	var leftover bool
+	if err := chromedp.Run(db.Ctx, chromedp.Evaluate(`document.getElementById('__devbrowser_annotations') !== null`, &leftover)); err != nil {
+		t.Fatalf("failed to check the annotations: %v", err)
+	}
+	if leftover {
+		t.Error("expected #__devbrowser_annotations to be removed after the capture")
+	}
+}
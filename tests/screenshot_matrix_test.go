@@ -0,0 +1,130 @@
+package devbrowser_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/mcp"
+)
+
+func encodeTestPNG(t *testing.T, w, h int, c color.Color) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestContactSheet_GridLayout(t *testing.T) {
+	images := [][]byte{
+		encodeTestPNG(t, 200, 400, color.RGBA{255, 0, 0, 255}), // scaled to 100x200
+		encodeTestPNG(t, 100, 100, color.RGBA{0, 255, 0, 255}), // scaled to 100x100
+		encodeTestPNG(t, 300, 150, color.RGBA{0, 0, 255, 255}), // scaled to 100x50
+	}
+
+	sheet, err := devbrowser.ContactSheet(images, 2, 100)
+	if err != nil {
+		t.Fatalf("ContactSheet failed: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(sheet))
+	if err != nil {
+		t.Fatalf("contact sheet is not a PNG: %v", err)
+	}
+
+	// 2 columns of 100px plus 3 paddings of 16px; rows are 200 and 50 tall.
+	wantW := 2*100 + 3*16
+	wantH := 200 + 50 + 3*16
+	if img.Bounds().Dx() != wantW || img.Bounds().Dy() != wantH {
+		t.Errorf("expected %dx%d sheet, got %dx%d", wantW, wantH, img.Bounds().Dx(), img.Bounds().Dy())
+	}
+
+	// Second cell (green) starts after the first column.
+	r, g, b, _ := img.At(16+100+16+50, 16+50).RGBA()
+	if r != 0 || g>>8 != 255 || b != 0 {
+		t.Errorf("expected green pixel in the second cell, got %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestContactSheet_RejectsEmptyInput(t *testing.T) {
+	if _, err := devbrowser.ContactSheet(nil, 2, 100); err == nil {
+		t.Error("expected error for an empty image list")
+	}
+}
+
+func TestScreenshotMatrix_Metadata(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetMCPTools(), "browser_screenshot_matrix")
+	if tool == nil {
+		t.Fatal("browser_screenshot_matrix tool is not registered")
+	}
+	if tool.Resource != "browser_file" {
+		t.Errorf("Expected Resource 'browser_file', got '%s'", tool.Resource)
+	}
+	if tool.Action != 'c' {
+		t.Errorf("Expected Action 'c', got '%c'", tool.Action)
+	}
+}
+
+func TestScreenshotMatrix_UnknownDevice(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetScreenshotMatrixTools(), "browser_screenshot_matrix")
+	args := devbrowser.ScreenshotMatrixArgs{Targets: "mobile, Nokia 9000"}
+	req := mcp.Request{
+		Params: mcp.CallToolParams{
+			Name:      "browser_screenshot_matrix",
+			Arguments: encodeArgs(&args),
+		},
+		Action: 'c',
+	}
+
+	// Targets are validated before the browser state is checked.
+	_, err := tool.Execute(nil, req)
+	if err == nil {
+		t.Fatal("expected error for unknown device")
+	}
+	if !strings.Contains(err.Error(), "unsupported device: Nokia 9000") {
+		t.Errorf("expected unsupported device error, got: %v", err)
+	}
+}
+
+func TestScreenshotMatrix_DuplicateTargets(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetScreenshotMatrixTools(), "browser_screenshot_matrix")
+	cases := []struct {
+		targets string
+		wantErr string
+	}{
+		{"mobile, desktop, mobile", "duplicate target: mobile (already listed as mobile)"},
+		{"Pixel 5, pixel-5", "duplicate target: pixel-5 (already listed as Pixel 5)"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.targets, func(t *testing.T) {
+			_, err := tool.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(&devbrowser.ScreenshotMatrixArgs{Targets: tc.targets})},
+				Action: 'c',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package devbrowser_test
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func TestScreenshotOptions_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    devbrowser.ScreenshotOptions
+		wantErr string
+		ext     string
+		mime    string
+		quality int
+	}{
+		{name: "zero value is png", ext: ".png", mime: "image/png"},
+		{name: "jpg alias", opts: devbrowser.ScreenshotOptions{Format: "JPG"}, ext: ".jpg", mime: "image/jpeg", quality: 80},
+		{name: "webp quality kept", opts: devbrowser.ScreenshotOptions{Format: "webp", Quality: 55}, ext: ".webp", mime: "image/webp", quality: 55},
+		{name: "png drops quality", opts: devbrowser.ScreenshotOptions{Quality: 40}, ext: ".png", mime: "image/png"},
+		{name: "unknown format", opts: devbrowser.ScreenshotOptions{Format: "gif"}, wantErr: "unsupported format"},
+		{name: "quality range", opts: devbrowser.ScreenshotOptions{Format: "jpeg", Quality: 101}, wantErr: "quality must be between"},
+		{name: "scale range", opts: devbrowser.ScreenshotOptions{Scale: 0.01}, wantErr: "scale must be between"},
+		{name: "half clip", opts: devbrowser.ScreenshotOptions{ClipWidth: 100}, wantErr: "clip_width and clip_height"},
+		{name: "selector with clip", opts: devbrowser.ScreenshotOptions{Selector: "#a", ClipWidth: 10, ClipHeight: 10}, wantErr: "selector cannot be combined"},
+		{name: "transparent jpeg", opts: devbrowser.ScreenshotOptions{Format: "jpeg", OmitBackground: true}, wantErr: "omit_background needs png or webp"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := tc.opts
+			err := opts.Validate()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if opts.Extension() != tc.ext || opts.MimeType() != tc.mime {
+				t.Errorf("expected %s/%s, got %s/%s", tc.ext, tc.mime, opts.Extension(), opts.MimeType())
+			}
+			if opts.Quality != tc.quality {
+				t.Errorf("expected quality %d, got %d", tc.quality, opts.Quality)
+			}
+			if opts.Scale != 1 {
+				t.Errorf("expected default scale 1, got %g", opts.Scale)
+			}
+		})
+	}
+}
+
+func TestSaveScreenshot_ExtensionMustMatchFormat(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetScreenshotTools(), "browser_save_screenshot")
+	args := devbrowser.SaveScreenshotArgs{
+		Dir:    t.TempDir(),
+		Name:   "widget.png",
+		Format: "jpeg",
+	}
+	req := mcp.Request{
+		Params: mcp.CallToolParams{
+			Name:      "browser_save_screenshot",
+			Arguments: encodeArgs(&args),
+		},
+		Action: 'c',
+	}
+
+	// Options are validated before the browser state is checked.
+	_, err := tool.Execute(nil, req)
+	if err == nil || !strings.Contains(err.Error(), "name extension .png does not match format jpeg") {
+		t.Errorf("expected extension mismatch error, got: %v", err)
+	}
+}
+
+func TestScreenshot_SelectorWithFullpage(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetScreenshotTools(), "browser_screenshot")
+	_, err := tool.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(&devbrowser.ScreenshotArgs{Selector: "#card", Fullpage: true})},
+		Action: 'r',
+	})
+	if err == nil || !strings.Contains(err.Error(), "selector and fullpage are mutually exclusive") {
+		t.Errorf("expected a mutually exclusive error, got: %v", err)
+	}
+}
+
+func TestScreenshotOptions_Capture(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body style="margin:0">
+			<div style="position:absolute;left:0;top:0;width:100px;height:100px;background:rgb(255,0,0)"></div>
+		</body></html>`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+	var viewportWidth int
+	if err := chromedp.Run(db.Ctx, chromedp.Evaluate(`window.innerWidth`, &viewportWidth)); err != nil {
+		t.Fatalf("failed to read the viewport: %v", err)
+	}
+
+	capture := func(opts devbrowser.ScreenshotOptions) *devbrowser.ScreenshotResult {
+		t.Helper()
+		res, err := db.CaptureScreenshotWithOptions(opts)
+		if err != nil {
+			t.Fatalf("capture %+v failed: %v", opts, err)
+		}
+		return res
+	}
+	decodePNG := func(data []byte) image.Image {
+		t.Helper()
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("output is not a PNG: %v", err)
+		}
+		return img
+	}
+
+	jpg := capture(devbrowser.ScreenshotOptions{Format: "jpeg", Quality: 50})
+	if jpg.MimeType != "image/jpeg" || !bytes.HasPrefix(jpg.ImageData, []byte{0xFF, 0xD8, 0xFF}) {
+		t.Errorf("expected a JPEG, got %s starting % x", jpg.MimeType, jpg.ImageData[:4])
+	}
+	if cfg, err := jpeg.DecodeConfig(bytes.NewReader(jpg.ImageData)); err != nil || cfg.Width != viewportWidth {
+		t.Errorf("expected a %dpx wide JPEG, got %+v, %v", viewportWidth, cfg, err)
+	}
+
+	webp := capture(devbrowser.ScreenshotOptions{Format: "webp"})
+	if webp.MimeType != "image/webp" || len(webp.ImageData) < 12 ||
+		string(webp.ImageData[:4]) != "RIFF" || string(webp.ImageData[8:12]) != "WEBP" {
+		t.Errorf("expected a WebP, got %s starting %q", webp.MimeType, webp.ImageData[:min(len(webp.ImageData), 12)])
+	}
+
+	clip := decodePNG(capture(devbrowser.ScreenshotOptions{ClipX: 50, ClipY: 20, ClipWidth: 120, ClipHeight: 80}).ImageData)
+	if b := clip.Bounds(); b.Dx() != 120 || b.Dy() != 80 {
+		t.Errorf("expected a 120x80 clip, got %dx%d", b.Dx(), b.Dy())
+	}
+	// The clip starts at (50, 20): the red box fills its left half.
+	if r, g, b, _ := clip.At(10, 10).RGBA(); r>>8 != 255 || g>>8 != 0 || b>>8 != 0 {
+		t.Errorf("expected the red box at the clip's top left, got rgb(%d, %d, %d)", r>>8, g>>8, b>>8)
+	}
+
+	scaled := decodePNG(capture(devbrowser.ScreenshotOptions{ClipWidth: 200, ClipHeight: 100, Scale: 0.5}).ImageData)
+	if b := scaled.Bounds(); b.Dx() != 100 || b.Dy() != 50 {
+		t.Errorf("expected scale 0.5 to give 100x50, got %dx%d", b.Dx(), b.Dy())
+	}
+	if cfg, err := png.DecodeConfig(bytes.NewReader(capture(devbrowser.ScreenshotOptions{Scale: 0.5}).ImageData)); err != nil || cfg.Width != viewportWidth/2 {
+		t.Errorf("expected a %dpx wide viewport at scale 0.5, got %+v, %v", viewportWidth/2, cfg, err)
+	}
+
+	transparent := decodePNG(capture(devbrowser.ScreenshotOptions{ClipWidth: 200, ClipHeight: 200, OmitBackground: true}).ImageData)
+	if _, _, _, a := transparent.At(150, 150).RGBA(); a != 0 {
+		t.Errorf("expected the background to be transparent, got alpha %d", a>>8)
+	}
+	if _, _, _, a := transparent.At(50, 50).RGBA(); a>>8 != 255 {
+		t.Errorf("expected the red box to stay opaque, got alpha %d", a>>8)
+	}
+	opaque := decodePNG(capture(devbrowser.ScreenshotOptions{ClipWidth: 200, ClipHeight: 200}).ImageData)
+	if _, _, _, a := opaque.At(150, 150).RGBA(); a>>8 != 255 {
+		t.Errorf("expected the default background to be opaque, got alpha %d", a>>8)
+	}
+}
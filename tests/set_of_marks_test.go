@@ -0,0 +1,188 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/json"
+	"github.com/tinywasm/mcp"
+)
+
+func TestElementRef_Validation(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	tools := db.GetInteractionTools()
+	click := findTool(tools, "browser_click_element")
+	fill := findTool(tools, "browser_fill_element")
+
+	cases := []struct {
+		name    string
+		tool    *mcp.Tool
+		args    string
+		wantErr string
+	}{
+		{"click without target", click, encodeArgs(&devbrowser.ClickElementArgs{}), "selector or ref required"},
+		{"click with both", click, encodeArgs(&devbrowser.ClickElementArgs{Selector: "#a", Ref: 1}), "either selector or ref"},
+		{"click before snapshot", click, encodeArgs(&devbrowser.ClickElementArgs{Ref: 3}), "no marks snapshot"},
+		{"fill before snapshot", fill, encodeArgs(&devbrowser.FillElementArgs{Ref: 2, Value: "x"}), "no marks snapshot"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := mcp.Request{
+				Params: mcp.CallToolParams{Name: tc.tool.Name, Arguments: tc.args},
+				Action: 'u',
+			}
+			_, err := tc.tool.Execute(nil, req)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSetOfMarks_ClickAndFillByRef(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<input id="q" placeholder="Search">
+				<button id="go" onclick="this.innerText='Clicked ' + document.getElementById('q').value">Go</button>
+				<button style="display:none">Hidden</button>
+				<div class="card" style="cursor:pointer"><span>Card title</span></div>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser(func(msg ...any) {
+		t.Log(msg...)
+	})
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	shot := findTool(db.GetScreenshotTools(), "browser_screenshot")
+	res, err := shot.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{
+			Name:      "browser_screenshot",
+			Arguments: encodeArgs(&devbrowser.ScreenshotArgs{Marks: true}),
+		},
+		Action: 'r',
+	})
+	if err != nil {
+		t.Fatalf("marks screenshot failed: %v", err)
+	}
+
+	var contents mcp.TextContentList
+	if err := json.Decode(string(res.Content), &contents); err != nil {
+		t.Fatal(err)
+	}
+	table := contents[0].Text
+	for _, want := range []string{`[1] textbox "Search" -> #q`, `[2] button "Go" -> #go`, `[3] clickable "Card title"`} {
+		if !strings.Contains(table, want) {
+			t.Errorf("expected %q in marks table, got:\n%s", want, table)
+		}
+	}
+	if strings.Contains(table, "Hidden") || strings.Contains(table, "[4]") {
+		t.Errorf("hidden button or inherited-pointer span must not be marked:\n%s", table)
+	}
+
+	var overlays int
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`document.querySelectorAll('#__devbrowser_marks').length`, &overlays))
+	if overlays != 0 {
+		t.Error("marks overlay was not removed after the capture")
+	}
+
+	tools := db.GetInteractionTools()
+	fill := findTool(tools, "browser_fill_element")
+	if _, err := fill.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{
+			Name:      "browser_fill_element",
+			Arguments: encodeArgs(&devbrowser.FillElementArgs{Ref: 1, Value: "shoes"}),
+		},
+		Action: 'u',
+	}); err != nil {
+		t.Fatalf("fill by ref failed: %v", err)
+	}
+
+	click := findTool(tools, "browser_click_element")
+	if _, err := click.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{
+			Name:      "browser_click_element",
+			Arguments: encodeArgs(&devbrowser.ClickElementArgs{Ref: 2}),
+		},
+		Action: 'u',
+	}); err != nil {
+		t.Fatalf("click by ref failed: %v", err)
+	}
+
+	var text string
+	if err := chromedp.Run(db.Ctx, chromedp.Text("#go", &text)); err != nil {
+		t.Fatal(err)
+	}
+	if text != "Clicked shoes" {
+		t.Errorf("expected 'Clicked shoes', got %q", text)
+	}
+}
+
+func TestSetOfMarks_DroppedOnClose(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body><button id="go">Go</button></body></html>`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	open := func() {
+		t.Helper()
+		if err := db.CreateBrowserContext(); err != nil {
+			t.Fatalf("failed to create browser context: %v", err)
+		}
+		db.IsOpenFlag = true
+		if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL)); err != nil {
+			db.CloseBrowser()
+			t.Fatalf("failed to navigate: %v", err)
+		}
+	}
+
+	open()
+	shot := findTool(db.GetScreenshotTools(), "browser_screenshot")
+	if _, err := shot.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: shot.Name, Arguments: encodeArgs(&devbrowser.ScreenshotArgs{Marks: true})},
+		Action: 'r',
+	}); err != nil {
+		db.CloseBrowser()
+		t.Fatalf("marks screenshot failed: %v", err)
+	}
+	if err := db.CloseBrowser(); err != nil {
+		t.Fatalf("CloseBrowser failed: %v", err)
+	}
+
+	// Refs of the old session must not resolve in the new one.
+	open()
+	defer db.CloseBrowser()
+	click := findTool(db.GetInteractionTools(), "browser_click_element")
+	_, err := click.Execute(nil, mcp.Request{
+		Params: mcp.CallToolParams{Name: click.Name, Arguments: encodeArgs(&devbrowser.ClickElementArgs{Ref: 1})},
+		Action: 'u',
+	})
+	if err == nil || !strings.Contains(err.Error(), "no marks snapshot") {
+		t.Errorf("expected the marks snapshot to be dropped on close, got: %v", err)
+	}
+}
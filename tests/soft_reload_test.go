@@ -0,0 +1,140 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+func TestReloadForFile_PendingWhileOpening(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatal(err)
+	}
+	defer db.CloseBrowser()
+	db.SetSoftReload(true)
+	db.IsOpenFlag = true
+
+	// A CSS change before the browser is ready is a plain pending reload.
+	if err := db.ReloadForFile("web/style.css"); err != nil {
+		t.Fatalf("ReloadForFile returned unexpected error: %v", err)
+	}
+	if !db.IsPendingReload() {
+		t.Error("expected a pending reload after ReloadForFile while opening")
+	}
+}
+
+func TestSoftReload(t *testing.T) {
+	var cssLoads atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/style.css" {
+			cssLoads.Add(1)
+			w.Header().Set("Content-Type", "text/css")
+			fmt.Fprint(w, "h1 { color: rgb(0, 0, 255); }")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<head><link rel="stylesheet" href="/style.css"></head>
+			<body style="height: 5000px">
+				<h1>Soft</h1>
+				<input id="email" value="">
+				<input type="checkbox" name="agree">
+				<select id="size"><option>S</option><option>M</option></select>
+				<p id="cart"></p>
+				<script>
+					window.loads = (window.loads || 0) + 1;
+					document.getElementById('cart').textContent = sessionStorage.getItem('cart') || 'empty';
+					addEventListener('pagehide', () => sessionStorage.removeItem('cart'));
+				</script>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	var logs []string
+	var mu sync.Mutex
+	logger := func(msg ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, fmt.Sprint(msg...))
+	}
+
+	db, _ := DefaultTestBrowser(logger)
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+	db.InitializeNetworkCapture()
+	db.SetSoftReload(true, "cart")
+
+	if err := chromedp.Run(db.Ctx,
+		chromedp.Navigate(ts.URL),
+		chromedp.SendKeys("#email", "ada@example.com"),
+		chromedp.Click(`input[name="agree"]`),
+		chromedp.SetValue("#size", "M"),
+		chromedp.Evaluate(`sessionStorage.setItem('cart', '3 items'); scrollTo(0, 1200); document.getElementById('email').focus(); 0`, nil),
+	); err != nil {
+		t.Fatalf("failed to set up the page: %v", err)
+	}
+
+	report, err := db.SoftReload()
+	if err != nil {
+		t.Fatalf("SoftReload failed: %v", err)
+	}
+	got := report.String()
+	for _, want := range []string{"scroll 0,1200", "3 form fields", "focus #email", "sessionStorage.cart"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the report to contain %q, got: %s", want, got)
+		}
+	}
+
+	var state struct {
+		Loads   int    `json:"loads"`
+		Email   string `json:"email"`
+		Agree   bool   `json:"agree"`
+		Size    string `json:"size"`
+		Cart    string `json:"cart"`
+		Focused string `json:"focused"`
+	}
+	if err := chromedp.Run(db.Ctx, chromedp.Evaluate(`({
+		loads: window.loads,
+		email: document.getElementById('email').value,
+		agree: document.querySelector('input[name="agree"]').checked,
+		size: document.getElementById('size').value,
+		cart: document.getElementById('cart').textContent,
+		focused: document.activeElement.id,
+	})`, &state)); err != nil {
+		t.Fatalf("failed to read the page: %v", err)
+	}
+	if state.Email != "ada@example.com" || !state.Agree || state.Size != "M" || state.Cart != "3 items" || state.Focused != "email" {
+		t.Errorf("page state not restored: %+v", state)
+	}
+
+	// A CSS change swaps the stylesheet without reloading the page.
+	db.SetReadyForTest(true)
+	before := cssLoads.Load()
+	if err := db.ReloadForFile("web/style.css"); err != nil {
+		t.Fatalf("ReloadForFile failed: %v", err)
+	}
+	var loads int
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`window.loads`, &loads))
+	if loads != 1 || cssLoads.Load() != before+1 {
+		t.Errorf("expected a CSS hot-swap without a reload, got %d page loads and %d stylesheet loads", loads, cssLoads.Load()-before)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !strings.Contains(strings.Join(logs, "\n"), "Hot-swap for style.css: "+ts.URL+"/style.css reloaded in place") {
+		t.Errorf("expected a hot-swap log, got: %v", logs)
+	}
+}
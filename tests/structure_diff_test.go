@@ -0,0 +1,59 @@
+package devbrowser_test
+
+import (
+	"testing"
+
+	"github.com/tinywasm/devbrowser"
+)
+
+func TestDiffStructure(t *testing.T) {
+	before := `<body>
+  <div id="app">
+    <ul class="list">
+      <li> One
+      <li> Two
+    <button class="btn" [clickable] ref:e3> Save
+    <p> Loading...
+`
+	after := `<body>
+  <div id="app">
+    <ul class="list">
+      <li> Zero
+        <a href="/zero" [clickable] ref:e9> open
+      <li> One
+      <li> Two
+    <button class="btn" disabled [clickable] ref:e3> Saving
+    <div class="toast" [position:fixed]> Saved
+`
+	got := devbrowser.DiffStructure(before, after)
+	// The inserted <li> pairs with nothing, so the rows after it are not
+	// reported as changed.
+	want := `2 added, 1 removed, 1 changed
+
++ in body > div#app > ul.list:
+    <li> Zero
+      <a href="/zero" [clickable] ref:e9> open
+
+~ in body > div#app:
+    - <button class="btn" [clickable] ref:e3> Save
+    + <button class="btn" disabled [clickable] ref:e3> Saving
+
+- in body > div#app:
+    <p> Loading...
+
++ in body > div#app:
+    <div class="toast" [position:fixed]> Saved
+`
+	if got != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffStructure_NoChanges(t *testing.T) {
+	before := "<body>\n  <button [clickable] ref:e1> Go\n"
+	// Gaining or losing a ref is not a DOM change.
+	after := "<body>\n  <button [clickable]> Go\n"
+	if got := devbrowser.DiffStructure(before, after); got != "" {
+		t.Errorf("expected no changes, got:\n%s", got)
+	}
+}
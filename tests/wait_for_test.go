@@ -0,0 +1,139 @@
+package devbrowser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tinywasm/devbrowser"
+	"github.com/tinywasm/devbrowser/chromedp"
+	"github.com/tinywasm/mcp"
+)
+
+func TestWaitFor_InvalidArgs(t *testing.T) {
+	db, _ := DefaultTestBrowser()
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+
+	tool := findTool(db.GetWaitTools(), "browser_wait_for")
+	cases := []struct {
+		args    *devbrowser.WaitForArgs
+		wantErr string
+	}{
+		{&devbrowser.WaitForArgs{}, "set exactly one condition"},
+		{&devbrowser.WaitForArgs{Selector: "#a", Url: "/done"}, "set exactly one condition"},
+		{&devbrowser.WaitForArgs{Selector: "#a", State: "gone"}, "unsupported state"},
+		{&devbrowser.WaitForArgs{Text: "Saved", State: "hidden"}, "state applies to selector alone"},
+		{&devbrowser.WaitForArgs{Url: "/[a-/"}, "invalid url regexp"},
+		{&devbrowser.WaitForArgs{Selector: "text="}, "invalid locator"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.wantErr, func(t *testing.T) {
+			_, err := tool.Execute(nil, mcp.Request{
+				Params: mcp.CallToolParams{Name: tool.Name, Arguments: encodeArgs(tc.args)},
+				Action: 'r',
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(400 * time.Millisecond)
+			fmt.Fprint(w, "ok")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+			<!DOCTYPE html>
+			<html>
+			<body>
+				<div id="spinner">Loading</div>
+				<script>
+					setTimeout(() => {
+						document.getElementById('spinner').remove();
+						const p = document.createElement('p');
+						p.id = 'done';
+						p.textContent = 'Saved!';
+						document.body.appendChild(p);
+						history.pushState({}, '', '/done');
+						window.ready = 42;
+					}, 300);
+				</script>
+			</body>
+			</html>
+		`)
+	}))
+	defer ts.Close()
+
+	db, _ := DefaultTestBrowser()
+	if err := db.CreateBrowserContext(); err != nil {
+		t.Fatalf("failed to create browser context: %v", err)
+	}
+	db.IsOpenFlag = true
+	defer db.CloseBrowser()
+	db.InitializeNetworkCapture()
+
+	if err := chromedp.Run(db.Ctx, chromedp.Navigate(ts.URL), chromedp.WaitReady("body")); err != nil {
+		t.Fatalf("failed to navigate: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		cond devbrowser.WaitCondition
+		want string
+	}{
+		{"visible", devbrowser.WaitCondition{Selector: "#done"}, "visible"},
+		{"detached", devbrowser.WaitCondition{Selector: "#spinner", State: "detached"}, "not in the page"},
+		{"text", devbrowser.WaitCondition{Text: "Saved!"}, "text found"},
+		{"url", devbrowser.WaitCondition{URL: "/done$/"}, "/done"},
+		{"js", devbrowser.WaitCondition{JS: "window.ready"}, "value 42"},
+		{"dom stable", devbrowser.WaitCondition{DOMStable: 200 * time.Millisecond}, "last DOM mutation"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := db.WaitFor(tc.cond)
+			if err != nil {
+				t.Fatalf("WaitFor failed: %v", err)
+			}
+			if !strings.Contains(got, tc.want) {
+				t.Errorf("expected %q in %q", tc.want, got)
+			}
+		})
+	}
+
+	// A fetch keeps the network busy until it completes.
+	chromedp.Run(db.Ctx, chromedp.Evaluate(`fetch('/slow'); 0`, nil))
+	start := time.Now()
+	if _, err := db.WaitFor(devbrowser.WaitCondition{NetworkIdle: 100 * time.Millisecond}); err != nil {
+		t.Fatalf("network idle wait failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("expected to wait for the slow request, returned after %v", elapsed)
+	}
+
+	_, err := db.WaitFor(devbrowser.WaitCondition{Selector: "#never", Timeout: 300 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "timed out after 300ms") || !strings.Contains(err.Error(), "last observed: not in the page") {
+		t.Errorf("expected a timeout reporting the last observation, got: %v", err)
+	}
+	// Only a missing element meets detached; a failed read keeps polling.
+	_, err = db.WaitFor(devbrowser.WaitCondition{Selector: "body >>> p", State: "detached", Timeout: 300 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "timed out after 300ms") || !strings.Contains(err.Error(), "neither an iframe nor a shadow host") {
+		t.Errorf("expected a failed read not to count as detached, got: %v", err)
+	}
+	_, err = db.WaitFor(devbrowser.WaitCondition{JS: "window.ready > 100", Timeout: 300 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "last observed: value false") {
+		t.Errorf("expected a JS timeout reporting the last value, got: %v", err)
+	}
+	_, err = db.WaitFor(devbrowser.WaitCondition{Selector: "div[", Timeout: 300 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "invalid selector") {
+		t.Errorf("expected an invalid selector error, got: %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package devbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tinywasm/devbrowser/chromedp"
+)
+
+// waitPollInterval is how often WaitFor checks its condition.
+const waitPollInterval = 100 * time.Millisecond
+
+// waitDefaultTimeout is the timeout of a WaitCondition that sets none.
+const waitDefaultTimeout = 5 * time.Second
+
+// WaitCondition is what WaitFor waits for. Set one of Selector (with
+// State), Text (optionally inside Selector), URL, NetworkIdle, DOMStable or
+// JS.
+type WaitCondition struct {
+	Selector    string        // CSS selector, ref, locator or selector path
+	State       string        // Of Selector: visible (default), hidden, attached or detached
+	Text        string        // Text present in the page, or in Selector
+	URL         string        // Substring of the page URL, or a /regexp/
+	NetworkIdle time.Duration // No request in flight for this long
+	DOMStable   time.Duration // No DOM mutation for this long
+	JS          string        // JS expression polled until it is truthy
+	Timeout     time.Duration // Default 5s
+}
+
+// elementStateJS reports whether an element is visible: it has a box and
+// is not hidden by visibility.
+const elementStateJS = `function() {
+	const r = this.getBoundingClientRect();
+	const style = this.ownerDocument.defaultView.getComputedStyle(this);
+	return {visible: r.width > 0 && r.height > 0 && style.visibility !== 'hidden',
+		box: Math.round(r.width) + 'x' + Math.round(r.height) + ' at ' + Math.round(r.x) + ',' + Math.round(r.y)};
+}`
+
+// domStableJS installs a MutationObserver on the document, once per page,
+// and returns the milliseconds since the last mutation it saw.
+const domStableJS = `(() => {
+	if (!window.__devbrowserMutationObserver) {
+		window.__devbrowserLastMutation = performance.now();
+		window.__devbrowserMutationObserver = new MutationObserver(() => { window.__devbrowserLastMutation = performance.now(); });
+		window.__devbrowserMutationObserver.observe(document, {subtree: true, childList: true, attributes: true, characterData: true});
+	}
+	return performance.now() - window.__devbrowserLastMutation;
+})()`
+
+// String describes the condition for reports.
+func (c WaitCondition) String() string {
+	switch {
+	case c.Text != "" && c.Selector != "":
+		return fmt.Sprintf("text %q in %s", c.Text, c.Selector)
+	case c.Text != "":
+		return fmt.Sprintf("text %q", c.Text)
+	case c.Selector != "":
+		return fmt.Sprintf("%s %s", c.Selector, c.state())
+	case c.URL != "":
+		return fmt.Sprintf("URL matching %s", c.URL)
+	case c.NetworkIdle > 0:
+		return fmt.Sprintf("network idle for %dms", c.NetworkIdle.Milliseconds())
+	case c.DOMStable > 0:
+		return fmt.Sprintf("DOM stable for %dms", c.DOMStable.Milliseconds())
+	}
+	return fmt.Sprintf("JS %s to be truthy", c.JS)
+}
+
+func (c WaitCondition) state() string {
+	if c.State == "" {
+		return "visible"
+	}
+	return c.State
+}
+
+// validate checks that exactly one condition is set and well formed.
+func (c WaitCondition) validate() error {
+	set := 0
+	for _, on := range []bool{c.Selector != "" || c.Text != "", c.URL != "", c.NetworkIdle != 0, c.DOMStable != 0, c.JS != ""} {
+		if on {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("set exactly one condition: selector (with state), text, url, network_idle, dom_stable or js")
+	}
+	switch c.State {
+	case "", "visible", "hidden", "attached", "detached":
+	default:
+		return fmt.Errorf("unsupported state: %s. Use visible, hidden, attached or detached", c.State)
+	}
+	if c.State != "" && c.Text != "" {
+		return fmt.Errorf("state applies to selector alone, not to text")
+	}
+	if c.NetworkIdle < 0 || c.DOMStable < 0 || c.Timeout < 0 {
+		return fmt.Errorf("network_idle, dom_stable and timeout must be positive")
+	}
+	if _, err := c.urlPattern(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// urlPattern compiles a /regexp/ URL condition; nil means substring.
+func (c WaitCondition) urlPattern() (*regexp.Regexp, error) {
+	if len(c.URL) < 2 || !strings.HasPrefix(c.URL, "/") || !strings.HasSuffix(c.URL, "/") {
+		return nil, nil
+	}
+	re, err := regexp.Compile(c.URL[1 : len(c.URL)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid url regexp %s: %v", c.URL, err)
+	}
+	return re, nil
+}
+
+// WaitFor waits until c holds, polling the page, and describes what it saw
+// then. On timeout the error reports what was last observed.
+func (b *DevBrowser) WaitFor(c WaitCondition) (string, error) {
+	if b.Ctx == nil {
+		return "", errors.New("context not initialized")
+	}
+	if err := c.validate(); err != nil {
+		return "", err
+	}
+	if c.Selector != "" {
+		if err := b.checkSelector(c.Selector); err != nil {
+			return "", err
+		}
+	}
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = waitDefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(b.Ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	if c.JS != "" {
+		return b.waitJS(ctx, c, start)
+	}
+
+	var last string
+	for {
+		met, observed, err := b.observe(ctx, c)
+		if err != nil {
+			return "", err
+		}
+		last = observed
+		if met {
+			return fmt.Sprintf("%s after %dms: %s", c, time.Since(start).Milliseconds(), observed), nil
+		}
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("timed out after %dms waiting for %s; last observed: %s", timeout.Milliseconds(), c, last)
+		case <-time.After(waitPollInterval):
+		}
+	}
+}
+
+// observe checks c once. Failures to read the page (it may be navigating)
+// are observations; err is only for conditions that can never hold.
+func (b *DevBrowser) observe(ctx context.Context, c WaitCondition) (met bool, observed string, err error) {
+	switch {
+	case c.Text != "":
+		var text string
+		if c.Selector != "" {
+			err = b.callOnElement(ctx, c.Selector, `function() { return this.innerText || this.textContent || ''; }`, &text)
+		} else {
+			err = chromedp.Run(ctx, chromedp.Evaluate(`document.body ? document.body.innerText : ''`, &text))
+		}
+		if err != nil {
+			return false, err.Error(), nil
+		}
+		if strings.Contains(text, c.Text) {
+			return true, "text found", nil
+		}
+		return false, fmt.Sprintf("text not in %d characters of visible text", len([]rune(text))), nil
+
+	case c.Selector != "":
+		return b.observeSelector(ctx, c)
+
+	case c.URL != "":
+		var url string
+		if err := chromedp.Run(ctx, chromedp.Location(&url)); err != nil {
+			return false, err.Error(), nil
+		}
+		re, _ := c.urlPattern()
+		if re != nil {
+			return re.MatchString(url), "URL " + url, nil
+		}
+		return strings.Contains(url, c.URL), "URL " + url, nil
+
+	case c.NetworkIdle > 0:
+		urls, quiet := b.networkState()
+		if len(urls) > 0 {
+			shown := urls[:min(len(urls), 3)]
+			more := ""
+			if len(urls) > len(shown) {
+				more = fmt.Sprintf(" and %d more", len(urls)-len(shown))
+			}
+			return false, fmt.Sprintf("%d request(s) in flight: %s%s", len(urls), strings.Join(shown, ", "), more), nil
+		}
+		return quiet >= c.NetworkIdle, fmt.Sprintf("no request in flight for %dms", quiet.Milliseconds()), nil
+	}
+
+	var quiet float64
+	if err := chromedp.Run(ctx, chromedp.Evaluate(domStableJS, &quiet)); err != nil {
+		return false, err.Error(), nil
+	}
+	return quiet >= float64(c.DOMStable.Milliseconds()), fmt.Sprintf("last DOM mutation %dms ago", int64(quiet)), nil
+}
+
+// observeSelector checks the state of the element of c.Selector.
+func (b *DevBrowser) observeSelector(ctx context.Context, c WaitCondition) (bool, string, error) {
+	// A syntax error would otherwise read as "not found" until the timeout.
+	if err := checkCSSSyntax(ctx, c.Selector); err != nil {
+		return false, "", err
+	}
+
+	var st struct {
+		Visible bool   `json:"visible"`
+		Box     string `json:"box"`
+	}
+	if err := b.callOnElement(ctx, c.Selector, elementStateJS, &st); err != nil {
+		if isElementMissing(err) {
+			return c.state() == "detached" || c.state() == "hidden", "not in the page (" + err.Error() + ")", nil
+		}
+		// A read that failed, such as during a navigation, says nothing
+		// about the element: keep polling.
+		return false, err.Error(), nil
+	}
+	observed := "attached, hidden"
+	if st.Visible {
+		observed = "visible, " + st.Box
+	}
+	switch c.state() {
+	case "attached":
+		return true, observed, nil
+	case "detached":
+		return false, observed, nil
+	case "hidden":
+		return !st.Visible, observed, nil
+	}
+	return st.Visible, observed, nil
+}
+
+// waitJS polls the JS condition of c with chromedp.Poll. On timeout it
+// evaluates the expression once more to report its last value.
+func (b *DevBrowser) waitJS(ctx context.Context, c WaitCondition, start time.Time) (string, error) {
+	deadline, _ := ctx.Deadline()
+	var res any
+	err := chromedp.Run(ctx, chromedp.Poll(c.JS, &res,
+		chromedp.WithPollingInterval(waitPollInterval),
+		chromedp.WithPollingTimeout(time.Until(deadline)),
+	))
+	if err == nil {
+		value, _ := json.Marshal(res)
+		return fmt.Sprintf("%s after %dms: value %s", c, time.Since(start).Milliseconds(), value), nil
+	}
+	if !errors.Is(err, chromedp.ErrPollingTimeout) && ctx.Err() == nil {
+		return "", fmt.Errorf("Error evaluating %s: %v", c.JS, err)
+	}
+
+	last := "unknown"
+	lctx, cancel := context.WithTimeout(b.Ctx, time.Second)
+	defer cancel()
+	var value any
+	if err := chromedp.Run(lctx, chromedp.Evaluate(c.JS, &value)); err != nil {
+		last = err.Error()
+	} else {
+		v, _ := json.Marshal(value)
+		last = "value " + string(v)
+	}
+	return "", fmt.Errorf("timed out after %dms waiting for %s; last observed: %s", time.Since(start).Milliseconds(), c, last)
+}